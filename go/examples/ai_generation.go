@@ -214,7 +214,7 @@ func completeSongExample(ctx context.Context, client *jewelmusic.Client) error {
 func getTemplatesExample(ctx context.Context, client *jewelmusic.Client) error {
 	fmt.Println("\n📚 Getting available song templates...")
 
-	templates, err := client.Copilot.GetTemplates(ctx, &jewelmusic.TemplateQuery{
+	templates, err := client.Copilot.ListTemplates(ctx, &jewelmusic.TemplateQuery{
 		Genre:    "electronic",
 		Mood:     "upbeat",
 		Duration: 180,
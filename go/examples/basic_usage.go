@@ -72,7 +72,7 @@ func basicUsageExample(ctx context.Context, client *jewelmusic.Client) error {
 
 func handleMissingAudioFile(ctx context.Context, client *jewelmusic.Client) error {
 	fmt.Println("📋 Listing existing tracks...")
-	tracks, err := client.Tracks.List(ctx, &jewelmusic.ListOptions{
+	tracks, err := client.Tracks.ListWithOptions(ctx, jewelmusic.ListOptions{
 		Page:    1,
 		PerPage: 5,
 	})
@@ -262,7 +262,7 @@ func contextCancellationExample() {
 	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Millisecond) // Very short timeout
 	defer cancel()
 
-	_, err := client.Tracks.List(ctx, &jewelmusic.ListOptions{
+	_, err := client.Tracks.ListWithOptions(ctx, jewelmusic.ListOptions{
 		Page:    1,
 		PerPage: 20,
 	})
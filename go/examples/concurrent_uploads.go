@@ -182,7 +182,7 @@ func mockConcurrentUploads(ctx context.Context, client *jewelmusic.Client) error
 	fmt.Println("🎭 Running mock concurrent upload demonstration...")
 
 	// Get existing tracks to simulate upload operations
-	tracks, err := client.Tracks.List(ctx, &jewelmusic.ListOptions{
+	tracks, err := client.Tracks.ListWithOptions(ctx, jewelmusic.ListOptions{
 		Page:    1,
 		PerPage: 10,
 	})
@@ -259,7 +259,7 @@ func concurrentAnalysis(ctx context.Context, client *jewelmusic.Client) error {
 	fmt.Println("\n🔍 Concurrent analysis operations...")
 
 	// Get some tracks to analyze
-	tracks, err := client.Tracks.List(ctx, &jewelmusic.ListOptions{
+	tracks, err := client.Tracks.ListWithOptions(ctx, jewelmusic.ListOptions{
 		Page:    1,
 		PerPage: 5,
 	})
@@ -350,7 +350,7 @@ func batchMetadataUpdates(ctx context.Context, client *jewelmusic.Client) error
 	fmt.Println("\n📝 Batch metadata updates...")
 
 	// Get tracks to update
-	tracks, err := client.Tracks.List(ctx, &jewelmusic.ListOptions{
+	tracks, err := client.Tracks.ListWithOptions(ctx, jewelmusic.ListOptions{
 		Page:    1,
 		PerPage: 5,
 	})
@@ -5,13 +5,10 @@ import (
 	"crypto/rand"
 	"encoding/hex"
 	"fmt"
-	"io"
 	"log"
 	"net/http"
 	"os"
-	"os/signal"
 	"strconv"
-	"syscall"
 	"time"
 
 	"github.com/jewelmusic/sdk/go/jewelmusic"
@@ -70,7 +67,6 @@ type WebhookServer struct {
 	client *jewelmusic.Client
 	port   int
 	secret string
-	server *http.Server
 }
 
 func (ws *WebhookServer) setupWebhook(ctx context.Context) error {
@@ -78,16 +74,16 @@ func (ws *WebhookServer) setupWebhook(ctx context.Context) error {
 
 	// In a real scenario, you'd use your actual server URL
 	webhookURL := fmt.Sprintf("http://localhost:%d%s", ws.port, webhookPath)
-	
+
 	webhook, err := ws.client.Webhooks.Create(ctx, &jewelmusic.Webhook{
 		URL: webhookURL,
 		Events: []string{
-			"track.uploaded",
-			"track.processed",
-			"analysis.completed",
-			"transcription.completed",
-			"distribution.live",
-			"copilot.generation_completed",
+			jewelmusic.EventTrackUploaded,
+			jewelmusic.EventTrackProcessed,
+			jewelmusic.EventAnalysisCompleted,
+			jewelmusic.EventTranscriptionCompleted,
+			jewelmusic.EventDistributionLive,
+			jewelmusic.EventCopilotGenerationCompleted,
 		},
 		Secret: ws.secret,
 	})
@@ -105,54 +101,54 @@ func (ws *WebhookServer) setupWebhook(ctx context.Context) error {
 }
 
 func (ws *WebhookServer) start() error {
+	// Register event handlers
+	webhooks := jewelmusic.NewWebhookHandler(ws.secret)
+	webhooks.On(jewelmusic.EventTrackUploaded, ws.wrap(ws.handleTrackUploaded))
+	webhooks.On(jewelmusic.EventTrackProcessed, ws.wrap(ws.handleTrackProcessed))
+	webhooks.On(jewelmusic.EventAnalysisCompleted, ws.wrap(ws.handleAnalysisCompleted))
+	webhooks.On(jewelmusic.EventTranscriptionCompleted, ws.wrap(ws.handleTranscriptionCompleted))
+	webhooks.On(jewelmusic.EventDistributionLive, ws.wrap(ws.handleDistributionLive))
+	webhooks.On(jewelmusic.EventCopilotGenerationCompleted, ws.wrap(ws.handleCopilotGenerationCompleted))
+	webhooks.OnUnhandled(ws.wrap(func(event *jewelmusic.WebhookEvent) error {
+		fmt.Printf("         ⚠️  Unknown event type: %s\n", event.Type)
+		return nil
+	}))
+
 	// Setup HTTP routes
 	mux := http.NewServeMux()
 	mux.HandleFunc("/", ws.handleRoot)
 	mux.HandleFunc("/health", ws.handleHealth)
-	mux.HandleFunc(webhookPath, ws.handleWebhook)
-
-	// Create server
-	ws.server = &http.Server{
-		Addr:         fmt.Sprintf(":%d", ws.port),
-		Handler:      mux,
-		ReadTimeout:  10 * time.Second,
-		WriteTimeout: 10 * time.Second,
-		IdleTimeout:  60 * time.Second,
-	}
+	mux.Handle(webhookPath, webhooks)
 
-	// Start server in goroutine
-	go func() {
-		fmt.Printf("\n🚀 Webhook server listening on http://localhost:%d\n", ws.port)
-		fmt.Printf("📍 Webhook endpoint: http://localhost:%d%s\n", ws.port, webhookPath)
-		fmt.Printf("🏥 Health check: http://localhost:%d/health\n", ws.port)
-		fmt.Println("\nPress Ctrl+C to stop the server")
+	fmt.Printf("\n🚀 Webhook server listening on http://localhost:%d\n", ws.port)
+	fmt.Printf("📍 Webhook endpoint: http://localhost:%d%s\n", ws.port, webhookPath)
+	fmt.Printf("🏥 Health check: http://localhost:%d/health\n", ws.port)
+	fmt.Println("\nPress Ctrl+C to stop the server")
 
-		if err := ws.server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			log.Printf("💥 Server error: %v", err)
-		}
-	}()
+	// Serve until interrupted, then shut down gracefully
+	if err := jewelmusic.RunWebhookServer(context.Background(), fmt.Sprintf(":%d", ws.port), mux); err != nil {
+		return fmt.Errorf("server error: %w", err)
+	}
 
-	// Wait for interrupt signal to gracefully shutdown
-	return ws.waitForShutdown()
+	fmt.Println("\n✅ Webhook server stopped gracefully")
+	return nil
 }
 
-func (ws *WebhookServer) waitForShutdown() error {
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	<-quit
-
-	fmt.Println("\n🛑 Shutting down webhook server...")
+// wrap adapts an event handler to the SDK handler signature, logging each event
+func (ws *WebhookServer) wrap(handle func(*jewelmusic.WebhookEvent) error) jewelmusic.WebhookEventHandler {
+	return func(ctx context.Context, event *jewelmusic.WebhookEvent) error {
+		timestamp := time.Now().Format("15:04:05")
 
-	// Graceful shutdown with timeout
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
+		fmt.Printf("\n[%s] 📨 Received webhook event: %s\n", timestamp, event.Type)
+		fmt.Printf("         Event ID: %s\n", event.ID)
+		fmt.Printf("         Timestamp: %s\n", event.Timestamp.Format(time.RFC3339))
 
-	if err := ws.server.Shutdown(ctx); err != nil {
-		return fmt.Errorf("server forced to shutdown: %w", err)
+		if err := handle(event); err != nil {
+			log.Printf("❌ Failed to process webhook event: %v", err)
+			return err
+		}
+		return nil
 	}
-
-	fmt.Println("✅ Webhook server stopped gracefully")
-	return nil
 }
 
 func (ws *WebhookServer) handleRoot(w http.ResponseWriter, r *http.Request) {
@@ -220,95 +216,17 @@ func (ws *WebhookServer) handleHealth(w http.ResponseWriter, r *http.Request) {
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
-	fmt.Fprintf(w, `{"status":"%s","timestamp":"%s","service":"%s","version":"%s"}`, 
+	fmt.Fprintf(w, `{"status":"%s","timestamp":"%s","service":"%s","version":"%s"}`,
 		response["status"], response["timestamp"], response["service"], response["version"])
 }
 
-func (ws *WebhookServer) handleWebhook(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
-	// Read the request body
-	body, err := io.ReadAll(r.Body)
-	if err != nil {
-		log.Printf("❌ Failed to read request body: %v", err)
-		http.Error(w, "Failed to read request body", http.StatusBadRequest)
-		return
-	}
-	defer r.Body.Close()
-
-	// Get the signature from headers
-	signature := r.Header.Get("X-JewelMusic-Signature")
-	if signature == "" {
-		log.Println("❌ Missing webhook signature")
-		http.Error(w, "Missing webhook signature", http.StatusBadRequest)
-		return
-	}
-
-	// Verify webhook signature
-	if !jewelmusic.VerifyWebhookSignature(body, signature, ws.secret) {
-		log.Println("❌ Invalid webhook signature")
-		http.Error(w, "Invalid webhook signature", http.StatusUnauthorized)
-		return
-	}
-
-	// Parse webhook event
-	event, err := jewelmusic.ParseWebhookEvent(body)
-	if err != nil {
-		log.Printf("❌ Failed to parse webhook event: %v", err)
-		http.Error(w, "Failed to parse webhook event", http.StatusBadRequest)
-		return
-	}
-
-	// Process the webhook event
-	if err := ws.processWebhookEvent(event); err != nil {
-		log.Printf("❌ Failed to process webhook event: %v", err)
-		http.Error(w, "Failed to process webhook event", http.StatusInternalServerError)
-		return
-	}
-
-	// Respond with success
-	w.WriteHeader(http.StatusOK)
-	fmt.Fprintf(w, `{"received": true, "event_type": "%s", "event_id": "%s"}`, 
-		event.Type, event.ID)
-}
-
-func (ws *WebhookServer) processWebhookEvent(event *jewelmusic.WebhookEvent) error {
-	timestamp := time.Now().Format("15:04:05")
-	
-	fmt.Printf("\n[%s] 📨 Received webhook event: %s\n", timestamp, event.Type)
-	fmt.Printf("         Event ID: %s\n", event.ID)
-	fmt.Printf("         Timestamp: %s\n", event.Timestamp.Format(time.RFC3339))
-
-	// Handle different event types
-	switch event.Type {
-	case "track.uploaded":
-		return ws.handleTrackUploaded(event)
-	case "track.processed":
-		return ws.handleTrackProcessed(event)
-	case "analysis.completed":
-		return ws.handleAnalysisCompleted(event)
-	case "transcription.completed":
-		return ws.handleTranscriptionCompleted(event)
-	case "distribution.live":
-		return ws.handleDistributionLive(event)
-	case "copilot.generation_completed":
-		return ws.handleCopilotGenerationCompleted(event)
-	default:
-		fmt.Printf("         ⚠️  Unknown event type: %s\n", event.Type)
-		return nil
-	}
-}
-
 func (ws *WebhookServer) handleTrackUploaded(event *jewelmusic.WebhookEvent) error {
 	track, ok := event.Data["track"].(map[string]interface{})
 	if !ok {
 		return fmt.Errorf("invalid track data in webhook")
 	}
 
-	fmt.Printf("         🎵 Track uploaded: '%s' by %s\n", 
+	fmt.Printf("         🎵 Track uploaded: '%s' by %s\n",
 		track["title"], track["artist"])
 	fmt.Printf("         📁 Track ID: %s\n", track["id"])
 	fmt.Printf("         ⏱️  Duration: %.0fs\n", track["duration"])
@@ -336,11 +254,11 @@ func (ws *WebhookServer) handleAnalysisCompleted(event *jewelmusic.WebhookEvent)
 	}
 
 	fmt.Printf("         🔍 Analysis completed: %s\n", analysis["id"])
-	
+
 	if trackID, exists := analysis["track_id"]; exists {
 		fmt.Printf("         🎵 Track ID: %s\n", trackID)
 	}
-	
+
 	if tempo, exists := analysis["tempo"]; exists {
 		if tempoMap, ok := tempo.(map[string]interface{}); ok {
 			if bpm, exists := tempoMap["bpm"]; exists {
@@ -348,7 +266,7 @@ func (ws *WebhookServer) handleAnalysisCompleted(event *jewelmusic.WebhookEvent)
 			}
 		}
 	}
-	
+
 	if key, exists := analysis["key"]; exists {
 		if keyMap, ok := key.(map[string]interface{}); ok {
 			if keyName, exists := keyMap["key"]; exists {
@@ -369,15 +287,15 @@ func (ws *WebhookServer) handleTranscriptionCompleted(event *jewelmusic.WebhookE
 	}
 
 	fmt.Printf("         📝 Transcription completed: %s\n", transcription["id"])
-	
+
 	if trackID, exists := transcription["track_id"]; exists {
 		fmt.Printf("         🎵 Track ID: %s\n", trackID)
 	}
-	
+
 	if language, exists := transcription["language"]; exists {
 		fmt.Printf("         🌐 Language: %s\n", language)
 	}
-	
+
 	if text, exists := transcription["text"]; exists {
 		if textStr, ok := text.(string); ok {
 			preview := textStr
@@ -400,7 +318,7 @@ func (ws *WebhookServer) handleDistributionLive(event *jewelmusic.WebhookEvent)
 	fmt.Printf("         🚀 Release is now live: '%s'\n", release["title"])
 	fmt.Printf("         📁 Release ID: %s\n", release["id"])
 	fmt.Printf("         🎤 Artist: %s\n", release["artist"])
-	
+
 	if platforms, exists := release["platforms"]; exists {
 		if platformList, ok := platforms.([]interface{}); ok {
 			fmt.Printf("         📱 Platforms: ")
@@ -424,15 +342,15 @@ func (ws *WebhookServer) handleCopilotGenerationCompleted(event *jewelmusic.Webh
 	}
 
 	fmt.Printf("         🤖 AI generation completed: %s\n", generation["id"])
-	
+
 	if genType, exists := generation["type"]; exists {
 		fmt.Printf("         🎯 Type: %s\n", genType)
 	}
-	
+
 	if title, exists := generation["title"]; exists {
 		fmt.Printf("         📝 Title: %s\n", title)
 	}
-	
+
 	if style, exists := generation["style"]; exists {
 		fmt.Printf("         🎨 Style: %s\n", style)
 	}
@@ -470,11 +388,22 @@ func exampleProgrammaticUsage() {
 	// Test a webhook
 	if len(webhooks.Items) > 0 {
 		webhook := webhooks.Items[0]
-		err := client.Webhooks.Test(ctx, webhook.ID)
+		result, err := client.Webhooks.Test(ctx, webhook.ID, jewelmusic.EventWebhookTest)
 		if err != nil {
 			log.Printf("Failed to test webhook: %v", err)
+		} else if !result.Delivered {
+			log.Printf("Webhook test to %s failed: %s", webhook.URL, result.Error)
 		} else {
-			fmt.Printf("✅ Webhook test sent to %s\n", webhook.URL)
+			fmt.Printf("✅ Webhook test delivered to %s (HTTP %d, %dms)\n", webhook.URL, result.StatusCode, result.LatencyMs)
+		}
+
+		// Pause the webhook; Active is a *bool so that false is sent
+		// explicitly rather than omitted
+		_, err = client.Webhooks.Update(ctx, webhook.ID, jewelmusic.WebhookUpdate{
+			Active: jewelmusic.Bool(false),
+		})
+		if err != nil {
+			log.Printf("Failed to pause webhook: %v", err)
 		}
 	}
-}
\ No newline at end of file
+}
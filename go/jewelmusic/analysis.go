@@ -2,44 +2,69 @@ package jewelmusic
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"io"
+	"sort"
+	"strconv"
 )
 
 // AnalysisResource provides music analysis capabilities
 type AnalysisResource struct {
 	client *Client
+
+	// presets caches the mastering presets used for validation
+	presets lazy[[]MasteringPreset]
+	// types caches the analysis types used for validation
+	types lazy[[]string]
 }
 
 // AnalysisOptions represents options for audio analysis
 type AnalysisOptions struct {
-	AnalysisTypes    []string `json:"analysisTypes,omitempty"`
-	DetailedReport   bool     `json:"detailedReport,omitempty"`
-	CulturalContext  string   `json:"culturalContext,omitempty"`
-	TargetPlatforms  []string `json:"targetPlatforms,omitempty"`
-	TargetLoudness   float64  `json:"targetLoudness,omitempty"`
+	AnalysisTypes   []string `json:"analysisTypes,omitempty"`
+	DetailedReport  bool     `json:"detailedReport,omitempty"`
+	CulturalContext string   `json:"culturalContext,omitempty"`
+	TargetPlatforms []string `json:"targetPlatforms,omitempty"`
+	TargetLoudness  float64  `json:"targetLoudness,omitempty"`
+	Priority        Priority `json:"priority,omitempty"`
 }
 
 // QualityCheckOptions represents options for quality analysis
 type QualityCheckOptions struct {
-	CheckClipping      bool    `json:"checkClipping,omitempty"`
-	CheckPhaseIssues   bool    `json:"checkPhaseIssues,omitempty"`
-	CheckDynamicRange  bool    `json:"checkDynamicRange,omitempty"`
-	TargetLoudness     float64 `json:"targetLoudness,omitempty"`
-	TargetPlatform     string  `json:"targetPlatform,omitempty"`
+	CheckClipping     bool    `json:"checkClipping,omitempty"`
+	CheckPhaseIssues  bool    `json:"checkPhaseIssues,omitempty"`
+	CheckDynamicRange bool    `json:"checkDynamicRange,omitempty"`
+	TargetLoudness    float64 `json:"targetLoudness,omitempty"`
+	TargetPlatform    string  `json:"targetPlatform,omitempty"`
 }
 
 // MasteringSuggestionOptions represents options for mastering suggestions
 type MasteringSuggestionOptions struct {
-	TargetPlatform   string `json:"targetPlatform,omitempty"`
-	Genre            string `json:"genre,omitempty"`
-	IncludePresets   bool   `json:"includePresets,omitempty"`
+	TargetPlatform string `json:"targetPlatform,omitempty"`
+	Genre          string `json:"genre,omitempty"`
+	IncludePresets bool   `json:"includePresets,omitempty"`
 }
 
+// Stems that can be isolated by SeparateStems
+const (
+	StemVocals = "vocals"
+	StemDrums  = "drums"
+	StemBass   = "bass"
+	StemOther  = "other"
+)
+
 // UploadTrack uploads and analyzes an audio track
 func (a *AnalysisResource) UploadTrack(ctx context.Context, file io.Reader, filename string, options *AnalysisOptions) (*Analysis, error) {
+	if options == nil {
+		options = a.client.defaultAnalysisOptions
+	}
+
 	metadata := make(map[string]string)
-	
+
 	if options != nil {
+		if err := a.validateAnalysisTypes(ctx, options.AnalysisTypes); err != nil {
+			return nil, err
+		}
 		if len(options.AnalysisTypes) > 0 {
 			// Convert slice to comma-separated string
 			analysisTypesStr := ""
@@ -51,15 +76,15 @@ func (a *AnalysisResource) UploadTrack(ctx context.Context, file io.Reader, file
 			}
 			metadata["analysisTypes"] = analysisTypesStr
 		}
-		
+
 		if options.DetailedReport {
 			metadata["detailedReport"] = "true"
 		}
-		
+
 		if options.CulturalContext != "" {
 			metadata["culturalContext"] = options.CulturalContext
 		}
-		
+
 		if len(options.TargetPlatforms) > 0 {
 			// Convert slice to comma-separated string
 			platformsStr := ""
@@ -71,6 +96,13 @@ func (a *AnalysisResource) UploadTrack(ctx context.Context, file io.Reader, file
 			}
 			metadata["targetPlatforms"] = platformsStr
 		}
+
+		if err := options.Priority.validate(); err != nil {
+			return nil, err
+		}
+		if options.Priority != "" {
+			metadata["priority"] = string(options.Priority)
+		}
 	}
 
 	resp, err := a.client.UploadFile(ctx, "/analysis/upload", file, filename, metadata)
@@ -87,6 +119,23 @@ func (a *AnalysisResource) UploadTrack(ctx context.Context, file io.Reader, file
 	return &result, nil
 }
 
+// UploadTrackPath uploads and analyzes an audio track from a local file path
+func (a *AnalysisResource) UploadTrackPath(ctx context.Context, path string, options *AnalysisOptions) (*Analysis, error) {
+	return uploadFromPath(path, func(file io.Reader, filename string) (*Analysis, error) {
+		return a.UploadTrack(ctx, file, filename, options)
+	})
+}
+
+// UploadTrackPaths uploads and analyzes several local files with at most
+// concurrency uploads in flight. Each result's ID is its path. The batch is
+// rejected with a *QuotaExceededError if it exceeds the remaining upload
+// quota, unless the client was created with WithoutQuotaCheck.
+func (a *AnalysisResource) UploadTrackPaths(ctx context.Context, paths []string, options *AnalysisOptions, concurrency int) ([]BatchResult[*Analysis], error) {
+	return runQuotaBatch(ctx, a.client, "uploads", paths, concurrency, func(ctx context.Context, path string) (*Analysis, error) {
+		return a.UploadTrackPath(ctx, path, options)
+	})
+}
+
 // GetAnalysis retrieves analysis results by ID
 func (a *AnalysisResource) GetAnalysis(ctx context.Context, analysisID string) (*Analysis, error) {
 	var result Analysis
@@ -96,8 +145,12 @@ func (a *AnalysisResource) GetAnalysis(ctx context.Context, analysisID string) (
 
 // AudioQualityCheck performs audio quality analysis
 func (a *AnalysisResource) AudioQualityCheck(ctx context.Context, file io.Reader, filename string, options *QualityCheckOptions) (*QualityAnalysis, error) {
+	if options == nil {
+		options = a.client.defaultQualityCheckOptions
+	}
+
 	metadata := make(map[string]string)
-	
+
 	if options != nil {
 		if options.CheckClipping {
 			metadata["checkClipping"] = "true"
@@ -109,7 +162,7 @@ func (a *AnalysisResource) AudioQualityCheck(ctx context.Context, file io.Reader
 			metadata["checkDynamicRange"] = "true"
 		}
 		if options.TargetLoudness != 0 {
-			metadata["targetLoudness"] = string(rune(int(options.TargetLoudness)))
+			metadata["targetLoudness"] = strconv.FormatFloat(options.TargetLoudness, 'f', -1, 64)
 		}
 		if options.TargetPlatform != "" {
 			metadata["targetPlatform"] = options.TargetPlatform
@@ -122,14 +175,66 @@ func (a *AnalysisResource) AudioQualityCheck(ctx context.Context, file io.Reader
 	}
 
 	var result QualityAnalysis
-	// Convert response data to QualityAnalysis struct
+	if err := resp.DecodeData(&result); err != nil {
+		return nil, err
+	}
 	return &result, nil
 }
 
+// ListMasteringPresets lists the available mastering presets, such as
+// streaming, club, and vinyl
+func (a *AnalysisResource) ListMasteringPresets(ctx context.Context) ([]MasteringPreset, error) {
+	var result []MasteringPreset
+	err := a.client.Get(ctx, "/analysis/mastering-presets", nil, &result)
+	return result, err
+}
+
+// AvailableTypes lists the analysis types that can be requested in
+// AnalysisOptions.AnalysisTypes
+func (a *AnalysisResource) AvailableTypes(ctx context.Context) ([]string, error) {
+	var result []string
+	err := a.client.Get(ctx, "/analysis/types", nil, &result)
+	return result, err
+}
+
+// validateAnalysisTypes checks that every requested type is one of the
+// available types. The available types are fetched once per client and
+// cached, and only when types are requested.
+func (a *AnalysisResource) validateAnalysisTypes(ctx context.Context, types []string) error {
+	if len(types) == 0 {
+		return nil
+	}
+	available, err := a.types.get(ctx, a.AvailableTypes)
+	if err != nil {
+		return err
+	}
+	for _, t := range types {
+		if !containsString(available, t) {
+			return &APIError{Code: "INVALID_REQUEST", Message: "Unknown analysis type: " + t}
+		}
+	}
+	return nil
+}
+
+// validateMasteringPreset checks that name is one of the available presets.
+// The presets are fetched once per client and cached.
+func (a *AnalysisResource) validateMasteringPreset(ctx context.Context, name string) error {
+	presets, err := a.presets.get(ctx, a.ListMasteringPresets)
+	if err != nil {
+		return err
+	}
+	for _, preset := range presets {
+		if preset.Name == name {
+			return nil
+		}
+	}
+	return &APIError{Code: "INVALID_REQUEST", Message: "Unknown mastering preset: " + name}
+}
+
 // GetMasteringSuggestions gets mastering suggestions for an audio file
 func (a *AnalysisResource) GetMasteringSuggestions(ctx context.Context, file io.Reader, filename string, options *MasteringSuggestionOptions) (map[string]interface{}, error) {
 	metadata := make(map[string]string)
-	
+
 	if options != nil {
 		if options.TargetPlatform != "" {
 			metadata["targetPlatform"] = options.TargetPlatform
@@ -190,10 +295,7 @@ func (a *AnalysisResource) AnalyzeTempo(ctx context.Context, file io.Reader, fil
 
 // ListAnalyses lists user's analyses with pagination
 func (a *AnalysisResource) ListAnalyses(ctx context.Context, page, perPage int, status string) (*ListResponse, error) {
-	params := map[string]string{
-		"page":    string(rune(page)),
-		"perPage": string(rune(perPage)),
-	}
+	params := pageParams(page, perPage)
 	if status != "" {
 		params["status"] = status
 	}
@@ -201,4 +303,211 @@ func (a *AnalysisResource) ListAnalyses(ctx context.Context, page, perPage int,
 	var result ListResponse
 	err := a.client.Get(ctx, "/analysis", params, &result)
 	return &result, err
-}
\ No newline at end of file
+}
+
+// Iterator returns an iterator over all of the user's analyses, optionally
+// filtered by status
+func (a *AnalysisResource) Iterator(status string) *Iterator[Analysis] {
+	return newIterator[Analysis](func(ctx context.Context, page, perPage int) (*ListResponse, error) {
+		return a.ListAnalyses(ctx, page, perPage, status)
+	})
+}
+
+// WaitForAnalysis polls an analysis until it reaches a terminal status
+func (a *AnalysisResource) WaitForAnalysis(ctx context.Context, analysisID string, options *PollOptions) (*Analysis, error) {
+	return Poll(ctx, func() (*Analysis, error) {
+		return a.GetAnalysis(detachCallOptions(ctx), analysisID)
+	}, func(analysis *Analysis) bool {
+		return IsTerminalStatus(analysis.Status)
+	}, a.client.pollOptions(options))
+}
+
+// Cancel cancels an in-progress analysis and returns its updated state.
+// ErrAlreadyCompleted is returned if the analysis has already finished.
+func (a *AnalysisResource) Cancel(ctx context.Context, analysisID string) (*Analysis, error) {
+	var result Analysis
+	err := a.client.cancelJob(ctx, "/analysis/"+analysisID+"/cancel", &result, func() string { return result.Status })
+	return &result, err
+}
+
+// SeparateStems starts separating a track into the given stems. When stems
+// is empty, all supported stems are produced.
+func (a *AnalysisResource) SeparateStems(ctx context.Context, trackID string, stems []string) (*StemJob, error) {
+	for _, stem := range stems {
+		switch stem {
+		case StemVocals, StemDrums, StemBass, StemOther:
+		default:
+			return nil, &APIError{Code: "INVALID_REQUEST", Message: "Unsupported stem: " + stem}
+		}
+	}
+
+	requestData := map[string]interface{}{
+		"trackId": trackID,
+	}
+	if len(stems) > 0 {
+		requestData["stems"] = stems
+	}
+
+	var result StemJob
+	err := a.client.Post(ctx, "/analysis/stems", requestData, &result)
+	return &result, err
+}
+
+// GetStemJob gets the status of a stem separation job
+func (a *AnalysisResource) GetStemJob(ctx context.Context, jobID string) (*StemJob, error) {
+	var result StemJob
+	err := a.client.Get(ctx, "/analysis/stems/"+jobID, nil, &result)
+	return &result, err
+}
+
+// WaitForStems polls a stem separation job until it reaches a terminal status
+func (a *AnalysisResource) WaitForStems(ctx context.Context, jobID string, options *PollOptions) (*StemJob, error) {
+	return Poll(ctx, func() (*StemJob, error) {
+		return a.GetStemJob(detachCallOptions(ctx), jobID)
+	}, func(job *StemJob) bool {
+		return IsTerminalStatus(job.Status)
+	}, a.client.pollOptions(options))
+}
+
+// DetectExplicit detects explicit content in a track
+func (a *AnalysisResource) DetectExplicit(ctx context.Context, trackID string) (*ExplicitReport, error) {
+	requestData := map[string]interface{}{
+		"trackId": trackID,
+	}
+
+	var result ExplicitReport
+	err := a.client.Post(ctx, "/analysis/explicit", requestData, &result)
+	return &result, err
+}
+
+// ClassifyGenre classifies a track into genres, ranked by confidence from
+// highest to lowest
+func (a *AnalysisResource) ClassifyGenre(ctx context.Context, trackID string) ([]GenreScore, error) {
+	requestData := map[string]interface{}{
+		"trackId": trackID,
+	}
+
+	var result []GenreScore
+	if err := a.client.Post(ctx, "/analysis/genre", requestData, &result); err != nil {
+		return nil, err
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Confidence > result[j].Confidence
+	})
+	return result, nil
+}
+
+// DetectMood detects the mood, energy, and danceability of a track
+func (a *AnalysisResource) DetectMood(ctx context.Context, trackID string) (*MoodReport, error) {
+	requestData := map[string]interface{}{
+		"trackId": trackID,
+	}
+
+	var result MoodReport
+	err := a.client.Post(ctx, "/analysis/mood", requestData, &result)
+	return &result, err
+}
+
+// AnalysisDiff describes how a track's analysis changed between two runs,
+// for example before and after remastering. Deltas are after minus before.
+type AnalysisDiff struct {
+	TempoDelta       float64
+	KeyChanged       bool
+	KeyBefore        string
+	KeyAfter         string
+	QualityDelta     float64
+	DetailDeltas     map[string]float64
+	IssuesResolved   []string
+	IssuesIntroduced []string
+}
+
+// Diff compares two analyses locally without calling the API. Quality
+// details are compared when present in both analyses. It returns nil if
+// either analysis is nil.
+func (a *AnalysisResource) Diff(before, after *Analysis) *AnalysisDiff {
+	if before == nil || after == nil {
+		return nil
+	}
+
+	diff := &AnalysisDiff{
+		TempoDelta:   after.Tempo.BPM - before.Tempo.BPM,
+		KeyBefore:    keyName(before.Key),
+		KeyAfter:     keyName(after.Key),
+		QualityDelta: after.Quality.OverallScore - before.Quality.OverallScore,
+		DetailDeltas: make(map[string]float64),
+	}
+	diff.KeyChanged = diff.KeyBefore != diff.KeyAfter
+
+	for name, value := range after.Quality.Details {
+		if previous, ok := before.Quality.Details[name]; ok {
+			diff.DetailDeltas[name] = value - previous
+		}
+	}
+
+	diff.IssuesResolved = missingFrom(before.Quality.Issues, after.Quality.Issues)
+	diff.IssuesIntroduced = missingFrom(after.Quality.Issues, before.Quality.Issues)
+	return diff
+}
+
+// keyName formats a detected key, e.g. "A minor"
+func keyName(key KeyAnalysis) string {
+	if key.Mode == "" {
+		return key.Key
+	}
+	return key.Key + " " + key.Mode
+}
+
+// missingFrom returns the items of from that are not in other
+func missingFrom(from, other []string) []string {
+	present := make(map[string]bool, len(other))
+	for _, item := range other {
+		present[item] = true
+	}
+
+	var missing []string
+	for _, item := range from {
+		if !present[item] {
+			missing = append(missing, item)
+		}
+	}
+	return missing
+}
+
+// AnalysisSchemaVersion is the version of the format written by ExportJSON
+const AnalysisSchemaVersion = 1
+
+// analysisExport is the versioned envelope used by ExportJSON and ImportJSON
+type analysisExport struct {
+	SchemaVersion int       `json:"schemaVersion"`
+	Analysis      *Analysis `json:"analysis"`
+}
+
+// ExportJSON writes analysis to w as versioned JSON that can be reloaded
+// with ImportJSON
+func (a *AnalysisResource) ExportJSON(analysis *Analysis, w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(analysisExport{SchemaVersion: AnalysisSchemaVersion, Analysis: analysis}); err != nil {
+		return fmt.Errorf("failed to export analysis: %w", err)
+	}
+	return nil
+}
+
+// ImportJSON reads an analysis written by ExportJSON. Exports from a newer
+// schema version than AnalysisSchemaVersion are rejected.
+func (a *AnalysisResource) ImportJSON(r io.Reader) (*Analysis, error) {
+	var export analysisExport
+	if err := json.NewDecoder(r).Decode(&export); err != nil {
+		return nil, fmt.Errorf("failed to import analysis: %w", err)
+	}
+
+	switch {
+	case export.SchemaVersion < 1:
+		return nil, fmt.Errorf("failed to import analysis: missing schema version")
+	case export.SchemaVersion > AnalysisSchemaVersion:
+		return nil, fmt.Errorf("failed to import analysis: unsupported schema version %d", export.SchemaVersion)
+	case export.Analysis == nil:
+		return nil, fmt.Errorf("failed to import analysis: no analysis data")
+	}
+	return export.Analysis, nil
+}
@@ -0,0 +1,513 @@
+package jewelmusic
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAudioQualityCheck(t *testing.T) {
+	var fields map[string][]string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/analysis/quality-check" {
+			t.Errorf("path = %s", r.URL.Path)
+		}
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Errorf("parsing upload: %v", err)
+			return
+		}
+		fields = r.MultipartForm.Value
+		writeData(t, w, map[string]interface{}{
+			"overallScore": 61.5,
+			"details":      map[string]interface{}{"peakLevel": 0.4, "phaseCorrelation": -0.35, "dynamicRange": 6},
+			"issues":       []string{"clipping", "phase_cancellation"},
+		})
+	})
+
+	options := &QualityCheckOptions{CheckClipping: true, CheckPhaseIssues: true, TargetLoudness: -14}
+	quality, err := client.Analysis.AudioQualityCheck(context.Background(), strings.NewReader("audio"), "mix.wav", options)
+	if err != nil {
+		t.Fatalf("AudioQualityCheck: %v", err)
+	}
+
+	for key, want := range map[string]string{"checkClipping": "true", "checkPhaseIssues": "true", "targetLoudness": "-14"} {
+		if got := fields[key]; len(got) != 1 || got[0] != want {
+			t.Errorf("field %s = %v, want %s", key, got, want)
+		}
+	}
+	if _, ok := fields["checkDynamicRange"]; ok {
+		t.Error("checkDynamicRange sent although not requested")
+	}
+
+	if quality.OverallScore != 61.5 {
+		t.Errorf("overall score = %v", quality.OverallScore)
+	}
+	if strings.Join(quality.Issues, ",") != "clipping,phase_cancellation" {
+		t.Errorf("issues = %v", quality.Issues)
+	}
+	wantDetails := map[string]float64{"peakLevel": 0.4, "phaseCorrelation": -0.35, "dynamicRange": 6}
+	if len(quality.Details) != len(wantDetails) {
+		t.Errorf("details = %v, want %v", quality.Details, wantDetails)
+	}
+	for key, want := range wantDetails {
+		if got := quality.Details[key]; got != want {
+			t.Errorf("details[%s] = %v, want %v", key, got, want)
+		}
+	}
+}
+
+func TestMasteringPresets(t *testing.T) {
+	presets := []map[string]interface{}{
+		{"name": "streaming", "description": "Loud and clear for streaming", "targetLoudness": -14, "targetPlatform": "spotify"},
+		{"name": "club", "description": "Punchy club master", "targetLoudness": -8},
+		{"name": "vinyl", "description": "Dynamic master for lacquer cutting", "targetLoudness": -16},
+	}
+
+	tests := []struct {
+		name        string
+		preset      string
+		wantPresets int
+		wantSongs   int
+		wantErr     bool
+	}{
+		{"known preset", "club", 1, 1, false},
+		{"unknown preset", "radio", 1, 0, true},
+		{"no preset", "", 0, 1, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var presetCalls, songCalls int
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/v1/analysis/mastering-presets":
+					presetCalls++
+					writeData(t, w, presets)
+				case "/v1/copilot/complete-song":
+					songCalls++
+					writeData(t, w, map[string]interface{}{"id": "gen_1", "status": "pending"})
+				default:
+					t.Errorf("unexpected path %s", r.URL.Path)
+				}
+			})
+
+			ctx := context.Background()
+			for i := 0; i < 2; i++ {
+				_, err := client.Copilot.CompleteSong(ctx, SongOptions{MasteringPreset: tt.preset})
+				if tt.wantErr != (err != nil) {
+					t.Fatalf("CompleteSong error = %v, want error %v", err, tt.wantErr)
+				}
+			}
+			if songCalls != 2*tt.wantSongs {
+				t.Errorf("songs requested = %d, want %d", songCalls, 2*tt.wantSongs)
+			}
+			if presetCalls != tt.wantPresets {
+				t.Errorf("preset listings = %d, want %d (cached)", presetCalls, tt.wantPresets)
+			}
+		})
+	}
+
+	t.Run("listing", func(t *testing.T) {
+		client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			writeData(t, w, presets)
+		})
+
+		got, err := client.Analysis.ListMasteringPresets(context.Background())
+		if err != nil {
+			t.Fatalf("ListMasteringPresets: %v", err)
+		}
+		want := []MasteringPreset{
+			{Name: "streaming", Description: "Loud and clear for streaming", TargetLoudness: -14, TargetPlatform: "spotify"},
+			{Name: "club", Description: "Punchy club master", TargetLoudness: -8},
+			{Name: "vinyl", Description: "Dynamic master for lacquer cutting", TargetLoudness: -16},
+		}
+		if len(got) != len(want) {
+			t.Fatalf("presets = %+v, want %+v", got, want)
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Errorf("preset %d = %+v, want %+v", i, got[i], want[i])
+			}
+		}
+	})
+}
+
+func TestStemSeparationLifecycle(t *testing.T) {
+	statuses := []string{"pending", "processing", "completed"}
+	var requestBody string
+	polls := 0
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/v1/analysis/stems":
+			data, _ := io.ReadAll(r.Body)
+			requestBody = strings.TrimSpace(string(data))
+			writeData(t, w, map[string]interface{}{"id": "stm_1", "trackId": "trk_1", "status": "pending", "stems": []string{"vocals", "drums"}})
+		case r.Method == http.MethodGet && r.URL.Path == "/v1/analysis/stems/stm_1":
+			job := map[string]interface{}{"id": "stm_1", "trackId": "trk_1", "status": statuses[polls], "stems": []string{"vocals", "drums"}}
+			if statuses[polls] == "completed" {
+				job["stemUrls"] = map[string]string{
+					"vocals": "https://cdn.example.com/stm_1/vocals.wav",
+					"drums":  "https://cdn.example.com/stm_1/drums.wav",
+				}
+			}
+			polls++
+			writeData(t, w, job)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	})
+
+	ctx := context.Background()
+	job, err := client.Analysis.SeparateStems(ctx, "trk_1", []string{StemVocals, StemDrums})
+	if err != nil {
+		t.Fatalf("SeparateStems: %v", err)
+	}
+	if requestBody != `{"stems":["vocals","drums"],"trackId":"trk_1"}` {
+		t.Errorf("body = %s", requestBody)
+	}
+	if job.Status != "pending" || len(job.StemURLs) != 0 {
+		t.Errorf("started job = %+v", job)
+	}
+
+	done, err := client.Analysis.WaitForStems(ctx, job.ID, &PollOptions{Interval: time.Millisecond})
+	if err != nil {
+		t.Fatalf("WaitForStems: %v", err)
+	}
+	if polls != len(statuses) {
+		t.Errorf("polls = %d, want %d", polls, len(statuses))
+	}
+	if done.Status != "completed" || done.StemURLs[StemVocals] != "https://cdn.example.com/stm_1/vocals.wav" || len(done.StemURLs) != 2 {
+		t.Errorf("finished job = %+v", done)
+	}
+
+	if _, err := client.Analysis.SeparateStems(ctx, "trk_1", []string{"guitar"}); err == nil {
+		t.Error("unsupported stem accepted")
+	}
+}
+
+func TestDetectExplicit(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/v1/analysis/explicit" {
+			t.Errorf("request = %s %s", r.Method, r.URL.Path)
+		}
+		writeData(t, w, map[string]interface{}{
+			"trackId":    "trk_1",
+			"explicit":   true,
+			"confidence": 0.93,
+			"flagged": []map[string]interface{}{
+				{"startTime": 32.5, "endTime": 34.1, "text": "****", "confidence": 0.98},
+				{"startTime": 75, "endTime": 76.2, "confidence": 0.88},
+			},
+		})
+	})
+
+	report, err := client.Analysis.DetectExplicit(context.Background(), "trk_1")
+	if err != nil {
+		t.Fatalf("DetectExplicit: %v", err)
+	}
+	if report.TrackID != "trk_1" || !report.Explicit || report.Confidence != 0.93 {
+		t.Errorf("report = %+v", report)
+	}
+	want := []ExplicitSegment{
+		{StartTime: 32.5, EndTime: 34.1, Text: "****", Confidence: 0.98},
+		{StartTime: 75, EndTime: 76.2, Confidence: 0.88},
+	}
+	if len(report.Flagged) != len(want) {
+		t.Fatalf("flagged = %+v, want %+v", report.Flagged, want)
+	}
+	for i := range want {
+		if report.Flagged[i] != want[i] {
+			t.Errorf("flagged %d = %+v, want %+v", i, report.Flagged[i], want[i])
+		}
+	}
+}
+
+func TestClassifyGenre(t *testing.T) {
+	tests := []struct {
+		name     string
+		response []map[string]interface{}
+		want     []GenreScore
+	}{
+		{
+			name: "already ranked",
+			response: []map[string]interface{}{
+				{"genre": "house", "confidence": 0.82}, {"genre": "techno", "confidence": 0.11},
+			},
+			want: []GenreScore{{"house", 0.82}, {"techno", 0.11}},
+		},
+		{
+			name: "unordered",
+			response: []map[string]interface{}{
+				{"genre": "jazz", "confidence": 0.2}, {"genre": "soul", "confidence": 0.65}, {"genre": "funk", "confidence": 0.15},
+			},
+			want: []GenreScore{{"soul", 0.65}, {"jazz", 0.2}, {"funk", 0.15}},
+		},
+		{name: "empty", response: []map[string]interface{}{}, want: []GenreScore{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/v1/analysis/genre" {
+					t.Errorf("path = %s", r.URL.Path)
+				}
+				writeData(t, w, tt.response)
+			})
+
+			got, err := client.Analysis.ClassifyGenre(context.Background(), "trk_1")
+			if err != nil {
+				t.Fatalf("ClassifyGenre: %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("genres = %+v, want %+v", got, tt.want)
+			}
+			for i := range tt.want {
+				if got[i] != tt.want[i] {
+					t.Errorf("rank %d = %+v, want %+v", i+1, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestDetectMood(t *testing.T) {
+	var body string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/v1/analysis/mood" {
+			t.Errorf("request = %s %s", r.Method, r.URL.Path)
+		}
+		data, _ := io.ReadAll(r.Body)
+		body = strings.TrimSpace(string(data))
+		writeData(t, w, map[string]interface{}{
+			"trackId": "trk_1", "valence": 0.72, "energy": 0.88, "danceability": 0.91,
+			"moods": []string{"euphoric", "uplifting"},
+		})
+	})
+
+	report, err := client.Analysis.DetectMood(context.Background(), "trk_1")
+	if err != nil {
+		t.Fatalf("DetectMood: %v", err)
+	}
+	if body != `{"trackId":"trk_1"}` {
+		t.Errorf("body = %s", body)
+	}
+	if report.TrackID != "trk_1" || report.Valence != 0.72 || report.Energy != 0.88 || report.Danceability != 0.91 {
+		t.Errorf("report = %+v", report)
+	}
+	if strings.Join(report.Moods, ",") != "euphoric,uplifting" {
+		t.Errorf("moods = %v", report.Moods)
+	}
+}
+
+func TestAnalysisDiff(t *testing.T) {
+	original := &Analysis{
+		ID:    "ana_1",
+		Tempo: TempoAnalysis{BPM: 120},
+		Key:   KeyAnalysis{Key: "A", Mode: "minor"},
+		Quality: QualityAnalysis{
+			OverallScore: 6.5,
+			Details:      map[string]float64{"loudness": -18, "dynamicRange": 12, "stereoWidth": 0.5},
+			Issues:       []string{"clipping", "low_loudness"},
+		},
+	}
+
+	tests := []struct {
+		name          string
+		before, after *Analysis
+		want          *AnalysisDiff
+	}{
+		{
+			name:   "remaster",
+			before: original,
+			after: &Analysis{
+				ID:    "ana_2",
+				Tempo: TempoAnalysis{BPM: 120},
+				Key:   KeyAnalysis{Key: "A", Mode: "minor"},
+				Quality: QualityAnalysis{
+					OverallScore: 8.75,
+					Details:      map[string]float64{"loudness": -14, "dynamicRange": 8, "truePeak": -1},
+					Issues:       []string{"low_loudness", "over_compressed"},
+				},
+			},
+			want: &AnalysisDiff{
+				KeyBefore:        "A minor",
+				KeyAfter:         "A minor",
+				QualityDelta:     2.25,
+				DetailDeltas:     map[string]float64{"loudness": 4, "dynamicRange": -4},
+				IssuesResolved:   []string{"clipping"},
+				IssuesIntroduced: []string{"over_compressed"},
+			},
+		},
+		{
+			name:   "re-edit in a new key",
+			before: original,
+			after: &Analysis{
+				Tempo:   TempoAnalysis{BPM: 124.5},
+				Key:     KeyAnalysis{Key: "C", Mode: "major"},
+				Quality: QualityAnalysis{OverallScore: 6.5},
+			},
+			want: &AnalysisDiff{
+				TempoDelta:     4.5,
+				KeyChanged:     true,
+				KeyBefore:      "A minor",
+				KeyAfter:       "C major",
+				DetailDeltas:   map[string]float64{},
+				IssuesResolved: []string{"clipping", "low_loudness"},
+			},
+		},
+		{
+			name:   "missing analysis",
+			before: original,
+		},
+	}
+
+	client := NewClient("jml_live_test")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := client.Analysis.Diff(tt.before, tt.after)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Diff = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAnalysisJSONRoundTrip(t *testing.T) {
+	completed := time.Date(2026, 10, 1, 12, 30, 0, 0, time.UTC)
+	analysis := &Analysis{
+		ID:      "ana_1",
+		TrackID: "trk_1",
+		Status:  "completed",
+		Tempo:   TempoAnalysis{BPM: 128, Confidence: 0.97, TimeSignature: "4/4"},
+		Key:     KeyAnalysis{Key: "F#", Mode: "minor", Confidence: 0.88},
+		Structure: StructureAnalysis{
+			Sections: []Section{{Type: "intro", EndTime: 15, Duration: 15}, {Type: "chorus", StartTime: 15, EndTime: 45.5, Duration: 30.5}},
+			Form:     "ABAB",
+		},
+		Quality: QualityAnalysis{
+			OverallScore: 8.5,
+			Details:      map[string]float64{"loudness": -14},
+			Issues:       []string{"clipping"},
+		},
+		CreatedAt:   completed.Add(-time.Minute),
+		CompletedAt: &completed,
+	}
+	client := NewClient("jml_live_test")
+
+	var buf strings.Builder
+	if err := client.Analysis.ExportJSON(analysis, &buf); err != nil {
+		t.Fatalf("ExportJSON: %v", err)
+	}
+	if !strings.Contains(buf.String(), `"schemaVersion": 1`) {
+		t.Errorf("export does not record the schema version:\n%s", buf.String())
+	}
+
+	imported, err := client.Analysis.ImportJSON(strings.NewReader(buf.String()))
+	if err != nil {
+		t.Fatalf("ImportJSON: %v", err)
+	}
+	if !reflect.DeepEqual(imported, analysis) {
+		t.Errorf("imported = %+v, want %+v", imported, analysis)
+	}
+}
+
+func TestAnalysisImportJSONRejectsInvalidExports(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"newer schema", `{"schemaVersion": 2, "analysis": {"id": "ana_1"}}`},
+		{"missing schema version", `{"analysis": {"id": "ana_1"}}`},
+		{"missing analysis", `{"schemaVersion": 1}`},
+		{"not JSON", `analysis`},
+	}
+
+	client := NewClient("jml_live_test")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if analysis, err := client.Analysis.ImportJSON(strings.NewReader(tt.input)); err == nil {
+				t.Errorf("ImportJSON = %+v, want an error", analysis)
+			}
+		})
+	}
+}
+
+func TestAnalysisTypeValidation(t *testing.T) {
+	available := []string{"tempo", "key", "structure", "loudness"}
+
+	tests := []struct {
+		name        string
+		types       []string
+		wantTypes   int
+		wantUploads int
+		wantUnknown string
+	}{
+		{"known types", []string{"tempo", "key"}, 1, 2, ""},
+		{"unknown type", []string{"tempo", "vibe"}, 1, 0, "vibe"},
+		{"no types", nil, 0, 2, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var typeCalls, uploads int
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/v1/analysis/types":
+					typeCalls++
+					writeData(t, w, available)
+				case "/v1/analysis/upload":
+					uploads++
+					if got, want := requestFields(t, r)["analysisTypes"], strings.Join(tt.types, ","); got != want {
+						t.Errorf("analysisTypes = %q, want %q", got, want)
+					}
+					writeData(t, w, map[string]interface{}{"id": "ana_1", "status": "pending"})
+				default:
+					t.Errorf("unexpected path %s", r.URL.Path)
+				}
+			})
+
+			ctx := context.Background()
+			for i := 0; i < 2; i++ {
+				_, err := client.Analysis.UploadTrack(ctx, strings.NewReader("RIFF"), "track.wav", &AnalysisOptions{AnalysisTypes: tt.types})
+				if tt.wantUnknown == "" {
+					if err != nil {
+						t.Fatalf("UploadTrack: %v", err)
+					}
+					continue
+				}
+				var apiErr *APIError
+				if !errors.As(err, &apiErr) || apiErr.Code != "INVALID_REQUEST" || !strings.Contains(apiErr.Message, tt.wantUnknown) {
+					t.Fatalf("UploadTrack error = %v, want an invalid request naming %q", err, tt.wantUnknown)
+				}
+			}
+			if uploads != tt.wantUploads {
+				t.Errorf("uploads = %d, want %d", uploads, tt.wantUploads)
+			}
+			if typeCalls != tt.wantTypes {
+				t.Errorf("type listings = %d, want %d (cached)", typeCalls, tt.wantTypes)
+			}
+		})
+	}
+
+	t.Run("listing", func(t *testing.T) {
+		client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodGet || r.URL.Path != "/v1/analysis/types" {
+				t.Errorf("request = %s %s, want GET /v1/analysis/types", r.Method, r.URL.Path)
+			}
+			writeData(t, w, available)
+		})
+
+		got, err := client.Analysis.AvailableTypes(context.Background())
+		if err != nil {
+			t.Fatalf("AvailableTypes: %v", err)
+		}
+		if !reflect.DeepEqual(got, available) {
+			t.Errorf("types = %v, want %v", got, available)
+		}
+	})
+}
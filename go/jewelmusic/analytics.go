@@ -3,7 +3,6 @@ package jewelmusic
 import (
 	"context"
 	"strconv"
-	"strings"
 )
 
 // AnalyticsResource provides comprehensive analytics and reporting
@@ -24,17 +23,17 @@ type AnalyticsQuery struct {
 
 // RoyaltyReportOptions represents options for royalty reports
 type RoyaltyReportOptions struct {
-	Currency        string   `json:"currency,omitempty"`
-	IncludePending  bool     `json:"includePending,omitempty"`
-	GroupBy         string   `json:"groupBy,omitempty"`
-	Platforms       []string `json:"platforms,omitempty"`
+	Currency       string   `json:"currency,omitempty"`
+	IncludePending bool     `json:"includePending,omitempty"`
+	GroupBy        string   `json:"groupBy,omitempty"`
+	Platforms      []string `json:"platforms,omitempty"`
 }
 
 // RevenueProjectionOptions represents options for revenue projections
 type RevenueProjectionOptions struct {
-	Period                     string   `json:"period,omitempty"`
-	Tracks                     []string `json:"tracks,omitempty"`
-	Platforms                  []string `json:"platforms,omitempty"`
+	Period                    string   `json:"period,omitempty"`
+	Tracks                    []string `json:"tracks,omitempty"`
+	Platforms                 []string `json:"platforms,omitempty"`
 	IncludeConfidenceInterval bool     `json:"includeConfidenceInterval,omitempty"`
 }
 
@@ -57,12 +56,12 @@ type ExportOptions struct {
 
 // AlertConfig represents configuration for analytics alerts
 type AlertConfig struct {
-	Name         string      `json:"name"`
-	Condition    AlertCondition `json:"condition"`
-	Notifications []string   `json:"notifications"`
-	Email        string      `json:"email,omitempty"`
-	WebhookURL   string      `json:"webhookUrl,omitempty"`
-	Phone        string      `json:"phone,omitempty"`
+	Name          string         `json:"name"`
+	Condition     AlertCondition `json:"condition"`
+	Notifications []string       `json:"notifications"`
+	Email         string         `json:"email,omitempty"`
+	WebhookURL    string         `json:"webhookUrl,omitempty"`
+	Phone         string         `json:"phone,omitempty"`
 }
 
 // AlertCondition represents an alert condition
@@ -73,143 +72,67 @@ type AlertCondition struct {
 	Period    string  `json:"period"`
 }
 
-// GetStreams gets streaming analytics data
-func (a *AnalyticsResource) GetStreams(ctx context.Context, query AnalyticsQuery) (*AnalyticsData, error) {
+// queryParams converts the query into scalar and multi-valued request parameters
+func (q AnalyticsQuery) queryParams() (map[string]string, map[string][]string) {
 	params := map[string]string{
-		"startDate": query.StartDate,
-		"endDate":   query.EndDate,
-	}
-	
-	if query.GroupBy != "" {
-		params["groupBy"] = query.GroupBy
-	}
-	if len(query.Platforms) > 0 {
-		params["platforms"] = strings.Join(query.Platforms, ",")
+		"startDate": q.StartDate,
+		"endDate":   q.EndDate,
 	}
-	if len(query.Territories) > 0 {
-		params["territories"] = strings.Join(query.Territories, ",")
+	if q.GroupBy != "" {
+		params["groupBy"] = q.GroupBy
 	}
-	if len(query.Tracks) > 0 {
-		params["tracks"] = strings.Join(query.Tracks, ",")
-	}
-	if len(query.Metrics) > 0 {
-		params["metrics"] = strings.Join(query.Metrics, ",")
+
+	lists := map[string][]string{
+		"platforms":   q.Platforms,
+		"territories": q.Territories,
+		"tracks":      q.Tracks,
+		"metrics":     q.Metrics,
 	}
+	return params, lists
+}
+
+// GetStreams gets streaming analytics data
+func (a *AnalyticsResource) GetStreams(ctx context.Context, query AnalyticsQuery) (*AnalyticsData, error) {
+	params, lists := query.queryParams()
 
 	var result AnalyticsData
-	err := a.client.Get(ctx, "/analytics/streams", params, &result)
+	err := a.client.getWithLists(ctx, "/analytics/streams", params, lists, exactNumbers{&result})
 	return &result, err
 }
 
 // GetListeners gets listener demographics and behavior data
 func (a *AnalyticsResource) GetListeners(ctx context.Context, query AnalyticsQuery) (map[string]interface{}, error) {
-	params := map[string]string{
-		"startDate": query.StartDate,
-		"endDate":   query.EndDate,
-	}
-	
-	if query.GroupBy != "" {
-		params["groupBy"] = query.GroupBy
-	}
-	if len(query.Platforms) > 0 {
-		params["platforms"] = strings.Join(query.Platforms, ",")
-	}
-	if len(query.Territories) > 0 {
-		params["territories"] = strings.Join(query.Territories, ",")
-	}
-	if len(query.Tracks) > 0 {
-		params["tracks"] = strings.Join(query.Tracks, ",")
-	}
-	if len(query.Metrics) > 0 {
-		params["metrics"] = strings.Join(query.Metrics, ",")
-	}
+	params, lists := query.queryParams()
 
 	var result map[string]interface{}
-	err := a.client.Get(ctx, "/analytics/listeners", params, &result)
+	err := a.client.getWithLists(ctx, "/analytics/listeners", params, lists, exactNumbers{&result})
 	return result, err
 }
 
 // GetPlatformMetrics gets platform-specific performance metrics
 func (a *AnalyticsResource) GetPlatformMetrics(ctx context.Context, query AnalyticsQuery) (map[string]interface{}, error) {
-	params := map[string]string{
-		"startDate": query.StartDate,
-		"endDate":   query.EndDate,
-	}
-	
-	if query.GroupBy != "" {
-		params["groupBy"] = query.GroupBy
-	}
-	if len(query.Platforms) > 0 {
-		params["platforms"] = strings.Join(query.Platforms, ",")
-	}
-	if len(query.Territories) > 0 {
-		params["territories"] = strings.Join(query.Territories, ",")
-	}
-	if len(query.Tracks) > 0 {
-		params["tracks"] = strings.Join(query.Tracks, ",")
-	}
-	if len(query.Metrics) > 0 {
-		params["metrics"] = strings.Join(query.Metrics, ",")
-	}
+	params, lists := query.queryParams()
 
 	var result map[string]interface{}
-	err := a.client.Get(ctx, "/analytics/platform-metrics", params, &result)
+	err := a.client.getWithLists(ctx, "/analytics/platform-metrics", params, lists, exactNumbers{&result})
 	return result, err
 }
 
 // GetGeographicalData gets geographical streaming data
 func (a *AnalyticsResource) GetGeographicalData(ctx context.Context, query AnalyticsQuery) (map[string]interface{}, error) {
-	params := map[string]string{
-		"startDate": query.StartDate,
-		"endDate":   query.EndDate,
-	}
-	
-	if query.GroupBy != "" {
-		params["groupBy"] = query.GroupBy
-	}
-	if len(query.Platforms) > 0 {
-		params["platforms"] = strings.Join(query.Platforms, ",")
-	}
-	if len(query.Territories) > 0 {
-		params["territories"] = strings.Join(query.Territories, ",")
-	}
-	if len(query.Tracks) > 0 {
-		params["tracks"] = strings.Join(query.Tracks, ",")
-	}
-	if len(query.Metrics) > 0 {
-		params["metrics"] = strings.Join(query.Metrics, ",")
-	}
+	params, lists := query.queryParams()
 
 	var result map[string]interface{}
-	err := a.client.Get(ctx, "/analytics/geographical", params, &result)
+	err := a.client.getWithLists(ctx, "/analytics/geographical", params, lists, exactNumbers{&result})
 	return result, err
 }
 
 // GetTrends gets trending analysis and insights
 func (a *AnalyticsResource) GetTrends(ctx context.Context, query AnalyticsQuery) (map[string]interface{}, error) {
-	params := map[string]string{
-		"startDate": query.StartDate,
-		"endDate":   query.EndDate,
-	}
-	
-	if query.GroupBy != "" {
-		params["groupBy"] = query.GroupBy
-	}
-	if len(query.Platforms) > 0 {
-		params["platforms"] = strings.Join(query.Platforms, ",")
-	}
-	if len(query.Territories) > 0 {
-		params["territories"] = strings.Join(query.Territories, ",")
-	}
-	if len(query.Tracks) > 0 {
-		params["tracks"] = strings.Join(query.Tracks, ",")
-	}
-	if len(query.Metrics) > 0 {
-		params["metrics"] = strings.Join(query.Metrics, ",")
-	}
+	params, lists := query.queryParams()
 
 	var result map[string]interface{}
-	err := a.client.Get(ctx, "/analytics/trends", params, &result)
+	err := a.client.getWithLists(ctx, "/analytics/trends", params, lists, exactNumbers{&result})
 	return result, err
 }
 
@@ -219,7 +142,8 @@ func (a *AnalyticsResource) GetRoyaltyReports(ctx context.Context, startDate, en
 		"startDate": startDate,
 		"endDate":   endDate,
 	}
-	
+	lists := make(map[string][]string)
+
 	if options != nil {
 		if options.Currency != "" {
 			params["currency"] = options.Currency
@@ -230,13 +154,11 @@ func (a *AnalyticsResource) GetRoyaltyReports(ctx context.Context, startDate, en
 		if options.GroupBy != "" {
 			params["groupBy"] = options.GroupBy
 		}
-		if len(options.Platforms) > 0 {
-			params["platforms"] = strings.Join(options.Platforms, ",")
-		}
+		lists["platforms"] = options.Platforms
 	}
 
 	var result map[string]interface{}
-	err := a.client.Get(ctx, "/analytics/royalties/reports", params, &result)
+	err := a.client.getWithLists(ctx, "/analytics/royalties/reports", params, lists, exactNumbers{&result})
 	return result, err
 }
 
@@ -247,82 +169,63 @@ func (a *AnalyticsResource) DownloadRoyaltyStatement(ctx context.Context, report
 	}
 
 	var result map[string]interface{}
-	err := a.client.Get(ctx, "/analytics/royalties/statements/"+reportID, params, &result)
+	err := a.client.Get(ctx, "/analytics/royalties/statements/"+reportID, params, exactNumbers{&result})
 	return result, err
 }
 
 // GetRevenueProjections gets revenue projections based on current trends
 func (a *AnalyticsResource) GetRevenueProjections(ctx context.Context, options *RevenueProjectionOptions) (map[string]interface{}, error) {
 	params := make(map[string]string)
-	
+	lists := make(map[string][]string)
+
 	if options != nil {
 		if options.Period != "" {
 			params["period"] = options.Period
 		}
-		if len(options.Tracks) > 0 {
-			params["tracks"] = strings.Join(options.Tracks, ",")
-		}
-		if len(options.Platforms) > 0 {
-			params["platforms"] = strings.Join(options.Platforms, ",")
-		}
+		lists["tracks"] = options.Tracks
+		lists["platforms"] = options.Platforms
 		if options.IncludeConfidenceInterval {
 			params["includeConfidenceInterval"] = "true"
 		}
 	}
 
 	var result map[string]interface{}
-	err := a.client.Get(ctx, "/analytics/royalties/projections", params, &result)
+	err := a.client.getWithLists(ctx, "/analytics/royalties/projections", params, lists, exactNumbers{&result})
 	return result, err
 }
 
 // GetTrackAnalytics gets track performance analytics
 func (a *AnalyticsResource) GetTrackAnalytics(ctx context.Context, trackID string, query AnalyticsQuery) (map[string]interface{}, error) {
-	params := map[string]string{
-		"startDate": query.StartDate,
-		"endDate":   query.EndDate,
-	}
-	
-	if query.GroupBy != "" {
-		params["groupBy"] = query.GroupBy
-	}
-	if len(query.Metrics) > 0 {
-		params["metrics"] = strings.Join(query.Metrics, ",")
-	}
-	if len(query.Platforms) > 0 {
-		params["platforms"] = strings.Join(query.Platforms, ",")
-	}
-	if len(query.Territories) > 0 {
-		params["territories"] = strings.Join(query.Territories, ",")
-	}
+	params, lists := query.queryParams()
+	delete(lists, "tracks")
 
 	var result map[string]interface{}
-	err := a.client.Get(ctx, "/analytics/tracks/"+trackID, params, &result)
+	err := a.client.getWithLists(ctx, "/analytics/tracks/"+trackID, params, lists, exactNumbers{&result})
 	return result, err
 }
 
 // GetRealtimeAnalytics gets real-time analytics dashboard data
 func (a *AnalyticsResource) GetRealtimeAnalytics(ctx context.Context, period string, updateInterval int, metrics []string) (map[string]interface{}, error) {
 	params := make(map[string]string)
-	
+
 	if period != "" {
 		params["period"] = period
 	}
 	if updateInterval > 0 {
 		params["updateInterval"] = strconv.Itoa(updateInterval)
 	}
-	if len(metrics) > 0 {
-		params["metrics"] = strings.Join(metrics, ",")
-	}
+	lists := map[string][]string{"metrics": metrics}
 
 	var result map[string]interface{}
-	err := a.client.Get(ctx, "/analytics/realtime", params, &result)
+	err := a.client.getWithLists(ctx, "/analytics/realtime", params, lists, exactNumbers{&result})
 	return result, err
 }
 
 // GetInsights gets analytics insights and recommendations
 func (a *AnalyticsResource) GetInsights(ctx context.Context, options *InsightsOptions) (map[string]interface{}, error) {
 	params := make(map[string]string)
-	
+	lists := make(map[string][]string)
+
 	if options != nil {
 		if options.Period != "" {
 			params["period"] = options.Period
@@ -333,13 +236,11 @@ func (a *AnalyticsResource) GetInsights(ctx context.Context, options *InsightsOp
 		if options.Focus != "" {
 			params["focus"] = options.Focus
 		}
-		if len(options.Tracks) > 0 {
-			params["tracks"] = strings.Join(options.Tracks, ",")
-		}
+		lists["tracks"] = options.Tracks
 	}
 
 	var result map[string]interface{}
-	err := a.client.Get(ctx, "/analytics/insights", params, &result)
+	err := a.client.getWithLists(ctx, "/analytics/insights", params, lists, exactNumbers{&result})
 	return result, err
 }
 
@@ -355,4 +256,4 @@ func (a *AnalyticsResource) SetupAlert(ctx context.Context, alertConfig AlertCon
 	var result map[string]interface{}
 	err := a.client.Post(ctx, "/analytics/alerts", alertConfig, &result)
 	return result, err
-}
\ No newline at end of file
+}
@@ -0,0 +1,56 @@
+package jewelmusic
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestAnalyticsPreservesLargeCounts(t *testing.T) {
+	const streams int64 = 9876543219
+
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"success":true,"data":{"summary":{"totalStreams":9876543219},` +
+			`"data":[{"metrics":{"streams":9876543219}}],"totalStreams":9876543219}}`))
+	})
+	ctx := context.Background()
+
+	data, err := client.Analytics.GetStreams(ctx, AnalyticsQuery{StartDate: "2024-01-01", EndDate: "2024-01-31"})
+	if err != nil {
+		t.Fatalf("GetStreams: %v", err)
+	}
+	if data.Summary.TotalStreams != streams {
+		t.Errorf("TotalStreams = %d, want %d", data.Summary.TotalStreams, streams)
+	}
+	if got := data.Data[0].Metrics["streams"]; got != streams {
+		t.Errorf("Metrics[streams] = %d, want %d", got, streams)
+	}
+
+	trends, err := client.Analytics.GetTrends(ctx, AnalyticsQuery{StartDate: "2024-01-01", EndDate: "2024-01-31"})
+	if err != nil {
+		t.Fatalf("GetTrends: %v", err)
+	}
+	number, ok := trends["totalStreams"].(json.Number)
+	if !ok {
+		t.Fatalf("totalStreams is %T, want json.Number", trends["totalStreams"])
+	}
+	if got, err := number.Int64(); err != nil || got != streams {
+		t.Errorf("totalStreams = %v (%v), want %d", got, err, streams)
+	}
+}
+
+func TestUntypedResultsKeepFloatNumbers(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		writeData(t, w, map[string]interface{}{"count": 42})
+	})
+
+	var result map[string]interface{}
+	if err := client.Get(context.Background(), "/tracks/stats", nil, &result); err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if _, ok := result["count"].(float64); !ok {
+		t.Errorf("count is %T, want float64", result["count"])
+	}
+}
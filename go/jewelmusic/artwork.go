@@ -0,0 +1,84 @@
+package jewelmusic
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	_ "image/jpeg" // register JPEG for image.DecodeConfig
+	_ "image/png"  // register PNG for image.DecodeConfig
+	"io"
+	"strconv"
+	"strings"
+)
+
+// MinArtworkSize is the minimum width and height, in pixels, of release
+// artwork accepted by distribution platforms
+const MinArtworkSize = 1400
+
+// ArtworkResult represents uploaded artwork. Variants lists the converted
+// copies requested with ArtworkOptions.
+type ArtworkResult struct {
+	URL      string           `json:"url"`
+	Width    int              `json:"width"`
+	Height   int              `json:"height"`
+	Format   string           `json:"format,omitempty"`
+	Variants []ArtworkVariant `json:"variants,omitempty"`
+}
+
+// ArtworkVariant is a copy of uploaded artwork converted for a platform or
+// to a requested size and format
+type ArtworkVariant struct {
+	Platform string `json:"platform,omitempty"`
+	URL      string `json:"url"`
+	Width    int    `json:"width"`
+	Height   int    `json:"height"`
+	Format   string `json:"format"`
+}
+
+// ArtworkOptions requests server-side conversion of uploaded artwork.
+// Platforms produces a variant matching each platform's specification;
+// Sizes and Formats produce variants for each combination.
+type ArtworkOptions struct {
+	Platforms []string
+	Sizes     []int
+	Formats   []string
+}
+
+// metadata converts the options into upload form fields
+func (o *ArtworkOptions) metadata() map[string]string {
+	metadata := make(map[string]string)
+	if len(o.Platforms) > 0 {
+		metadata["platforms"] = strings.Join(o.Platforms, ",")
+	}
+	if len(o.Sizes) > 0 {
+		sizes := make([]string, len(o.Sizes))
+		for i, size := range o.Sizes {
+			sizes[i] = strconv.Itoa(size)
+		}
+		metadata["sizes"] = strings.Join(sizes, ",")
+	}
+	if len(o.Formats) > 0 {
+		metadata["formats"] = strings.Join(o.Formats, ",")
+	}
+	return metadata
+}
+
+// validateArtwork checks that artwork is a square JPEG or PNG image of at
+// least MinArtworkSize pixels. It returns a reader that yields the complete
+// image, including the header read to inspect it.
+func validateArtwork(artwork io.Reader) (io.Reader, error) {
+	var header bytes.Buffer
+	config, _, err := image.DecodeConfig(io.TeeReader(artwork, &header))
+	if err != nil {
+		return nil, &APIError{Code: "INVALID_REQUEST", Message: "Artwork must be a JPEG or PNG image"}
+	}
+
+	switch {
+	case config.Width != config.Height:
+		return nil, &APIError{Code: "INVALID_REQUEST", Message: fmt.Sprintf("Artwork must be square, got %dx%d", config.Width, config.Height)}
+	case config.Width < MinArtworkSize:
+		return nil, &APIError{Code: "INVALID_REQUEST", Message: fmt.Sprintf("Artwork must be at least %dx%d pixels, got %dx%d", MinArtworkSize, MinArtworkSize, config.Width, config.Height)}
+	}
+
+	return io.MultiReader(&header, artwork), nil
+}
@@ -0,0 +1,88 @@
+package jewelmusic
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// AuditEvent describes a completed mutating API call
+type AuditEvent struct {
+	Time       time.Time
+	Method     string
+	Path       string
+	Operation  string
+	ResourceID string
+	StatusCode int
+	RequestID  string
+	Err        error
+}
+
+// WithAuditHook registers hook to be called once after every mutating call
+// (POST, PUT, PATCH, and DELETE), whether it succeeded or not, for example to
+// keep an audit trail. ResourceID is taken from the path, or from the
+// response data for creates. The hook runs synchronously and must be safe
+// for concurrent use.
+func WithAuditHook(hook func(AuditEvent)) ClientOption {
+	return func(c *Client) {
+		c.auditHook = hook
+	}
+}
+
+// auditRecord collects the response details reported to the audit hook
+type auditRecord struct {
+	statusCode int
+	requestID  string
+	data       json.RawMessage
+}
+
+// auditing reports whether a request with method is reported to the audit
+// hook
+func (c *Client) auditing(method string) bool {
+	if c.auditHook == nil {
+		return false
+	}
+	switch method {
+	case "POST", "PUT", "PATCH", "DELETE":
+		return true
+	}
+	return false
+}
+
+// audit reports a completed mutating call to the audit hook
+func (c *Client) audit(method, path string, record auditRecord, err error) {
+	_, operation := describePath(method, path)
+	c.auditHook(AuditEvent{
+		Time:       c.clock.Now(),
+		Method:     method,
+		Path:       path,
+		Operation:  operation,
+		ResourceID: auditResourceID(path, record.data),
+		StatusCode: record.statusCode,
+		RequestID:  record.requestID,
+		Err:        err,
+	})
+}
+
+// auditResourceID returns the last ID segment of path, falling back to the
+// "id" field of the response data
+func auditResourceID(path string, data json.RawMessage) string {
+	if i := strings.IndexByte(path, '?'); i >= 0 {
+		path = path[:i]
+	}
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for i := len(segments) - 1; i > 0; i-- {
+		if strings.IndexFunc(segments[i], unicode.IsDigit) >= 0 {
+			return segments[i]
+		}
+	}
+
+	var created struct {
+		ID string `json:"id"`
+	}
+	if json.Unmarshal(data, &created) == nil {
+		return created.ID
+	}
+	return ""
+}
@@ -0,0 +1,123 @@
+package jewelmusic
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWithAuditHook(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	tests := []struct {
+		name      string
+		call      func(ctx context.Context, client *Client) error
+		wantEvent *AuditEvent
+	}{
+		{
+			name: "create",
+			call: func(ctx context.Context, client *Client) error {
+				_, err := client.Copilot.GenerateMelody(ctx, MelodyOptions{Style: "pop"})
+				return err
+			},
+			wantEvent: &AuditEvent{
+				Method:     "POST",
+				Path:       "/copilot/melody",
+				Operation:  "POST /copilot/melody",
+				ResourceID: "res_1",
+				StatusCode: http.StatusOK,
+				RequestID:  "req_1",
+			},
+		},
+		{
+			name: "upload",
+			call: func(ctx context.Context, client *Client) error {
+				_, err := client.Tracks.Upload(ctx, strings.NewReader("RIFF"), "track.wav", TrackMetadata{Title: "Song", Artist: "Artist"}, nil)
+				return err
+			},
+			wantEvent: &AuditEvent{
+				Method:     "POST",
+				Path:       "/tracks/upload",
+				Operation:  "POST /tracks/upload",
+				ResourceID: "res_1",
+				StatusCode: http.StatusOK,
+				RequestID:  "req_1",
+			},
+		},
+		{
+			name: "delete",
+			call: func(ctx context.Context, client *Client) error {
+				_, err := client.Tracks.Delete(ctx, "trk_1")
+				return err
+			},
+			wantEvent: &AuditEvent{
+				Method:     "DELETE",
+				Path:       "/tracks/trk_1",
+				Operation:  "DELETE /tracks/{id}",
+				ResourceID: "trk_1",
+				StatusCode: http.StatusOK,
+				RequestID:  "req_1",
+			},
+		},
+		{
+			name: "get",
+			call: func(ctx context.Context, client *Client) error {
+				_, err := client.Tracks.Get(ctx, "trk_1")
+				return err
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var events []AuditEvent
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("X-Request-ID", "req_1")
+				writeData(t, w, map[string]interface{}{"id": "res_1", "status": "pending"})
+			}, WithClock(newFakeClock(now)), WithAuditHook(func(event AuditEvent) {
+				events = append(events, event)
+			}))
+
+			if err := tt.call(context.Background(), client); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.wantEvent == nil {
+				if len(events) != 0 {
+					t.Errorf("events = %+v, want none", events)
+				}
+				return
+			}
+			if len(events) != 1 {
+				t.Fatalf("events = %+v, want one", events)
+			}
+			want := *tt.wantEvent
+			want.Time = now
+			if got := events[0]; got != want {
+				t.Errorf("event = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestAuditHookReportsFailures(t *testing.T) {
+	var events []AuditEvent
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Request-ID", "req_2")
+		writeError(t, w, http.StatusNotFound, "NOT_FOUND", "Track not found")
+	}, WithAuditHook(func(event AuditEvent) {
+		events = append(events, event)
+	}))
+
+	_, err := client.Tracks.Update(context.Background(), "trk_404", TrackMetadata{Title: "Song", Artist: "Artist"})
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if len(events) != 1 {
+		t.Fatalf("events = %+v, want one", events)
+	}
+	event := events[0]
+	if event.Method != "PUT" || event.ResourceID != "trk_404" || event.StatusCode != http.StatusNotFound || event.RequestID != "req_2" || event.Err != err {
+		t.Errorf("event = %+v", event)
+	}
+}
@@ -0,0 +1,201 @@
+package jewelmusic
+
+import (
+	"context"
+	"io"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Jitter selects how randomness is applied to backoff delays
+type Jitter int
+
+const (
+	// NoJitter uses the exponential delay as is
+	NoJitter Jitter = iota
+	// FullJitter picks a random delay between zero and the exponential delay
+	FullJitter
+	// EqualJitter keeps half of the exponential delay and randomizes the rest
+	EqualJitter
+)
+
+// BackoffStrategy computes exponentially growing delays between request
+// retries and between status polls
+type BackoffStrategy struct {
+	Base       time.Duration
+	Max        time.Duration
+	Multiplier float64
+	Jitter     Jitter
+}
+
+// DefaultBackoffStrategy returns the strategy used for request retries when
+// none is configured: 1s doubling up to 60s without jitter
+func DefaultBackoffStrategy() BackoffStrategy {
+	return BackoffStrategy{
+		Base:       time.Second,
+		Max:        60 * time.Second,
+		Multiplier: 2,
+		Jitter:     NoJitter,
+	}
+}
+
+// Delay returns the delay before the given zero-based retry attempt
+func (b BackoffStrategy) Delay(attempt int) time.Duration {
+	multiplier := b.Multiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+
+	delay := float64(b.Base) * math.Pow(multiplier, float64(attempt))
+	if b.Max > 0 && delay > float64(b.Max) {
+		delay = float64(b.Max)
+	}
+
+	switch b.Jitter {
+	case FullJitter:
+		delay = rand.Float64() * delay
+	case EqualJitter:
+		delay = delay/2 + rand.Float64()*delay/2
+	}
+	return time.Duration(delay)
+}
+
+// DefaultMaxRetries is the number of times a failed request is retried
+const DefaultMaxRetries = 3
+
+// WithBackoffStrategy sets the backoff strategy used between request retries.
+// The WaitFor helpers use the same strategy between polls when they are
+// called without explicit poll options.
+func WithBackoffStrategy(strategy BackoffStrategy) ClientOption {
+	return func(c *Client) {
+		c.backoff = strategy
+		c.customBackoff = true
+	}
+}
+
+// WithMaxRetries sets how many times failed requests are retried
+func WithMaxRetries(maxRetries int) ClientOption {
+	return func(c *Client) {
+		c.maxRetries = maxRetries
+	}
+}
+
+// pollOptions returns options for the WaitFor helpers, falling back to the
+// backoff strategy set with WithBackoffStrategy when none are given
+func (c *Client) pollOptions(options *PollOptions) *PollOptions {
+	if options != nil {
+		if options.Clock == nil {
+			withClock := *options
+			withClock.Clock = c.clock
+			return &withClock
+		}
+		return options
+	}
+	if !c.customBackoff {
+		return &PollOptions{Clock: c.clock}
+	}
+	return &PollOptions{
+		Interval:    c.backoff.Base,
+		MaxInterval: c.backoff.Max,
+		Multiplier:  math.Max(c.backoff.Multiplier, 1),
+		Jitter:      c.backoff.Jitter,
+		Clock:       c.clock,
+	}
+}
+
+// idempotentMethods are the methods whose requests can be repeated without
+// creating duplicates if the first attempt reached the server
+var idempotentMethods = map[string]bool{
+	"GET":     true,
+	"HEAD":    true,
+	"OPTIONS": true,
+	"PUT":     true,
+	"DELETE":  true,
+}
+
+// shouldRetry reports whether a request outcome is worth retrying. A rate
+// limited request was not processed, so it is retried for any method;
+// transport and server errors only for idempotent methods, since the server
+// may have committed a POST or PATCH before failing.
+func shouldRetry(method string, resp *http.Response, err error) bool {
+	if err == nil && resp.StatusCode == http.StatusTooManyRequests {
+		return true
+	}
+	if !idempotentMethods[method] {
+		return false
+	}
+	return err != nil || resp.StatusCode >= 500
+}
+
+// retryAfter parses a Retry-After header given in seconds
+func retryAfter(resp *http.Response) time.Duration {
+	if resp == nil {
+		return 0
+	}
+	seconds, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+	if err != nil || seconds <= 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// sleepContext waits for d on clock or until ctx is done
+func sleepContext(ctx context.Context, clock Clock, d time.Duration) error {
+	timer := clock.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C():
+		return nil
+	}
+}
+
+// doRequest sends a prepared request, retrying rate limits, and for idempotent
+// methods transport and server errors, with the client's backoff strategy. A Retry-After header
+// on the response takes precedence over the computed delay. Requests whose
+// body cannot be replayed are not retried, and neither are requests once the
+// client's retry budget is exhausted. Headers attached with WithHeaders
+// are added before the first attempt.
+func (c *Client) doRequest(req *http.Request, path string) (*http.Response, error) {
+	ctx := req.Context()
+	applyRequestHeaders(req)
+	for attempt := 0; ; attempt++ {
+		resp, err := c.send(req, path)
+
+		replayable := req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+		if attempt >= c.maxRetries || !replayable || ctx.Err() != nil || !shouldRetry(req.Method, resp, err) {
+			return resp, err
+		}
+		if c.retryBudget != nil && !c.retryBudget.take(c.clock.Now()) {
+			return resp, err
+		}
+
+		delay := c.backoff.Delay(attempt)
+		if after := retryAfter(resp); after > 0 {
+			delay = after
+		}
+		if resp != nil {
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		if err := sleepContext(ctx, c.clock, delay); err != nil {
+			return nil, err
+		}
+
+		next := req.Clone(ctx)
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			next.Body = body
+		}
+		req = next
+	}
+}
@@ -0,0 +1,133 @@
+package jewelmusic
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestBackoffStrategyDelayBounds(t *testing.T) {
+	tests := []struct {
+		name     string
+		jitter   Jitter
+		attempt  int
+		min, max time.Duration
+	}{
+		{"no jitter first attempt", NoJitter, 0, time.Second, time.Second},
+		{"no jitter grows", NoJitter, 2, 4 * time.Second, 4 * time.Second},
+		{"no jitter capped", NoJitter, 10, 10 * time.Second, 10 * time.Second},
+		{"full jitter", FullJitter, 2, 0, 4 * time.Second},
+		{"full jitter capped", FullJitter, 10, 0, 10 * time.Second},
+		{"equal jitter", EqualJitter, 2, 2 * time.Second, 4 * time.Second},
+		{"equal jitter capped", EqualJitter, 10, 5 * time.Second, 10 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			strategy := BackoffStrategy{
+				Base:       time.Second,
+				Max:        10 * time.Second,
+				Multiplier: 2,
+				Jitter:     tt.jitter,
+			}
+			for i := 0; i < 100; i++ {
+				if delay := strategy.Delay(tt.attempt); delay < tt.min || delay > tt.max {
+					t.Fatalf("Delay(%d) = %v, want within [%v, %v]", tt.attempt, delay, tt.min, tt.max)
+				}
+			}
+		})
+	}
+}
+
+func TestPollOptionsDefaults(t *testing.T) {
+	client := NewClient("key")
+	if opts := client.pollOptions(nil).withDefaults(); opts.MaxInterval != defaultPollMaxInterval {
+		t.Errorf("default poll cap = %v, want %v", opts.MaxInterval, defaultPollMaxInterval)
+	}
+
+	strategy := BackoffStrategy{Base: 500 * time.Millisecond, Max: 5 * time.Second, Multiplier: 3, Jitter: FullJitter}
+	client = NewClient("key", WithBackoffStrategy(strategy))
+	opts := client.pollOptions(nil).withDefaults()
+	if opts.Interval != strategy.Base || opts.MaxInterval != strategy.Max || opts.Multiplier != strategy.Multiplier || opts.Jitter != FullJitter {
+		t.Errorf("poll options = %+v, want the configured strategy", opts)
+	}
+}
+
+func TestWaitForUsesBackoffStrategy(t *testing.T) {
+	strategy := BackoffStrategy{Base: time.Second, Max: 4 * time.Second, Multiplier: 2, Jitter: EqualJitter}
+	bounds := []struct{ min, max time.Duration }{
+		{500 * time.Millisecond, time.Second},
+		{time.Second, 2 * time.Second},
+		{2 * time.Second, 4 * time.Second},
+		{2 * time.Second, 4 * time.Second},
+	}
+
+	polls := 0
+	clock := newFakeClock(time.Unix(1700000000, 0))
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		polls++
+		status := "processing"
+		if polls > len(bounds) {
+			status = "completed"
+		}
+		writeData(t, w, map[string]interface{}{"id": "tr_1", "status": status})
+	}, WithClock(clock), WithBackoffStrategy(strategy))
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := client.Transcription.WaitForTranscription(context.Background(), "tr_1", nil)
+		done <- err
+	}()
+
+	for i, want := range bounds {
+		select {
+		case delay := <-clock.waiting:
+			if delay < want.min || delay > want.max {
+				t.Errorf("poll delay %d = %v, want within [%v, %v]", i, delay, want.min, want.max)
+			}
+			clock.Advance(delay)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("poll %d did not wait on the clock", i)
+		}
+	}
+	if err := <-done; err != nil {
+		t.Fatalf("WaitForTranscription: %v", err)
+	}
+}
+
+func TestDoRequestRetries(t *testing.T) {
+	tests := []struct {
+		name         string
+		method       string
+		status       int
+		wantAttempts int32
+	}{
+		{"GET server error", "GET", http.StatusInternalServerError, 3},
+		{"DELETE server error", "DELETE", http.StatusBadGateway, 3},
+		{"POST server error", "POST", http.StatusInternalServerError, 1},
+		{"PATCH server error", "PATCH", http.StatusServiceUnavailable, 1},
+		{"POST rate limited", "POST", http.StatusTooManyRequests, 3},
+		{"GET client error", "GET", http.StatusBadRequest, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts int32
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(&attempts, 1)
+				w.WriteHeader(tt.status)
+			}, WithMaxRetries(2), WithBackoffStrategy(BackoffStrategy{Base: time.Millisecond}))
+
+			var body interface{}
+			if tt.method == "POST" || tt.method == "PATCH" {
+				body = map[string]interface{}{"name": "release"}
+			}
+			client.makeRequest(context.Background(), tt.method, "/resource", body, nil)
+			if got := atomic.LoadInt32(&attempts); got != tt.wantAttempts {
+				t.Errorf("attempts = %d, want %d", got, tt.wantAttempts)
+			}
+		})
+	}
+}
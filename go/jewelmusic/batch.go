@@ -0,0 +1,120 @@
+package jewelmusic
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+// DefaultBatchConcurrency is the number of concurrent requests used by batch
+// helpers when no concurrency is given
+const DefaultBatchConcurrency = 4
+
+// BatchResult holds the outcome of one item of a batch operation
+type BatchResult[T any] struct {
+	ID    string
+	Value T
+	Err   error
+}
+
+// WithoutQuotaCheck disables the account quota check that batch helpers
+// perform before starting
+func WithoutQuotaCheck() ClientOption {
+	return func(c *Client) {
+		c.skipQuotaCheck = true
+	}
+}
+
+// quotaUsage is the usage of one quota as reported by User.GetLimits
+type quotaUsage struct {
+	Monthly   int  `json:"monthly"`
+	Used      int  `json:"used"`
+	Remaining *int `json:"remaining"`
+}
+
+// checkQuota returns a *QuotaExceededError when n more units of resource
+// would exceed the remaining quota reported by User.GetLimits. Resources
+// without a reported quota are not checked.
+func (c *Client) checkQuota(ctx context.Context, resource string, n int) error {
+	if c.skipQuotaCheck || n == 0 {
+		return nil
+	}
+
+	limits, err := c.User.GetLimits(detachCallOptions(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to check quota: %w", err)
+	}
+	raw, ok := limits[resource]
+	if !ok {
+		return nil
+	}
+
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return fmt.Errorf("failed to parse %s quota: %w", resource, err)
+	}
+	var usage quotaUsage
+	if err := json.Unmarshal(data, &usage); err != nil {
+		return fmt.Errorf("failed to parse %s quota: %w", resource, err)
+	}
+
+	var remaining int
+	switch {
+	case usage.Remaining != nil:
+		remaining = *usage.Remaining
+	case usage.Monthly > 0:
+		remaining = usage.Monthly - usage.Used
+	default:
+		return nil
+	}
+
+	if n > remaining {
+		return &QuotaExceededError{Resource: resource, Requested: n, Remaining: remaining}
+	}
+	return nil
+}
+
+// runQuotaBatch is like runBatch but first checks that the batch fits in
+// the remaining quota for resource, rejecting the whole batch otherwise
+func runQuotaBatch[T any](ctx context.Context, c *Client, resource string, ids []string, concurrency int, fn func(ctx context.Context, id string) (T, error)) ([]BatchResult[T], error) {
+	if err := c.checkQuota(ctx, resource, len(ids)); err != nil {
+		return nil, err
+	}
+	return runBatch(ctx, ids, concurrency, fn), nil
+}
+
+// runBatch calls fn for every ID with at most concurrency calls in flight and
+// returns the results in the order of ids. Failures are recorded per item and
+// do not stop the batch; items not started before ctx is done fail with the
+// context error.
+func runBatch[T any](ctx context.Context, ids []string, concurrency int, fn func(ctx context.Context, id string) (T, error)) []BatchResult[T] {
+	if concurrency <= 0 {
+		concurrency = DefaultBatchConcurrency
+	}
+
+	results := make([]BatchResult[T], len(ids))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+
+	for i, id := range ids {
+		results[i].ID = id
+
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			results[i].Err = ctx.Err()
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int, id string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results[i].Value, results[i].Err = fn(ctx, id)
+		}(i, id)
+	}
+	wg.Wait()
+
+	return results
+}
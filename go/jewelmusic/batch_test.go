@@ -0,0 +1,131 @@
+package jewelmusic
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestBatchQuotaCheck(t *testing.T) {
+	tests := []struct {
+		name          string
+		limits        map[string]interface{}
+		opts          []ClientOption
+		wantLimits    int
+		wantSubmitted bool
+		wantRemaining int
+		wantRejected  bool
+	}{
+		{
+			name:          "exceeds remaining quota",
+			limits:        map[string]interface{}{"transcriptions": map[string]interface{}{"monthly": 100, "used": 90, "remaining": 2}},
+			wantLimits:    1,
+			wantRejected:  true,
+			wantRemaining: 2,
+		},
+		{
+			name:          "exceeds monthly quota minus usage",
+			limits:        map[string]interface{}{"transcriptions": map[string]interface{}{"monthly": 10, "used": 9}},
+			wantLimits:    1,
+			wantRejected:  true,
+			wantRemaining: 1,
+		},
+		{
+			name:          "fits remaining quota",
+			limits:        map[string]interface{}{"transcriptions": map[string]interface{}{"remaining": 3}},
+			wantLimits:    1,
+			wantSubmitted: true,
+		},
+		{
+			name:          "no quota reported",
+			limits:        map[string]interface{}{"uploads": map[string]interface{}{"remaining": 0}},
+			wantLimits:    1,
+			wantSubmitted: true,
+		},
+		{
+			name:          "check disabled",
+			limits:        map[string]interface{}{"transcriptions": map[string]interface{}{"remaining": 0}},
+			opts:          []ClientOption{WithoutQuotaCheck()},
+			wantSubmitted: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var limitCalls int
+			var submitted bool
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/v1/user/limits":
+					limitCalls++
+					writeData(t, w, tt.limits)
+				case "/v1/transcription/batch":
+					submitted = true
+					writeData(t, w, map[string]interface{}{"jobs": []map[string]interface{}{}})
+				default:
+					t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+				}
+			}, tt.opts...)
+
+			_, err := client.Transcription.CreateBatch(context.Background(), []string{"trk_1", "trk_2", "trk_3"}, nil)
+			if limitCalls != tt.wantLimits || submitted != tt.wantSubmitted {
+				t.Errorf("limit checks = %d, submitted = %t; want %d, %t", limitCalls, submitted, tt.wantLimits, tt.wantSubmitted)
+			}
+			if !tt.wantRejected {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+
+			if !errors.Is(err, ErrQuotaExceeded) {
+				t.Fatalf("err = %v, want ErrQuotaExceeded", err)
+			}
+			var quotaErr *QuotaExceededError
+			if !errors.As(err, &quotaErr) {
+				t.Fatalf("err = %T, want *QuotaExceededError", err)
+			}
+			want := QuotaExceededError{Resource: "transcriptions", Requested: 3, Remaining: tt.wantRemaining}
+			if *quotaErr != want {
+				t.Errorf("quota error = %+v, want %+v", *quotaErr, want)
+			}
+		})
+	}
+}
+
+func TestUploadTrackPathsQuotaCheck(t *testing.T) {
+	dir := t.TempDir()
+	var paths []string
+	for _, name := range []string{"a.wav", "b.wav", "c.wav"} {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte("RIFF"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		paths = append(paths, path)
+	}
+
+	var uploads int
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/user/limits":
+			writeData(t, w, map[string]interface{}{"uploads": map[string]interface{}{"monthly": 50, "used": 48}})
+		case "/v1/analysis/upload":
+			uploads++
+			writeData(t, w, map[string]interface{}{"id": "ana_1", "status": "pending"})
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	})
+
+	results, err := client.Analysis.UploadTrackPaths(context.Background(), paths, nil, 2)
+	var quotaErr *QuotaExceededError
+	if !errors.As(err, &quotaErr) || quotaErr.Resource != "uploads" || quotaErr.Requested != 3 || quotaErr.Remaining != 2 {
+		t.Fatalf("err = %v, want a quota error for 3 uploads with 2 remaining", err)
+	}
+	if results != nil || uploads != 0 {
+		t.Errorf("results = %v after %d uploads, want the batch rejected before starting", results, uploads)
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"sort"
+	"sync"
 	"time"
 )
 
@@ -12,17 +14,41 @@ import (
 type Client struct {
 	apiKey     string
 	baseURL    string
+	basePath   string
+	apiVersion string
 	httpClient *http.Client
-	
+
+	listParamStyle ListParamStyle
+	observers      []RequestObserver
+	tracer         *httpTracer
+	signingSecret  string
+	backoff        BackoffStrategy
+	customBackoff  bool
+	maxRetries     int
+	retryBudget    *retryBudget
+	clock          Clock
+	authHeader     string
+	authFormat     string
+	limiter        *rateLimiter
+	requestSlots   chan struct{}
+	metadataSchema *MetadataSchema
+	skipQuotaCheck bool
+	auditHook      func(AuditEvent)
+
+	defaultAnalysisOptions      *AnalysisOptions
+	defaultQualityCheckOptions  *QualityCheckOptions
+	defaultUploadOptions        *UploadOptions
+	defaultTranscriptionOptions *TranscriptionOptions
+
 	// Resource managers
-	Copilot      *CopilotResource
-	Analysis     *AnalysisResource
-	Distribution *DistributionResource
+	Copilot       *CopilotResource
+	Analysis      *AnalysisResource
+	Distribution  *DistributionResource
 	Transcription *TranscriptionResource
-	Tracks       *TracksResource
-	Analytics    *AnalyticsResource
-	User         *UserResource
-	Webhooks     *WebhooksResource
+	Tracks        *TracksResource
+	Analytics     *AnalyticsResource
+	User          *UserResource
+	Webhooks      *WebhooksResource
 }
 
 // ClientOption configures the client
@@ -31,19 +57,29 @@ type ClientOption func(*Client)
 // NewClient creates a new JewelMusic API client
 func NewClient(apiKey string, opts ...ClientOption) *Client {
 	c := &Client{
-		apiKey:  apiKey,
-		baseURL: "https://api.jewelmusic.art",
+		apiKey:     apiKey,
+		baseURL:    "https://api.jewelmusic.art",
+		apiVersion: "v1",
+		backoff:    DefaultBackoffStrategy(),
+		maxRetries: DefaultMaxRetries,
+		clock:      SystemClock,
 		httpClient: &http.Client{
 			Timeout: 30 * time.Second,
 		},
 	}
-	
+
 	// Apply options
 	for _, opt := range opts {
 		opt(c)
 	}
-	
-	// Initialize resource managers
+
+	c.initResources()
+
+	return c
+}
+
+// initResources creates the resource managers bound to the client
+func (c *Client) initResources() {
 	c.Copilot = &CopilotResource{client: c}
 	c.Analysis = &AnalysisResource{client: c}
 	c.Distribution = &DistributionResource{client: c}
@@ -52,8 +88,35 @@ func NewClient(apiKey string, opts ...ClientOption) *Client {
 	c.Analytics = &AnalyticsResource{client: c}
 	c.User = &UserResource{client: c}
 	c.Webhooks = &WebhooksResource{client: c}
-	
-	return c
+}
+
+// With returns a copy of the client with opts applied on top of its current
+// configuration, for example to use a different API key per tenant. The
+// copy shares the underlying HTTP client, retry budget, and concurrency limit
+// with the original, which is left unchanged. Rate limits are tracked per
+// API key, so a copy with a different key gets its own rate limiter.
+func (c *Client) With(opts ...ClientOption) *Client {
+	clone := *c
+	clone.observers = append([]RequestObserver(nil), c.observers...)
+
+	for _, opt := range opts {
+		opt(&clone)
+	}
+	if clone.limiter != nil && clone.limiter == c.limiter && clone.apiKey != c.apiKey {
+		clone.limiter = &rateLimiter{}
+	}
+
+	clone.initResources()
+
+	return &clone
+}
+
+// WithAPIKey sets the API key, typically to override it in a client
+// created with Client.With
+func WithAPIKey(apiKey string) ClientOption {
+	return func(c *Client) {
+		c.apiKey = apiKey
+	}
 }
 
 // WithEnvironment sets the environment (production, sandbox)
@@ -77,6 +140,40 @@ func WithBaseURL(baseURL string) ClientOption {
 	}
 }
 
+// WithAuthHeader sends the API key in a custom header instead of
+// "Authorization: Bearer <key>". format is a fmt format string with a single
+// %s verb for the key, e.g. WithAuthHeader("X-API-Key", "%s"); an empty
+// format sends the key as is.
+func WithAuthHeader(name, format string) ClientOption {
+	return func(c *Client) {
+		c.authHeader = name
+		c.authFormat = format
+	}
+}
+
+// WithBasePath sets a path prefix inserted between the base URL and the API
+// version, for APIs mounted under a sub-path behind a reverse proxy
+func WithBasePath(basePath string) ClientOption {
+	return func(c *Client) {
+		c.basePath = basePath
+	}
+}
+
+// WithAPIVersion sets the API version path segment (default "v1")
+func WithAPIVersion(version string) ClientOption {
+	return func(c *Client) {
+		c.apiVersion = version
+	}
+}
+
+// WithListParamStyle sets how slice query parameters are encoded
+// (comma-separated by default)
+func WithListParamStyle(style ListParamStyle) ClientOption {
+	return func(c *Client) {
+		c.listParamStyle = style
+	}
+}
+
 // WithHTTPClient sets a custom HTTP client
 func WithHTTPClient(httpClient *http.Client) ClientOption {
 	return func(c *Client) {
@@ -94,13 +191,98 @@ type PingResponse struct {
 // Ping tests the API connection and authentication
 func (c *Client) Ping(ctx context.Context) (*PingResponse, error) {
 	var response PingResponse
-	err := c.makeRequest(ctx, "GET", "/v1/ping", nil, &response)
+	err := c.makeRequest(ctx, "GET", "/ping", nil, &response)
 	if err != nil {
 		return nil, err
 	}
 	return &response, nil
 }
 
+// Subsystems reported by Health
+const (
+	SubsystemUpload       = "upload"
+	SubsystemAnalysis     = "analysis"
+	SubsystemDistribution = "distribution"
+	SubsystemCopilot      = "copilot"
+)
+
+// HealthReport represents the health of the API and its subsystems
+type HealthReport struct {
+	Status     string                     `json:"status"`
+	Timestamp  string                     `json:"timestamp"`
+	Subsystems map[string]SubsystemHealth `json:"subsystems"`
+}
+
+// SubsystemHealth represents the health of one subsystem
+type SubsystemHealth struct {
+	Status    string `json:"status"`
+	LatencyMs int    `json:"latencyMs,omitempty"`
+	Message   string `json:"message,omitempty"`
+}
+
+// Healthy reports whether the subsystem is operational
+func (h SubsystemHealth) Healthy() bool {
+	return h.Status == "ok" || h.Status == "healthy"
+}
+
+// Unhealthy returns the names of the subsystems that are not operational
+func (r *HealthReport) Unhealthy() []string {
+	var names []string
+	for name, health := range r.Subsystems {
+		if !health.Healthy() {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Health reports the health of the upload, analysis, distribution, and
+// copilot subsystems, complementing Ping
+func (c *Client) Health(ctx context.Context) (*HealthReport, error) {
+	var response HealthReport
+	err := c.makeRequest(ctx, "GET", "/health", nil, &response)
+	if err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// PingResult reports the health of one environment checked by MultiPing
+type PingResult struct {
+	BaseURL  string
+	Healthy  bool
+	Latency  time.Duration
+	Response *PingResponse
+	Err      error
+}
+
+// MultiPing pings several clients concurrently, for example one per
+// environment, and returns their results in the same order
+func MultiPing(ctx context.Context, clients ...*Client) []PingResult {
+	results := make([]PingResult, len(clients))
+
+	var wg sync.WaitGroup
+	for i, client := range clients {
+		wg.Add(1)
+		go func(i int, client *Client) {
+			defer wg.Done()
+			start := time.Now()
+			response, err := client.Ping(ctx)
+			results[i] = PingResult{
+				BaseURL:  client.baseURL,
+				Healthy:  err == nil && response.Success,
+				Latency:  time.Since(start),
+				Response: response,
+				Err:      err,
+			}
+		}(i, client)
+	}
+	wg.Wait()
+
+	return results
+}
+
 // makeRequest is a helper method for making HTTP requests
 func (c *Client) makeRequest(ctx context.Context, method, path string, body interface{}, result interface{}) error {
 	// Implementation will be added in the next step
@@ -115,4 +297,4 @@ type TranscriptionResource struct{ client *Client }
 type TracksResource struct{ client *Client }
 type AnalyticsResource struct{ client *Client }
 type UserResource struct{ client *Client }
-type WebhooksResource struct{ client *Client }
\ No newline at end of file
+type WebhooksResource struct{ client *Client }
@@ -0,0 +1,282 @@
+package jewelmusic
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newTestClient starts a server running handler and returns a client that
+// sends its requests there
+func newTestClient(t *testing.T, handler http.HandlerFunc, opts ...ClientOption) *Client {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return NewClient("jml_live_test", append([]ClientOption{WithBaseURL(server.URL)}, opts...)...)
+}
+
+// writeData writes data in the API's success envelope
+func writeData(t *testing.T, w http.ResponseWriter, data interface{}) {
+	t.Helper()
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(map[string]interface{}{"success": true, "data": data}); err != nil {
+		t.Errorf("encoding response: %v", err)
+	}
+}
+
+// writeError writes an API error with the given status
+func writeError(t *testing.T, w http.ResponseWriter, status int, code, message string) {
+	t.Helper()
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	body := map[string]interface{}{"success": false, "error": map[string]interface{}{"code": code, "message": message}}
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		t.Errorf("encoding response: %v", err)
+	}
+}
+
+func TestClientWith(t *testing.T) {
+	var keys []string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		keys = append(keys, r.Header.Get("Authorization"))
+		writeData(t, w, map[string]interface{}{})
+	}, WithRateLimiter())
+
+	tenant := client.With(WithAPIKey("jml_live_tenant"))
+	ctx := context.Background()
+	if err := tenant.Get(ctx, "/user/profile", nil, nil); err != nil {
+		t.Fatalf("clone request: %v", err)
+	}
+	if err := client.Get(ctx, "/user/profile", nil, nil); err != nil {
+		t.Fatalf("original request: %v", err)
+	}
+
+	want := []string{"Bearer jml_live_tenant", "Bearer jml_live_test"}
+	if strings.Join(keys, ",") != strings.Join(want, ",") {
+		t.Errorf("Authorization headers = %v, want %v", keys, want)
+	}
+	if tenant.limiter == client.limiter {
+		t.Error("clone with a different API key shares the rate limiter")
+	}
+	if same := client.With(WithMaxRetries(0)); same.limiter != client.limiter {
+		t.Error("clone with the same API key does not share the rate limiter")
+	}
+	if tenant.httpClient != client.httpClient {
+		t.Error("clone does not share the HTTP client")
+	}
+}
+
+func TestWithAuthHeader(t *testing.T) {
+	tests := []struct {
+		name       string
+		opts       []ClientOption
+		wantHeader string
+		wantValue  string
+	}{
+		{"default bearer", nil, "Authorization", "Bearer jml_live_test"},
+		{"custom header", []ClientOption{WithAuthHeader("X-API-Key", "%s")}, "X-API-Key", "jml_live_test"},
+		{"custom format", []ClientOption{WithAuthHeader("X-Auth", "Key %s")}, "X-Auth", "Key jml_live_test"},
+		{"empty format", []ClientOption{WithAuthHeader("X-Api-Key", "")}, "X-Api-Key", "jml_live_test"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var header http.Header
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				header = r.Header.Clone()
+				writeData(t, w, map[string]interface{}{})
+			}, tt.opts...)
+
+			if err := client.Get(context.Background(), "/user/profile", nil, nil); err != nil {
+				t.Fatalf("Get: %v", err)
+			}
+			if got := header.Get(tt.wantHeader); got != tt.wantValue {
+				t.Errorf("%s = %q, want %q", tt.wantHeader, got, tt.wantValue)
+			}
+			if tt.wantHeader != "Authorization" && header.Get("Authorization") != "" {
+				t.Errorf("Authorization still sent: %q", header.Get("Authorization"))
+			}
+		})
+	}
+}
+
+// writePage writes one page of a paginated list
+func writePage(t *testing.T, w http.ResponseWriter, items interface{}, page, totalPages, total int) {
+	t.Helper()
+	writeData(t, w, map[string]interface{}{
+		"items": items,
+		"pagination": map[string]interface{}{
+			"page":       page,
+			"total":      total,
+			"totalPages": totalPages,
+		},
+	})
+}
+
+func TestBuildURL(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []ClientOption
+		path string
+		want string
+	}{
+		{"default", nil, "/tracks", "https://api.jewelmusic.art/v1/tracks"},
+		{"base path", []ClientOption{WithBaseURL("https://gateway.example.com"), WithBasePath("/jewelmusic")}, "/tracks", "https://gateway.example.com/jewelmusic/v1/tracks"},
+		{"slashes trimmed", []ClientOption{WithBaseURL("https://gateway.example.com/"), WithBasePath("/api/music/")}, "tracks/trk_1", "https://gateway.example.com/api/music/v1/tracks/trk_1"},
+		{"custom version", []ClientOption{WithBasePath("proxy"), WithAPIVersion("v2")}, "/tracks", "https://api.jewelmusic.art/proxy/v2/tracks"},
+		{"no version", []ClientOption{WithAPIVersion("")}, "/tracks", "https://api.jewelmusic.art/tracks"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewClient("key", tt.opts...)
+			if got := client.buildURL(tt.path); got != tt.want {
+				t.Errorf("buildURL(%q) = %q, want %q", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMultiPing(t *testing.T) {
+	const slowDelay = 50 * time.Millisecond
+
+	pingHandler := func(delay time.Duration, version string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/v1/ping" {
+				t.Errorf("path = %s", r.URL.Path)
+			}
+			time.Sleep(delay)
+			writeData(t, w, map[string]interface{}{"success": true, "version": version})
+		}
+	}
+	sandbox := newTestClient(t, pingHandler(0, "sandbox"))
+	production := newTestClient(t, pingHandler(slowDelay, "production"))
+	down := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		writeError(t, w, http.StatusServiceUnavailable, "UNAVAILABLE", "Maintenance")
+	}, WithMaxRetries(0))
+
+	results := MultiPing(context.Background(), sandbox, production, down)
+	if len(results) != 3 {
+		t.Fatalf("results = %d, want 3", len(results))
+	}
+
+	for i, want := range []struct {
+		client  *Client
+		healthy bool
+		version string
+	}{
+		{sandbox, true, "sandbox"},
+		{production, true, "production"},
+		{down, false, ""},
+	} {
+		result := results[i]
+		if result.BaseURL != want.client.baseURL {
+			t.Errorf("result %d BaseURL = %s, want %s", i, result.BaseURL, want.client.baseURL)
+		}
+		if result.Healthy != want.healthy {
+			t.Errorf("result %d Healthy = %v, want %v (err %v)", i, result.Healthy, want.healthy, result.Err)
+		}
+		if want.healthy && result.Response.Version != want.version {
+			t.Errorf("result %d version = %q, want %q", i, result.Response.Version, want.version)
+		}
+		if !want.healthy && result.Err == nil {
+			t.Errorf("result %d has no error", i)
+		}
+	}
+
+	if results[1].Latency < slowDelay {
+		t.Errorf("slow environment latency = %v, want at least %v", results[1].Latency, slowDelay)
+	}
+	if results[0].Latency >= results[1].Latency {
+		t.Errorf("fast environment latency %v not below slow %v", results[0].Latency, results[1].Latency)
+	}
+}
+
+func TestHealth(t *testing.T) {
+	tests := []struct {
+		name          string
+		payload       string
+		wantStatus    string
+		wantUnhealthy []string
+	}{
+		{
+			name: "all operational",
+			payload: `{
+				"status": "ok",
+				"timestamp": "2024-01-01T00:00:00Z",
+				"subsystems": {
+					"upload": {"status": "ok", "latencyMs": 12},
+					"analysis": {"status": "healthy", "latencyMs": 40},
+					"distribution": {"status": "ok"},
+					"copilot": {"status": "ok", "latencyMs": 95}
+				}
+			}`,
+			wantStatus: "ok",
+		},
+		{
+			name: "degraded subsystems",
+			payload: `{
+				"status": "degraded",
+				"timestamp": "2024-01-01T00:00:00Z",
+				"subsystems": {
+					"upload": {"status": "ok", "latencyMs": 12},
+					"analysis": {"status": "degraded", "latencyMs": 2400, "message": "Queue backlog"},
+					"distribution": {"status": "down", "message": "Partner outage"},
+					"copilot": {"status": "ok"}
+				}
+			}`,
+			wantStatus:    "degraded",
+			wantUnhealthy: []string{SubsystemAnalysis, SubsystemDistribution},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodGet || r.URL.Path != "/v1/health" {
+					t.Errorf("request = %s %s, want GET /v1/health", r.Method, r.URL.Path)
+				}
+				writeData(t, w, json.RawMessage(tt.payload))
+			})
+
+			report, err := client.Health(context.Background())
+			if err != nil {
+				t.Fatalf("Health: %v", err)
+			}
+			if report.Status != tt.wantStatus {
+				t.Errorf("status = %q, want %q", report.Status, tt.wantStatus)
+			}
+			for _, name := range []string{SubsystemUpload, SubsystemAnalysis, SubsystemDistribution, SubsystemCopilot} {
+				if _, ok := report.Subsystems[name]; !ok {
+					t.Errorf("subsystem %q missing from %+v", name, report.Subsystems)
+				}
+			}
+			if got := report.Unhealthy(); !reflect.DeepEqual(got, tt.wantUnhealthy) {
+				t.Errorf("unhealthy = %v, want %v", got, tt.wantUnhealthy)
+			}
+		})
+	}
+}
+
+func TestHealthSubsystemDetails(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		writeData(t, w, json.RawMessage(`{"status": "degraded", "subsystems": {"analysis": {"status": "degraded", "latencyMs": 2400, "message": "Queue backlog"}}}`))
+	})
+
+	report, err := client.Health(context.Background())
+	if err != nil {
+		t.Fatalf("Health: %v", err)
+	}
+	want := SubsystemHealth{Status: "degraded", LatencyMs: 2400, Message: "Queue backlog"}
+	if got := report.Subsystems[SubsystemAnalysis]; got != want {
+		t.Errorf("analysis = %+v, want %+v", got, want)
+	}
+	if want.Healthy() {
+		t.Error("degraded subsystem reported healthy")
+	}
+}
@@ -0,0 +1,70 @@
+package jewelmusic
+
+import "time"
+
+// Clock is the source of time used for retry and poll delays, the retry
+// budget, audit timestamps, request and webhook signature timestamps, and
+// webhook signature tolerance checks. Tests can supply a fake clock to
+// control time.
+type Clock interface {
+	Now() time.Time
+	NewTimer(d time.Duration) Timer
+}
+
+// Timer is a timer created by a Clock. Like time.Timer, it sends the current
+// time on its channel once it fires, and stopping it releases its resources.
+type Timer interface {
+	C() <-chan time.Time
+	Stop() bool
+}
+
+// SystemClock is the Clock backed by the time package, used by default
+var SystemClock Clock = systemClock{}
+
+// systemClock implements Clock with the time package
+type systemClock struct{}
+
+// Now implements Clock
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// NewTimer implements Clock
+func (systemClock) NewTimer(d time.Duration) Timer {
+	return systemTimer{time.NewTimer(d)}
+}
+
+// systemTimer implements Timer with a time.Timer
+type systemTimer struct {
+	timer *time.Timer
+}
+
+// C implements Timer
+func (t systemTimer) C() <-chan time.Time {
+	return t.timer.C
+}
+
+// Stop implements Timer
+func (t systemTimer) Stop() bool {
+	return t.timer.Stop()
+}
+
+// WithClock sets the clock used for retry delays, the retry budget, the rate
+// limiter, audit timestamps, request signature timestamps, and the default
+// poll options of the WaitFor helpers
+func WithClock(clock Clock) ClientOption {
+	return func(c *Client) {
+		if clock == nil {
+			clock = SystemClock
+		}
+		c.clock = clock
+	}
+}
+
+// clockOrSystem returns clock, or SystemClock if clock is nil
+func clockOrSystem(clock Clock) Clock {
+	if clock == nil {
+		return SystemClock
+	}
+	return clock
+}
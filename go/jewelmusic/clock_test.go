@@ -0,0 +1,265 @@
+package jewelmusic
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeClock is a Clock whose time only moves when advanced. Timers fire once
+// the clock has been advanced past their deadline.
+type fakeClock struct {
+	mu      sync.Mutex
+	now     time.Time
+	timers  []*fakeTimer
+	waiting chan time.Duration
+}
+
+func newFakeClock(now time.Time) *fakeClock {
+	return &fakeClock{now: now, waiting: make(chan time.Duration, 100)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) NewTimer(d time.Duration) Timer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	timer := &fakeTimer{clock: c, deadline: c.now.Add(d), c: make(chan time.Time, 1)}
+	c.timers = append(c.timers, timer)
+	c.waiting <- d
+	return timer
+}
+
+// Advance moves the clock forward by d and fires the timers that are due
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+	pending := c.timers[:0]
+	for _, timer := range c.timers {
+		if timer.deadline.After(c.now) {
+			pending = append(pending, timer)
+		} else {
+			timer.c <- c.now
+		}
+	}
+	c.timers = pending
+}
+
+// activeTimers returns the number of timers that have neither fired nor
+// been stopped
+func (c *fakeClock) activeTimers() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.timers)
+}
+
+type fakeTimer struct {
+	clock    *fakeClock
+	deadline time.Time
+	c        chan time.Time
+}
+
+func (t *fakeTimer) C() <-chan time.Time {
+	return t.c
+}
+
+func (t *fakeTimer) Stop() bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	for i, timer := range t.clock.timers {
+		if timer == t {
+			t.clock.timers = append(t.clock.timers[:i], t.clock.timers[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+func TestVerifySignatureWithClock(t *testing.T) {
+	payload := []byte(`{"event":"track.uploaded"}`)
+	const secret = "whsec_test"
+	signedAt := time.Unix(1700000000, 0)
+	signature := CreateSignatureWithSchemes(payload, secret, signedAt.Unix(), SchemeHMACSHA256)
+
+	tests := []struct {
+		name string
+		now  time.Time
+		want bool
+	}{
+		{"at signing time", signedAt, true},
+		{"within tolerance", signedAt.Add(299 * time.Second), true},
+		{"past tolerance", signedAt.Add(301 * time.Second), false},
+		{"clock behind", signedAt.Add(-301 * time.Second), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := newFakeClock(tt.now)
+			if got := VerifySignatureWithClock(clock, payload, signature, secret, 300); got != tt.want {
+				t.Errorf("VerifySignatureWithClock = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRequestSignatureUsesClock(t *testing.T) {
+	const secret = "client_secret"
+	signedAt := time.Unix(1700000000, 0)
+
+	var header, uri string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Get(ClientSignatureHeader)
+		uri = r.URL.RequestURI()
+		writeData(t, w, map[string]interface{}{})
+	}, WithRequestSigner(secret), WithClock(newFakeClock(signedAt)))
+
+	if err := client.Get(context.Background(), "/tracks", nil, nil); err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+
+	ts := signedAt.Unix()
+	if want := CreateSignature(requestSigningPayload("GET", uri, nil), secret, &ts); header != want {
+		t.Errorf("signature = %q, want %q", header, want)
+	}
+}
+
+func TestCreateSignatureWithClock(t *testing.T) {
+	payload := []byte(`{"event":"track.uploaded"}`)
+	const secret = "whsec_test"
+	signedAt := time.Unix(1700000000, 0)
+
+	signature := CreateSignatureWithClock(newFakeClock(signedAt), payload, secret)
+	ts := signedAt.Unix()
+	if want := CreateSignature(payload, secret, &ts); signature != want {
+		t.Errorf("signature = %q, want %q", signature, want)
+	}
+	if !VerifyWithSecretsAndClock(newFakeClock(signedAt.Add(time.Minute)), payload, signature, secret, "whsec_new") {
+		t.Error("signature from the old secret rejected within tolerance")
+	}
+	if VerifyWithSecretsAndClock(newFakeClock(signedAt.Add(time.Hour)), payload, signature, secret, "whsec_new") {
+		t.Error("signature accepted past tolerance")
+	}
+}
+
+func TestWebhookParsingUsesClock(t *testing.T) {
+	const secret = "whsec_test"
+	payload := []byte(`{"id":"evt_1","type":"track.uploaded","data":{}}`)
+	signedAt := time.Unix(1700000000, 0)
+	signature := CreateSignatureWithClock(newFakeClock(signedAt), payload, secret)
+
+	newRequest := func() *http.Request {
+		r := httptest.NewRequest(http.MethodPost, "/webhooks", bytes.NewReader(payload))
+		r.Header.Set(SignatureHeader, signature)
+		return r
+	}
+
+	tests := []struct {
+		name       string
+		now        time.Time
+		wantErr    error
+		wantStatus int
+	}{
+		{"within tolerance", signedAt.Add(4 * time.Minute), nil, http.StatusOK},
+		{"past tolerance", signedAt.Add(6 * time.Minute), ErrInvalidSignature, http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := newFakeClock(tt.now)
+			if _, err := ParseRequestWithClock(clock, newRequest(), secret); !errors.Is(err, tt.wantErr) {
+				t.Errorf("ParseRequestWithClock error = %v, want %v", err, tt.wantErr)
+			}
+
+			handler := NewWebhookHandler(secret)
+			handler.SetClock(clock)
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, newRequest())
+			if w.Code != tt.wantStatus {
+				t.Errorf("handler status = %d, want %d", w.Code, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestRetryDelaysUseClock(t *testing.T) {
+	clock := newFakeClock(time.Unix(1700000000, 0))
+	attempts := 0
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		writeData(t, w, map[string]interface{}{})
+	}, WithClock(clock), WithBackoffStrategy(BackoffStrategy{Base: time.Second, Max: time.Minute, Multiplier: 2}))
+
+	done := make(chan error, 1)
+	go func() {
+		done <- client.Get(context.Background(), "/tracks", nil, nil)
+	}()
+
+	for _, want := range []time.Duration{time.Second, 2 * time.Second} {
+		select {
+		case got := <-clock.waiting:
+			if got != want {
+				t.Errorf("retry delay = %v, want %v", got, want)
+			}
+			clock.Advance(got)
+		case <-time.After(5 * time.Second):
+			t.Fatal("request did not wait on the clock")
+		}
+	}
+	if err := <-done; err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if attempts != 3 {
+		t.Errorf("attempts = %d, want 3", attempts)
+	}
+}
+
+func TestSleepContextStopsTimerOnCancel(t *testing.T) {
+	clock := newFakeClock(time.Unix(1700000000, 0))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := sleepContext(ctx, clock, time.Hour); err != context.Canceled {
+		t.Fatalf("sleepContext error = %v, want %v", err, context.Canceled)
+	}
+	if n := clock.activeTimers(); n != 0 {
+		t.Errorf("%d timers left running after cancellation", n)
+	}
+}
+
+func TestRateLimiterUsesClock(t *testing.T) {
+	clock := newFakeClock(time.Unix(1700000000, 0))
+	limiter := &rateLimiter{}
+	limiter.update(clock.Now(), 10, 0, 30)
+
+	done := make(chan error, 1)
+	go func() {
+		done <- limiter.wait(context.Background(), clock)
+	}()
+
+	select {
+	case got := <-clock.waiting:
+		if got != 30*time.Second {
+			t.Errorf("wait = %v, want 30s", got)
+		}
+		clock.Advance(got)
+	case <-time.After(5 * time.Second):
+		t.Fatal("limiter did not wait on the clock")
+	}
+	if err := <-done; err != nil {
+		t.Fatalf("wait: %v", err)
+	}
+}
@@ -0,0 +1,55 @@
+package jewelmusic
+
+import (
+	"context"
+	"io"
+	"sync"
+)
+
+// WithMaxConcurrentRequests limits the number of requests the client has in
+// flight at once. Further requests block until a slot frees up or their
+// context is done. A request holds its slot until its response body is
+// closed.
+func WithMaxConcurrentRequests(n int) ClientOption {
+	return func(c *Client) {
+		if n > 0 {
+			c.requestSlots = make(chan struct{}, n)
+		} else {
+			c.requestSlots = nil
+		}
+	}
+}
+
+// acquireSlot blocks until a request slot is available
+func (c *Client) acquireSlot(ctx context.Context) error {
+	if c.requestSlots == nil {
+		return nil
+	}
+	select {
+	case c.requestSlots <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// releaseSlot frees a request slot taken by acquireSlot
+func (c *Client) releaseSlot() {
+	if c.requestSlots != nil {
+		<-c.requestSlots
+	}
+}
+
+// slotBody releases a request slot when the response body is closed
+type slotBody struct {
+	io.ReadCloser
+	once    sync.Once
+	release func()
+}
+
+// Close implements io.Closer
+func (b *slotBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(b.release)
+	return err
+}
@@ -0,0 +1,91 @@
+package jewelmusic
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWithMaxConcurrentRequests(t *testing.T) {
+	tests := []struct {
+		name    string
+		limit   int
+		wantMax int32
+	}{
+		{"one", 1, 1},
+		{"three", 3, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var inFlight, maxInFlight int32
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				n := atomic.AddInt32(&inFlight, 1)
+				defer atomic.AddInt32(&inFlight, -1)
+				for {
+					m := atomic.LoadInt32(&maxInFlight)
+					if n <= m || atomic.CompareAndSwapInt32(&maxInFlight, m, n) {
+						break
+					}
+				}
+				time.Sleep(10 * time.Millisecond)
+				writeData(t, w, map[string]interface{}{})
+			}, WithMaxConcurrentRequests(tt.limit))
+
+			var wg sync.WaitGroup
+			for i := 0; i < 10; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					if err := client.Get(context.Background(), "/tracks", nil, nil); err != nil {
+						t.Errorf("Get: %v", err)
+					}
+				}()
+			}
+			wg.Wait()
+
+			if maxInFlight > tt.wantMax {
+				t.Errorf("max in flight = %d, want at most %d", maxInFlight, tt.wantMax)
+			}
+		})
+	}
+}
+
+func TestMaxConcurrentRequestsHonorsContext(t *testing.T) {
+	release := make(chan struct{})
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		writeData(t, w, map[string]interface{}{})
+	}, WithMaxConcurrentRequests(1))
+
+	done := make(chan error, 1)
+	go func() {
+		done <- client.Get(context.Background(), "/tracks", nil, nil)
+	}()
+
+	// Wait until the first request holds the only slot
+	for deadline := time.Now().Add(5 * time.Second); len(client.requestSlots) == 0; {
+		if time.Now().After(deadline) {
+			t.Fatal("first request never took a slot")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := client.Get(ctx, "/tracks", nil, nil); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("blocked request error = %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	close(release)
+	if err := <-done; err != nil {
+		t.Fatalf("first request: %v", err)
+	}
+	if n := len(client.requestSlots); n != 0 {
+		t.Errorf("%d slots still held after all requests finished", n)
+	}
+}
@@ -0,0 +1,225 @@
+package jewelmusic
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+)
+
+// extraParamsKey is the context key for per-call extra query parameters
+type extraParamsKey struct{}
+
+// WithExtraParams returns a context that adds params to the query string of
+// GET requests made with it. This allows using server parameters the SDK does
+// not model yet. Parameters set by the SDK method itself take precedence.
+// Like the other per-call options, they do not apply to requests the SDK
+// makes on its own, such as lookups, polls, and iterator pages.
+//
+//	ctx = jewelmusic.WithExtraParams(ctx, map[string]string{"includeArchived": "true"})
+//	tracks, err := client.Tracks.List(ctx, 1, 20, nil)
+func WithExtraParams(ctx context.Context, params map[string]string) context.Context {
+	merged := make(map[string]string)
+	for k, v := range extraParams(ctx) {
+		merged[k] = v
+	}
+	for k, v := range params {
+		merged[k] = v
+	}
+	return context.WithValue(ctx, extraParamsKey{}, merged)
+}
+
+// extraParams returns the extra query parameters attached to ctx
+func extraParams(ctx context.Context) map[string]string {
+	params, _ := ctx.Value(extraParamsKey{}).(map[string]string)
+	return params
+}
+
+// ResponseMeta holds metadata about the most recent response received with a
+// context returned by WithResponseMeta
+type ResponseMeta struct {
+	RequestID    string
+	StatusCode   int
+	LastModified time.Time
+}
+
+// responseMetaKey is the context key for response metadata capture
+type responseMetaKey struct{}
+
+// WithResponseMeta returns a context that records response metadata into
+// meta for every request made with it, including successful ones, so the
+// request ID can be referenced in support tickets.
+//
+//	var meta jewelmusic.ResponseMeta
+//	track, err := client.Tracks.Get(jewelmusic.WithResponseMeta(ctx, &meta), id)
+//	log.Println("request ID:", meta.RequestID)
+func WithResponseMeta(ctx context.Context, meta *ResponseMeta) context.Context {
+	return context.WithValue(ctx, responseMetaKey{}, meta)
+}
+
+// recordResponseMeta stores response metadata in the capture attached to ctx,
+// if any
+func recordResponseMeta(ctx context.Context, requestID string, resp *http.Response) {
+	if meta, ok := ctx.Value(responseMetaKey{}).(*ResponseMeta); ok && meta != nil {
+		meta.RequestID = requestID
+		meta.StatusCode = resp.StatusCode
+		meta.LastModified, _ = http.ParseTime(resp.Header.Get("Last-Modified"))
+	}
+}
+
+// ErrNotModified is returned by GET requests made with WithIfModifiedSince
+// when the resource has not changed since the given time
+var ErrNotModified = errors.New("jewelmusic: not modified")
+
+// ifModifiedSinceKey is the context key for conditional GET requests
+type ifModifiedSinceKey struct{}
+
+// WithIfModifiedSince returns a context that makes GET requests conditional
+// on the resource having changed since t. Unchanged resources return
+// ErrNotModified without a body, which lets dashboards poll analytics
+// cheaply. Combine it with WithResponseMeta to learn the LastModified time
+// of each fetch. Lookups, polls, and iterator pages are never conditional.
+//
+//	data, err := client.Analytics.GetStreams(jewelmusic.WithIfModifiedSince(ctx, lastFetch), query)
+//	if errors.Is(err, jewelmusic.ErrNotModified) {
+//		// keep showing the cached data
+//	}
+func WithIfModifiedSince(ctx context.Context, t time.Time) context.Context {
+	return context.WithValue(ctx, ifModifiedSinceKey{}, t)
+}
+
+// ifModifiedSince returns the time attached to ctx by WithIfModifiedSince
+func ifModifiedSince(ctx context.Context) time.Time {
+	t, _ := ctx.Value(ifModifiedSinceKey{}).(time.Time)
+	return t
+}
+
+// headersKey is the context key for per-call request headers
+type headersKey struct{}
+
+// WithHeaders returns a context that adds headers to the requests made with
+// it, without affecting other calls or the lookups, polls, and iterator pages
+// the SDK makes on its own. Headers set by the SDK itself, such as
+// authentication, take precedence.
+//
+//	ctx = jewelmusic.WithHeaders(ctx, map[string]string{"X-Priority": "high"})
+//	analysis, err := client.Analysis.UploadTrack(ctx, file, "track.wav", nil)
+func WithHeaders(ctx context.Context, headers map[string]string) context.Context {
+	merged := make(map[string]string)
+	for k, v := range requestHeaders(ctx) {
+		merged[k] = v
+	}
+	for k, v := range headers {
+		merged[k] = v
+	}
+	return context.WithValue(ctx, headersKey{}, merged)
+}
+
+// requestHeaders returns the per-call headers attached to ctx
+func requestHeaders(ctx context.Context) map[string]string {
+	headers, _ := ctx.Value(headersKey{}).(map[string]string)
+	return headers
+}
+
+// applyRequestHeaders adds the per-call headers attached to the request's
+// context that the SDK has not already set
+func applyRequestHeaders(req *http.Request) {
+	for k, v := range requestHeaders(req.Context()) {
+		if req.Header.Get(k) == "" {
+			req.Header.Set(k, v)
+		}
+	}
+}
+
+// detachedContext hides the per-call options of its parent context while
+// keeping its deadline, cancellation, and other values
+type detachedContext struct {
+	context.Context
+}
+
+// Value implements context.Context
+func (c detachedContext) Value(key interface{}) interface{} {
+	switch key.(type) {
+	case extraParamsKey, ifModifiedSinceKey, headersKey:
+		return nil
+	}
+	return c.Context.Value(key)
+}
+
+// detachCallOptions returns ctx without the options set by WithExtraParams,
+// WithIfModifiedSince, and WithHeaders, for requests the SDK makes on its own
+// while serving a call
+func detachCallOptions(ctx context.Context) context.Context {
+	return detachedContext{ctx}
+}
+
+// DeadlineBudget splits the time left until a context's deadline across the
+// steps of a multi-step workflow, such as upload, analyze, and release. Each
+// step gets a share of the time remaining when it starts, in proportion to
+// its weight among the steps not yet started, so a slow step is cut off at
+// its share instead of silently starving the later ones, and time left over
+// by a fast step carries over. A DeadlineBudget is meant for sequential steps
+// and is not safe for concurrent use.
+//
+//	budget, err := jewelmusic.NewDeadlineBudget(ctx, 3, 1, 1)
+//	uploadCtx, cancel, err := budget.Next()
+//	...
+//	track, err = client.Tracks.Upload(uploadCtx, file, filename, metadata, nil)
+//	cancel()
+type DeadlineBudget struct {
+	ctx     context.Context
+	weights []float64
+	next    int
+}
+
+// NewDeadlineBudget creates a budget for one step per weight. ctx must have a
+// deadline and every weight must be positive.
+func NewDeadlineBudget(ctx context.Context, weights ...float64) (*DeadlineBudget, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		return nil, errors.New("jewelmusic: deadline budget requires a context with a deadline")
+	}
+	if len(weights) == 0 {
+		return nil, errors.New("jewelmusic: deadline budget requires at least one step")
+	}
+	for _, weight := range weights {
+		if weight <= 0 {
+			return nil, errors.New("jewelmusic: deadline budget weights must be positive")
+		}
+	}
+	return &DeadlineBudget{ctx: ctx, weights: weights}, nil
+}
+
+// Allocation returns the time the next step would get if it started now
+func (b *DeadlineBudget) Allocation() time.Duration {
+	if b.next >= len(b.weights) {
+		return 0
+	}
+	deadline, _ := b.ctx.Deadline()
+	remaining := time.Until(deadline)
+	if remaining <= 0 {
+		return 0
+	}
+
+	var total float64
+	for _, weight := range b.weights[b.next:] {
+		total += weight
+	}
+	return time.Duration(float64(remaining) * b.weights[b.next] / total)
+}
+
+// Next starts the next step and returns its context, which expires at the
+// end of the step's share. It fails without starting the step if the parent
+// context is already done or all steps have been started.
+func (b *DeadlineBudget) Next() (context.Context, context.CancelFunc, error) {
+	if err := b.ctx.Err(); err != nil {
+		return nil, nil, err
+	}
+	if b.next >= len(b.weights) {
+		return nil, nil, errors.New("jewelmusic: deadline budget has no steps left")
+	}
+
+	allocation := b.Allocation()
+	b.next++
+	ctx, cancel := context.WithTimeout(b.ctx, allocation)
+	return ctx, cancel, nil
+}
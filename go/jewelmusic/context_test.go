@@ -0,0 +1,371 @@
+package jewelmusic
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWithIfModifiedSince(t *testing.T) {
+	lastModified := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		since   time.Time
+		wantErr error
+	}{
+		{"unchanged", lastModified, ErrNotModified},
+		{"changed", lastModified.Add(-time.Hour), nil},
+		{"unconditional", time.Time{}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Last-Modified", lastModified.Format(http.TimeFormat))
+				if since, err := http.ParseTime(r.Header.Get("If-Modified-Since")); err == nil && !lastModified.After(since) {
+					w.WriteHeader(http.StatusNotModified)
+					return
+				}
+				writeData(t, w, map[string]interface{}{"summary": map[string]interface{}{"totalStreams": 10}})
+			})
+
+			ctx := context.Background()
+			if !tt.since.IsZero() {
+				ctx = WithIfModifiedSince(ctx, tt.since)
+			}
+			var meta ResponseMeta
+			data, err := client.Analytics.GetStreams(WithResponseMeta(ctx, &meta), AnalyticsQuery{})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("GetStreams error = %v, want %v", err, tt.wantErr)
+			}
+			if !meta.LastModified.Equal(lastModified) {
+				t.Errorf("LastModified = %v, want %v", meta.LastModified, lastModified)
+			}
+			if tt.wantErr == nil && data.Summary.TotalStreams != 10 {
+				t.Errorf("TotalStreams = %d, want 10", data.Summary.TotalStreams)
+			}
+		})
+	}
+}
+
+func TestCallOptionsDoNotApplyToNestedRequests(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "GET":
+			if r.Header.Get("If-Modified-Since") != "" {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			if r.Header.Get("X-Trace") != "" || r.URL.Query().Get("debug") != "" {
+				t.Errorf("lookup carries per-call options: %s", r.URL)
+			}
+			writeData(t, w, map[string]interface{}{"id": "gen_1", "type": "melody", "status": "completed"})
+		case "POST":
+			if r.Header.Get("X-Trace") != "abc" {
+				t.Errorf("X-Trace = %q, want abc", r.Header.Get("X-Trace"))
+			}
+			writeData(t, w, map[string]interface{}{"id": "gen_2", "type": "melody", "status": "pending"})
+		}
+	})
+
+	ctx := WithIfModifiedSince(context.Background(), time.Now())
+	ctx = WithHeaders(ctx, map[string]string{"X-Trace": "abc"})
+	ctx = WithExtraParams(ctx, map[string]string{"debug": "true"})
+
+	generation, err := client.Copilot.Extend(ctx, "gen_1", 30, nil)
+	if err != nil {
+		t.Fatalf("Extend: %v", err)
+	}
+	if generation.ID != "gen_2" {
+		t.Errorf("generation ID = %q, want gen_2", generation.ID)
+	}
+}
+
+func TestWithExtraParams(t *testing.T) {
+	tests := []struct {
+		name      string
+		ctx       func(ctx context.Context) context.Context
+		wantQuery string
+	}{
+		{
+			name:      "none",
+			ctx:       func(ctx context.Context) context.Context { return ctx },
+			wantQuery: "genre=jazz&page=1&perPage=20",
+		},
+		{
+			name: "merged",
+			ctx: func(ctx context.Context) context.Context {
+				return WithExtraParams(ctx, map[string]string{"includeArchived": "true"})
+			},
+			wantQuery: "genre=jazz&includeArchived=true&page=1&perPage=20",
+		},
+		{
+			name: "layered",
+			ctx: func(ctx context.Context) context.Context {
+				ctx = WithExtraParams(ctx, map[string]string{"includeArchived": "true", "sort": "title"})
+				return WithExtraParams(ctx, map[string]string{"sort": "-uploadedAt"})
+			},
+			wantQuery: "genre=jazz&includeArchived=true&page=1&perPage=20&sort=-uploadedAt",
+		},
+		{
+			name: "SDK params take precedence",
+			ctx: func(ctx context.Context) context.Context {
+				return WithExtraParams(ctx, map[string]string{"genre": "rock", "page": "9"})
+			},
+			wantQuery: "genre=jazz&page=1&perPage=20",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var query string
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				query = r.URL.Query().Encode()
+				writePage(t, w, []map[string]interface{}{}, 1, 1, 0)
+			})
+
+			ctx := tt.ctx(context.Background())
+			if _, err := client.Tracks.List(ctx, 1, 20, &TrackFilter{Genre: "jazz"}); err != nil {
+				t.Fatalf("List: %v", err)
+			}
+			if query != tt.wantQuery {
+				t.Errorf("query = %q, want %q", query, tt.wantQuery)
+			}
+		})
+	}
+}
+
+func TestRequestIDCaptured(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		header string
+		body   string
+		want   string
+	}{
+		{"success from meta", http.StatusOK, "req_header", `{"success":true,"data":{},"meta":{"requestId":"req_meta"}}`, "req_meta"},
+		{"success from header", http.StatusOK, "req_header", `{"success":true,"data":{}}`, "req_header"},
+		{"error from meta", http.StatusNotFound, "", `{"success":false,"error":{"code":"NOT_FOUND","message":"missing"},"meta":{"requestId":"req_meta"}}`, "req_meta"},
+		{"validation error from header", http.StatusUnprocessableEntity, "req_header", `{"success":false,"error":{"code":"VALIDATION_ERROR","message":"invalid"}}`, "req_header"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				if tt.header != "" {
+					w.Header().Set("X-Request-ID", tt.header)
+				}
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}, WithMaxRetries(0))
+
+			requests := map[string]func(ctx context.Context) error{
+				"json": func(ctx context.Context) error {
+					return client.Get(ctx, "/user/profile", nil, nil)
+				},
+				"upload": func(ctx context.Context) error {
+					_, err := client.UploadFile(ctx, "/tracks/upload", strings.NewReader("audio"), "song.wav", nil)
+					return err
+				},
+			}
+			for kind, request := range requests {
+				var meta ResponseMeta
+				err := request(WithResponseMeta(context.Background(), &meta))
+				if meta.RequestID != tt.want || meta.StatusCode != tt.status {
+					t.Errorf("%s: meta = %+v, want request ID %q and status %d", kind, meta, tt.want, tt.status)
+				}
+				if tt.status < 400 {
+					if err != nil {
+						t.Errorf("%s: %v", kind, err)
+					}
+					continue
+				}
+				var apiErr *APIError
+				if !errors.As(err, &apiErr) || apiErr.RequestID != tt.want {
+					t.Errorf("%s: error = %v, want an APIError with request ID %q", kind, err, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestWithHeaders(t *testing.T) {
+	tests := []struct {
+		name        string
+		ctx         func(ctx context.Context) context.Context
+		wantHeaders map[string]string
+	}{
+		{
+			name: "single header",
+			ctx: func(ctx context.Context) context.Context {
+				return WithHeaders(ctx, map[string]string{"X-Priority": "high"})
+			},
+			wantHeaders: map[string]string{"X-Priority": "high"},
+		},
+		{
+			name: "nested calls merge",
+			ctx: func(ctx context.Context) context.Context {
+				ctx = WithHeaders(ctx, map[string]string{"X-Priority": "low", "X-Team": "dashboards"})
+				return WithHeaders(ctx, map[string]string{"X-Priority": "high"})
+			},
+			wantHeaders: map[string]string{"X-Priority": "high", "X-Team": "dashboards"},
+		},
+		{
+			name: "sdk headers take precedence",
+			ctx: func(ctx context.Context) context.Context {
+				return WithHeaders(ctx, map[string]string{"Authorization": "Bearer other", "X-Priority": "high"})
+			},
+			wantHeaders: map[string]string{"Authorization": "Bearer jml_live_test", "X-Priority": "high"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests []http.Header
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				requests = append(requests, r.Header.Clone())
+				writeData(t, w, map[string]interface{}{"id": "trk_1"})
+			})
+
+			ctx := context.Background()
+			if _, err := client.Tracks.Get(tt.ctx(ctx), "trk_1"); err != nil {
+				t.Fatalf("call with headers: %v", err)
+			}
+			if _, err := client.Tracks.Get(ctx, "trk_1"); err != nil {
+				t.Fatalf("call without headers: %v", err)
+			}
+			if len(requests) != 2 {
+				t.Fatalf("requests = %d, want 2", len(requests))
+			}
+
+			for k, v := range tt.wantHeaders {
+				if got := requests[0].Get(k); got != v {
+					t.Errorf("%s = %q, want %q", k, got, v)
+				}
+			}
+			for _, k := range []string{"X-Priority", "X-Team"} {
+				if got := requests[1].Get(k); got != "" {
+					t.Errorf("%s = %q on a call that did not set it", k, got)
+				}
+			}
+		})
+	}
+}
+
+func TestDeadlineBudgetAllocation(t *testing.T) {
+	const tolerance = 200 * time.Millisecond
+	tests := []struct {
+		name    string
+		weights []float64
+		want    []time.Duration
+	}{
+		{"equal steps", []float64{1, 1, 1, 1}, []time.Duration{15 * time.Second, 20 * time.Second, 30 * time.Second, 60 * time.Second}},
+		{"weighted steps", []float64{3, 1, 1}, []time.Duration{36 * time.Second, 30 * time.Second, 60 * time.Second}},
+		{"single step", []float64{2}, []time.Duration{60 * time.Second}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parent, cancel := context.WithTimeout(context.Background(), time.Minute)
+			defer cancel()
+			budget, err := NewDeadlineBudget(parent, tt.weights...)
+			if err != nil {
+				t.Fatalf("NewDeadlineBudget: %v", err)
+			}
+
+			// Steps finish instantly, so each gets its share of the full minute
+			for i, want := range tt.want {
+				if got := budget.Allocation(); got < want-tolerance || got > want {
+					t.Errorf("step %d allocation = %v, want %v", i, got, want)
+				}
+				ctx, cancelStep, err := budget.Next()
+				if err != nil {
+					t.Fatalf("step %d: %v", i, err)
+				}
+				deadline, _ := ctx.Deadline()
+				if got := time.Until(deadline); got < want-tolerance || got > want {
+					t.Errorf("step %d deadline in %v, want %v", i, got, want)
+				}
+				cancelStep()
+			}
+
+			if budget.Allocation() != 0 {
+				t.Errorf("allocation after the last step = %v, want 0", budget.Allocation())
+			}
+			if _, _, err := budget.Next(); err == nil {
+				t.Error("Next succeeded with no steps left")
+			}
+		})
+	}
+}
+
+func TestDeadlineBudgetAbortsSlowStep(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}, WithMaxRetries(0))
+
+	parent, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	budget, err := NewDeadlineBudget(parent, 1, 1)
+	if err != nil {
+		t.Fatalf("NewDeadlineBudget: %v", err)
+	}
+
+	uploadCtx, cancelUpload, err := budget.Next()
+	if err != nil {
+		t.Fatalf("Next: %v", err)
+	}
+	start := time.Now()
+	_, err = client.Tracks.Get(uploadCtx, "trk_1")
+	cancelUpload()
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("slow step error = %v, want a deadline error", err)
+	}
+	if elapsed := time.Since(start); elapsed > 1500*time.Millisecond {
+		t.Errorf("slow step ran for %v, past its share of the budget", elapsed)
+	}
+	if parent.Err() != nil {
+		t.Fatal("slow step used up the whole budget")
+	}
+	if got := budget.Allocation(); got < 500*time.Millisecond {
+		t.Errorf("last step allocation = %v, want the remaining time", got)
+	}
+
+	cancel()
+	if _, _, err := budget.Next(); !errors.Is(err, context.Canceled) {
+		t.Errorf("Next after the parent was cancelled = %v, want context.Canceled", err)
+	}
+}
+
+func TestNewDeadlineBudgetValidates(t *testing.T) {
+	withDeadline, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		weights []float64
+	}{
+		{"no deadline", context.Background(), []float64{1}},
+		{"no steps", withDeadline, nil},
+		{"zero weight", withDeadline, []float64{1, 0}},
+		{"negative weight", withDeadline, []float64{-1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewDeadlineBudget(tt.ctx, tt.weights...); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
@@ -1,6 +1,11 @@
 package jewelmusic
 
-import "context"
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+)
 
 // CopilotResource provides AI-powered music generation capabilities
 type CopilotResource struct {
@@ -17,6 +22,7 @@ type MelodyOptions struct {
 	Instruments []string `json:"instruments,omitempty"`
 	Complexity  string   `json:"complexity,omitempty"`
 	Energy      string   `json:"energy,omitempty"`
+	Priority    Priority `json:"priority,omitempty"`
 }
 
 // HarmonyOptions represents options for harmony generation
@@ -26,45 +32,68 @@ type HarmonyOptions struct {
 	Complexity  string   `json:"complexity,omitempty"`
 	Voicing     string   `json:"voicing,omitempty"`
 	Instruments []string `json:"instruments,omitempty"`
+	Priority    Priority `json:"priority,omitempty"`
 }
 
 // LyricsOptions represents options for lyrics generation
 type LyricsOptions struct {
-	Theme          string `json:"theme"`
-	Genre          string `json:"genre,omitempty"`
-	Language       string `json:"language,omitempty"`
-	Mood           string `json:"mood,omitempty"`
-	Structure      string `json:"structure,omitempty"`
-	RhymeScheme    string `json:"rhymeScheme,omitempty"`
-	SyllableCount  string `json:"syllableCount,omitempty"`
-	InspirationText string `json:"inspirationText,omitempty"`
+	Theme           string   `json:"theme"`
+	Genre           string   `json:"genre,omitempty"`
+	Language        string   `json:"language,omitempty"`
+	Mood            string   `json:"mood,omitempty"`
+	Structure       string   `json:"structure,omitempty"`
+	RhymeScheme     string   `json:"rhymeScheme,omitempty"`
+	SyllableCount   string   `json:"syllableCount,omitempty"`
+	InspirationText string   `json:"inspirationText,omitempty"`
+	Priority        Priority `json:"priority,omitempty"`
 }
 
 // SongOptions represents options for complete song generation
 type SongOptions struct {
-	Prompt         string `json:"prompt,omitempty"`
-	MelodyID       string `json:"melodyId,omitempty"`
-	HarmonyID      string `json:"harmonyId,omitempty"`
-	LyricsID       string `json:"lyricsId,omitempty"`
-	TemplateID     string `json:"templateId,omitempty"`
-	Style          string `json:"style,omitempty"`
-	Duration       int    `json:"duration,omitempty"`
-	IncludeVocals  bool   `json:"includeVocals,omitempty"`
-	VocalStyle     string `json:"vocalStyle,omitempty"`
-	MixingStyle    string `json:"mixingStyle,omitempty"`
-	MasteringPreset string `json:"masteringPreset,omitempty"`
+	Prompt          string   `json:"prompt,omitempty"`
+	MelodyID        string   `json:"melodyId,omitempty"`
+	HarmonyID       string   `json:"harmonyId,omitempty"`
+	LyricsID        string   `json:"lyricsId,omitempty"`
+	TemplateID      string   `json:"templateId,omitempty"`
+	Style           string   `json:"style,omitempty"`
+	Duration        int      `json:"duration,omitempty"`
+	IncludeVocals   bool     `json:"includeVocals,omitempty"`
+	VocalStyle      string   `json:"vocalStyle,omitempty"`
+	MixingStyle     string   `json:"mixingStyle,omitempty"`
+	MasteringPreset string   `json:"masteringPreset,omitempty"`
+	Priority        Priority `json:"priority,omitempty"`
 }
 
 // StyleTransferOptions represents options for style transfer
 type StyleTransferOptions struct {
-	SourceID          string  `json:"sourceId"`
-	TargetStyle       string  `json:"targetStyle"`
-	Intensity         float64 `json:"intensity,omitempty"`
-	PreserveStructure bool    `json:"preserveStructure,omitempty"`
-	PreserveTiming    bool    `json:"preserveTiming,omitempty"`
+	SourceID          string   `json:"sourceId"`
+	TargetStyle       string   `json:"targetStyle"`
+	Intensity         float64  `json:"intensity,omitempty"`
+	PreserveStructure bool     `json:"preserveStructure,omitempty"`
+	PreserveTiming    bool     `json:"preserveTiming,omitempty"`
+	Priority          Priority `json:"priority,omitempty"`
+}
+
+// RemixOptions represents options for blending generations into a remix.
+// Balance optionally weights each source and must match SourceIDs in length.
+type RemixOptions struct {
+	SourceIDs []string  `json:"sourceIds"`
+	Style     string    `json:"style,omitempty"`
+	Balance   []float64 `json:"balance,omitempty"`
+}
+
+// TemplateQuery represents filters for song templates. Duration is the
+// target length in seconds.
+type TemplateQuery struct {
+	Genre    string `json:"genre,omitempty"`
+	Mood     string `json:"mood,omitempty"`
+	Duration int    `json:"duration,omitempty"`
+	Style    string `json:"style,omitempty"`
 }
 
 // TemplateFilter represents filters for song templates
+//
+// Deprecated: use TemplateQuery, whose Duration is a number of seconds.
 type TemplateFilter struct {
 	Genre    string `json:"genre,omitempty"`
 	Mood     string `json:"mood,omitempty"`
@@ -72,8 +101,24 @@ type TemplateFilter struct {
 	Style    string `json:"style,omitempty"`
 }
 
+// ExtendOptions represents options for extending a generation
+type ExtendOptions struct {
+	Style    string `json:"style,omitempty"`
+	AddOutro bool   `json:"addOutro,omitempty"`
+}
+
+// extendableGenerationTypes lists the generation types that can be extended
+var extendableGenerationTypes = map[string]bool{
+	"melody": true,
+	"song":   true,
+}
+
 // GenerateMelody generates an AI melody
 func (c *CopilotResource) GenerateMelody(ctx context.Context, options MelodyOptions) (*Generation, error) {
+	if err := options.Priority.validate(); err != nil {
+		return nil, err
+	}
+
 	var result Generation
 	err := c.client.Post(ctx, "/copilot/melody", options, &result)
 	return &result, err
@@ -81,6 +126,10 @@ func (c *CopilotResource) GenerateMelody(ctx context.Context, options MelodyOpti
 
 // GenerateHarmony generates AI harmony for a melody
 func (c *CopilotResource) GenerateHarmony(ctx context.Context, options HarmonyOptions) (*Generation, error) {
+	if err := options.Priority.validate(); err != nil {
+		return nil, err
+	}
+
 	var result Generation
 	err := c.client.Post(ctx, "/copilot/harmony", options, &result)
 	return &result, err
@@ -88,19 +137,91 @@ func (c *CopilotResource) GenerateHarmony(ctx context.Context, options HarmonyOp
 
 // GenerateLyrics generates AI lyrics
 func (c *CopilotResource) GenerateLyrics(ctx context.Context, options LyricsOptions) (*Generation, error) {
+	if err := options.Priority.validate(); err != nil {
+		return nil, err
+	}
+
 	var result Generation
 	err := c.client.Post(ctx, "/copilot/lyrics", options, &result)
 	return &result, err
 }
 
 // CompleteSong generates a complete song with AI
+// A MasteringPreset, if set, is checked against ListMasteringPresets first.
 func (c *CopilotResource) CompleteSong(ctx context.Context, options SongOptions) (*Generation, error) {
+	if err := options.Priority.validate(); err != nil {
+		return nil, err
+	}
+	if options.MasteringPreset != "" {
+		if err := c.client.Analysis.validateMasteringPreset(ctx, options.MasteringPreset); err != nil {
+			return nil, err
+		}
+	}
+
 	var result Generation
 	err := c.client.Post(ctx, "/copilot/complete-song", options, &result)
 	return &result, err
 }
 
+// EstimateCost returns the expected cost of generating a song with options
+// without starting the generation
+func (c *CopilotResource) EstimateCost(ctx context.Context, options SongOptions) (*CostEstimate, error) {
+	var result CostEstimate
+	err := c.client.Post(ctx, "/copilot/complete-song/estimate", options, &result)
+	return &result, err
+}
+
+// GenerationProgress reports the state of a running generation
+type GenerationProgress struct {
+	GenerationID string
+	Status       string
+	Stage        string
+	Progress     float64
+}
+
+// CompleteSongWithProgress generates a complete song, for example from a
+// template, and waits for it to finish, calling progress whenever the
+// reported status, stage, or progress changes. progress is called from the
+// calling goroutine. It returns the finished generation.
+func (c *CopilotResource) CompleteSongWithProgress(ctx context.Context, options SongOptions, progress func(GenerationProgress)) (*Generation, error) {
+	generation, err := c.CompleteSong(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+
+	var last GenerationProgress
+	report := func(g *Generation) {
+		current := GenerationProgress{
+			GenerationID: g.ID,
+			Status:       g.Status,
+			Stage:        g.Stage,
+			Progress:     g.Progress,
+		}
+		if progress != nil && current != last {
+			progress(current)
+		}
+		last = current
+	}
+
+	report(generation)
+	if IsTerminalStatus(generation.Status) {
+		return generation, nil
+	}
+
+	return Poll(ctx, func() (*Generation, error) {
+		g, err := c.GetGeneration(detachCallOptions(ctx), generation.ID)
+		if err == nil {
+			report(g)
+		}
+		return g, err
+	}, func(g *Generation) bool {
+		return IsTerminalStatus(g.Status)
+	}, c.client.pollOptions(nil))
+}
+
 // GetTemplates retrieves available song templates
+//
+// Deprecated: use ListTemplates, which returns typed templates.
 func (c *CopilotResource) GetTemplates(ctx context.Context, filter *TemplateFilter) ([]map[string]interface{}, error) {
 	params := make(map[string]string)
 	if filter != nil {
@@ -123,8 +244,53 @@ func (c *CopilotResource) GetTemplates(ctx context.Context, filter *TemplateFilt
 	return result, err
 }
 
+// ListTemplates retrieves the available song templates matching query
+func (c *CopilotResource) ListTemplates(ctx context.Context, query *TemplateQuery) (*Templates, error) {
+	params := make(map[string]string)
+	if query != nil {
+		if query.Genre != "" {
+			params["genre"] = query.Genre
+		}
+		if query.Mood != "" {
+			params["mood"] = query.Mood
+		}
+		if query.Duration < 0 {
+			return nil, &APIError{Code: "INVALID_REQUEST", Message: "Duration must not be negative"}
+		}
+		if query.Duration > 0 {
+			params["duration"] = strconv.Itoa(query.Duration)
+		}
+		if query.Style != "" {
+			params["style"] = query.Style
+		}
+	}
+
+	var result Templates
+	if err := c.client.Get(ctx, "/copilot/templates", params, &result.Items); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetTemplate retrieves the full definition of a song template
+func (c *CopilotResource) GetTemplate(ctx context.Context, templateID string) (*Template, error) {
+	if templateID == "" {
+		return nil, &APIError{Code: "INVALID_REQUEST", Message: "Template ID is required"}
+	}
+
+	var result Template
+	if err := c.client.Get(ctx, "/copilot/templates/"+templateID, nil, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
 // StyleTransfer applies style transfer to existing content
 func (c *CopilotResource) StyleTransfer(ctx context.Context, options StyleTransferOptions) (*Generation, error) {
+	if err := options.Priority.validate(); err != nil {
+		return nil, err
+	}
+
 	var result Generation
 	err := c.client.Post(ctx, "/copilot/style-transfer", options, &result)
 	return &result, err
@@ -139,10 +305,7 @@ func (c *CopilotResource) GetGeneration(ctx context.Context, generationID string
 
 // ListGenerations lists user's generations with pagination
 func (c *CopilotResource) ListGenerations(ctx context.Context, page, perPage int, generationType string) (*ListResponse, error) {
-	params := map[string]string{
-		"page":    string(rune(page)),
-		"perPage": string(rune(perPage)),
-	}
+	params := pageParams(page, perPage)
 	if generationType != "" {
 		params["type"] = generationType
 	}
@@ -150,4 +313,123 @@ func (c *CopilotResource) ListGenerations(ctx context.Context, page, perPage int
 	var result ListResponse
 	err := c.client.Get(ctx, "/copilot/generations", params, &result)
 	return &result, err
-}
\ No newline at end of file
+}
+
+// GenerationsIterator returns an iterator over all of the user's generations,
+// optionally filtered by type
+func (c *CopilotResource) GenerationsIterator(generationType string) *Iterator[Generation] {
+	return newIterator[Generation](func(ctx context.Context, page, perPage int) (*ListResponse, error) {
+		return c.ListGenerations(ctx, page, perPage, generationType)
+	})
+}
+
+// WaitForGeneration polls a generation until it reaches a terminal status
+func (c *CopilotResource) WaitForGeneration(ctx context.Context, generationID string, options *PollOptions) (*Generation, error) {
+	return Poll(ctx, func() (*Generation, error) {
+		return c.GetGeneration(detachCallOptions(ctx), generationID)
+	}, func(g *Generation) bool {
+		return IsTerminalStatus(g.Status)
+	}, c.client.pollOptions(options))
+}
+
+// CancelGeneration cancels an in-progress generation and returns its updated
+// state. ErrAlreadyCompleted is returned if the generation has already finished.
+func (c *CopilotResource) CancelGeneration(ctx context.Context, generationID string) (*Generation, error) {
+	var result Generation
+	err := c.client.cancelJob(ctx, "/copilot/generations/"+generationID+"/cancel", &result, func() string { return result.Status })
+	return &result, err
+}
+
+// Extend lengthens a completed melody or song generation by additionalSeconds
+// and returns the new generation
+func (c *CopilotResource) Extend(ctx context.Context, generationID string, additionalSeconds int, opts *ExtendOptions) (*Generation, error) {
+	if additionalSeconds <= 0 {
+		return nil, &APIError{Code: "INVALID_REQUEST", Message: "Additional seconds must be positive"}
+	}
+
+	source, err := c.GetGeneration(detachCallOptions(ctx), generationID)
+	if err != nil {
+		return nil, err
+	}
+	if !extendableGenerationTypes[source.Type] {
+		return nil, &APIError{Code: "INVALID_REQUEST", Message: "Generation type cannot be extended: " + source.Type}
+	}
+	if source.Status != "completed" {
+		return nil, &APIError{Code: "INVALID_REQUEST", Message: "Only completed generations can be extended"}
+	}
+
+	requestData := map[string]interface{}{
+		"additionalSeconds": additionalSeconds,
+	}
+	if opts != nil {
+		if opts.Style != "" {
+			requestData["style"] = opts.Style
+		}
+		if opts.AddOutro {
+			requestData["addOutro"] = true
+		}
+	}
+
+	var result Generation
+	err = c.client.Post(ctx, "/copilot/generations/"+generationID+"/extend", requestData, &result)
+	return &result, err
+}
+
+// Remix blends two or more generations into a new generation
+func (c *CopilotResource) Remix(ctx context.Context, options RemixOptions) (*Generation, error) {
+	if len(options.SourceIDs) < 2 {
+		return nil, &APIError{Code: "INVALID_REQUEST", Message: "Remix requires at least two source generations"}
+	}
+	if len(options.Balance) > 0 {
+		if len(options.Balance) != len(options.SourceIDs) {
+			return nil, &APIError{Code: "INVALID_REQUEST", Message: "Balance must have one weight per source"}
+		}
+		for _, weight := range options.Balance {
+			if weight < 0 {
+				return nil, &APIError{Code: "INVALID_REQUEST", Message: "Balance weights must not be negative"}
+			}
+		}
+	}
+
+	var result Generation
+	err := c.client.Post(ctx, "/copilot/remix", options, &result)
+	return &result, err
+}
+
+// StreamSong starts a complete song generation and returns a reader that
+// delivers the audio progressively as the server renders it, along with the
+// generation handle. The caller must close the reader. If the stream breaks
+// off before rendering finishes, Read returns an error wrapping the cause
+// rather than io.EOF.
+func (c *CopilotResource) StreamSong(ctx context.Context, options SongOptions) (io.ReadCloser, *Generation, error) {
+	generation, err := c.CompleteSong(ctx, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	body, err := c.client.openStream(ctx, "/copilot/generations/"+generation.ID+"/stream")
+	if err != nil {
+		return nil, generation, err
+	}
+	return &songStream{body: body, generationID: generation.ID}, generation, nil
+}
+
+// songStream wraps a streaming song response, annotating mid-stream errors
+type songStream struct {
+	body         io.ReadCloser
+	generationID string
+}
+
+// Read implements io.Reader
+func (s *songStream) Read(p []byte) (int, error) {
+	n, err := s.body.Read(p)
+	if err != nil && err != io.EOF {
+		err = fmt.Errorf("song stream for generation %s interrupted: %w", s.generationID, err)
+	}
+	return n, err
+}
+
+// Close implements io.Closer
+func (s *songStream) Close() error {
+	return s.body.Close()
+}
@@ -0,0 +1,503 @@
+package jewelmusic
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExtend(t *testing.T) {
+	tests := []struct {
+		name       string
+		sourceType string
+		status     string
+		seconds    int
+		opts       *ExtendOptions
+		wantBody   string
+		wantErr    bool
+	}{
+		{
+			name:       "melody with options",
+			sourceType: "melody",
+			status:     "completed",
+			seconds:    30,
+			opts:       &ExtendOptions{Style: "ambient", AddOutro: true},
+			wantBody:   `{"addOutro":true,"additionalSeconds":30,"style":"ambient"}`,
+		},
+		{name: "song", sourceType: "song", status: "completed", seconds: 15, wantBody: `{"additionalSeconds":15}`},
+		{name: "non-extendable type", sourceType: "lyrics", status: "completed", seconds: 30, wantErr: true},
+		{name: "still processing", sourceType: "melody", status: "processing", seconds: 30, wantErr: true},
+		{name: "non-positive duration", sourceType: "melody", status: "completed", seconds: 0, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body string
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/v1/copilot/generations/gen_1":
+					writeData(t, w, map[string]interface{}{"id": "gen_1", "type": tt.sourceType, "status": tt.status})
+				case "/v1/copilot/generations/gen_1/extend":
+					data, _ := io.ReadAll(r.Body)
+					body = strings.TrimSpace(string(data))
+					writeData(t, w, map[string]interface{}{"id": "gen_2", "type": tt.sourceType, "status": "pending"})
+				default:
+					t.Errorf("unexpected path %s", r.URL.Path)
+				}
+			})
+
+			generation, err := client.Copilot.Extend(context.Background(), "gen_1", tt.seconds, tt.opts)
+			if tt.wantErr {
+				if err == nil || body != "" {
+					t.Fatalf("err = %v, extend body = %q; want an error before extending", err, body)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Extend: %v", err)
+			}
+			if body != tt.wantBody {
+				t.Errorf("body = %s, want %s", body, tt.wantBody)
+			}
+			if generation.ID != "gen_2" {
+				t.Errorf("generation = %+v, want the new generation", generation)
+			}
+		})
+	}
+}
+
+func TestRemix(t *testing.T) {
+	tests := []struct {
+		name     string
+		options  RemixOptions
+		wantBody string
+		wantErr  bool
+	}{
+		{
+			name:     "two sources",
+			options:  RemixOptions{SourceIDs: []string{"gen_1", "gen_2"}, Style: "lofi", Balance: []float64{0.7, 0.3}},
+			wantBody: `{"sourceIds":["gen_1","gen_2"],"style":"lofi","balance":[0.7,0.3]}`,
+		},
+		{
+			name:     "without style or balance",
+			options:  RemixOptions{SourceIDs: []string{"gen_1", "gen_2", "gen_3"}},
+			wantBody: `{"sourceIds":["gen_1","gen_2","gen_3"]}`,
+		},
+		{name: "one source", options: RemixOptions{SourceIDs: []string{"gen_1"}}, wantErr: true},
+		{name: "no sources", options: RemixOptions{}, wantErr: true},
+		{name: "balance length mismatch", options: RemixOptions{SourceIDs: []string{"gen_1", "gen_2"}, Balance: []float64{1}}, wantErr: true},
+		{name: "negative weight", options: RemixOptions{SourceIDs: []string{"gen_1", "gen_2"}, Balance: []float64{1.2, -0.2}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body string
+			calls := 0
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				calls++
+				if r.URL.Path != "/v1/copilot/remix" {
+					t.Errorf("path = %s", r.URL.Path)
+				}
+				data, _ := io.ReadAll(r.Body)
+				body = strings.TrimSpace(string(data))
+				writeData(t, w, map[string]interface{}{"id": "gen_9", "type": "remix", "status": "pending"})
+			})
+
+			generation, err := client.Copilot.Remix(context.Background(), tt.options)
+			if tt.wantErr {
+				if err == nil || calls != 0 {
+					t.Fatalf("err = %v after %d requests, want a rejection before sending", err, calls)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Remix: %v", err)
+			}
+			if body != tt.wantBody {
+				t.Errorf("body = %s, want %s", body, tt.wantBody)
+			}
+			if generation.ID != "gen_9" {
+				t.Errorf("generation = %+v", generation)
+			}
+		})
+	}
+}
+
+func TestStreamSong(t *testing.T) {
+	chunks := []string{"RIFF", "audio-1", "audio-2", "audio-3"}
+
+	tests := []struct {
+		name    string
+		broken  bool
+		wantErr error
+	}{
+		{"complete stream", false, nil},
+		{"interrupted stream", true, io.ErrUnexpectedEOF},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/v1/copilot/complete-song":
+					writeData(t, w, map[string]interface{}{"id": "gen_1", "type": "song", "status": "processing"})
+				case "/v1/copilot/generations/gen_1/stream":
+					if tt.broken {
+						// Promise more audio than is sent so the body ends early
+						w.Header().Set("Content-Length", "1000")
+					}
+					w.Header().Set("Content-Type", "audio/wav")
+					for _, chunk := range chunks {
+						io.WriteString(w, chunk)
+						w.(http.Flusher).Flush()
+					}
+				default:
+					t.Errorf("unexpected path %s", r.URL.Path)
+				}
+			})
+
+			stream, generation, err := client.Copilot.StreamSong(context.Background(), SongOptions{})
+			if err != nil {
+				t.Fatalf("StreamSong: %v", err)
+			}
+			defer stream.Close()
+			if generation.ID != "gen_1" {
+				t.Errorf("generation = %+v", generation)
+			}
+
+			data, err := io.ReadAll(stream)
+			if string(data) != strings.Join(chunks, "") {
+				t.Errorf("audio = %q, want %q", data, strings.Join(chunks, ""))
+			}
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Errorf("read: %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.wantErr) || !strings.Contains(err.Error(), "gen_1") {
+				t.Errorf("read error = %v, want %v naming the generation", err, tt.wantErr)
+			}
+		})
+	}
+
+	t.Run("stream refused", func(t *testing.T) {
+		client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/v1/copilot/complete-song" {
+				writeData(t, w, map[string]interface{}{"id": "gen_1", "status": "processing"})
+				return
+			}
+			writeError(t, w, http.StatusConflict, "NOT_STREAMABLE", "Generation cannot be streamed")
+		})
+
+		stream, generation, err := client.Copilot.StreamSong(context.Background(), SongOptions{})
+		var apiErr *APIError
+		if stream != nil || !errors.As(err, &apiErr) || apiErr.Code != "NOT_STREAMABLE" {
+			t.Fatalf("StreamSong = %v, %v; want a NOT_STREAMABLE error", stream, err)
+		}
+		if generation == nil || generation.ID != "gen_1" {
+			t.Errorf("generation handle = %+v, want gen_1", generation)
+		}
+	})
+}
+
+func TestEstimateCost(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     string
+		wantBody string
+		estimate func(ctx context.Context, client *Client) (*CostEstimate, error)
+	}{
+		{
+			name:     "complete song",
+			path:     "/v1/copilot/complete-song/estimate",
+			wantBody: `{"prompt":"late night drive","style":"synthwave","duration":180,"includeVocals":true}`,
+			estimate: func(ctx context.Context, client *Client) (*CostEstimate, error) {
+				return client.Copilot.EstimateCost(ctx, SongOptions{Prompt: "late night drive", Style: "synthwave", Duration: 180, IncludeVocals: true})
+			},
+		},
+		{
+			name:     "batch",
+			path:     "/v1/tracks/batch/estimate",
+			wantBody: `{"operations":["analyze","master"],"trackIds":["trk_1","trk_2"]}`,
+			estimate: func(ctx context.Context, client *Client) (*CostEstimate, error) {
+				return client.Tracks.EstimateBatchCost(ctx, []string{"trk_1", "trk_2"}, []string{"analyze", "master"})
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodPost || r.URL.Path != tt.path {
+					t.Errorf("request = %s %s, want POST %s", r.Method, r.URL.Path, tt.path)
+				}
+				data, _ := io.ReadAll(r.Body)
+				if body := strings.TrimSpace(string(data)); body != tt.wantBody {
+					t.Errorf("body = %s, want %s", body, tt.wantBody)
+				}
+				writeData(t, w, map[string]interface{}{"credits": 12.5, "price": 3.75, "currency": "USD"})
+			})
+
+			estimate, err := tt.estimate(context.Background(), client)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			want := CostEstimate{Credits: 12.5, Price: 3.75, Currency: "USD"}
+			if *estimate != want {
+				t.Errorf("estimate = %+v, want %+v", *estimate, want)
+			}
+		})
+	}
+}
+
+func TestListTemplates(t *testing.T) {
+	const payload = `[
+		{"id": "tpl_1", "name": "Summer Anthem", "description": "Upbeat festival pop", "genre": "pop", "style": "anthemic", "mood": "happy", "duration": 180, "structure": ["intro", "verse", "chorus", "outro"]},
+		{"id": "tpl_2", "name": "Late Night", "genre": "pop", "style": "lofi", "mood": "calm", "duration": 150}
+	]`
+	want := []Template{
+		{ID: "tpl_1", Name: "Summer Anthem", Description: "Upbeat festival pop", Genre: "pop", Style: "anthemic", Mood: "happy", Duration: 180, Structure: []string{"intro", "verse", "chorus", "outro"}},
+		{ID: "tpl_2", Name: "Late Night", Genre: "pop", Style: "lofi", Mood: "calm", Duration: 150},
+	}
+
+	tests := []struct {
+		name      string
+		query     *TemplateQuery
+		wantQuery url.Values
+		wantErr   bool
+	}{
+		{name: "no query", wantQuery: url.Values{}},
+		{
+			name:      "full query",
+			query:     &TemplateQuery{Genre: "pop", Mood: "happy", Duration: 180, Style: "anthemic"},
+			wantQuery: url.Values{"genre": {"pop"}, "mood": {"happy"}, "duration": {"180"}, "style": {"anthemic"}},
+		},
+		{name: "zero duration omitted", query: &TemplateQuery{Genre: "pop"}, wantQuery: url.Values{"genre": {"pop"}}},
+		{name: "negative duration", query: &TemplateQuery{Duration: -1}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests := 0
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				if r.URL.Path != "/v1/copilot/templates" {
+					t.Errorf("path = %s", r.URL.Path)
+				}
+				if got := r.URL.Query(); !reflect.DeepEqual(got, tt.wantQuery) {
+					t.Errorf("query = %v, want %v", got, tt.wantQuery)
+				}
+				writeData(t, w, json.RawMessage(payload))
+			})
+
+			templates, err := client.Copilot.ListTemplates(context.Background(), tt.query)
+			if tt.wantErr {
+				var apiErr *APIError
+				if !errors.As(err, &apiErr) || apiErr.Code != "INVALID_REQUEST" || requests != 0 {
+					t.Errorf("err = %v after %d requests, want an INVALID_REQUEST error before sending", err, requests)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ListTemplates: %v", err)
+			}
+			if !reflect.DeepEqual(templates.Items, want) {
+				t.Errorf("templates = %+v, want %+v", templates.Items, want)
+			}
+		})
+	}
+}
+
+func TestGetTemplatesDeprecatedFilter(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		want := url.Values{"genre": {"pop"}, "duration": {"2-3min"}}
+		if got := r.URL.Query(); !reflect.DeepEqual(got, want) {
+			t.Errorf("query = %v, want %v", got, want)
+		}
+		writeData(t, w, json.RawMessage(`[{"id": "tpl_1", "name": "Summer Anthem"}]`))
+	})
+
+	templates, err := client.Copilot.GetTemplates(context.Background(), &TemplateFilter{Genre: "pop", Duration: "2-3min"})
+	if err != nil {
+		t.Fatalf("GetTemplates: %v", err)
+	}
+	if len(templates) != 1 || templates[0]["id"] != "tpl_1" {
+		t.Errorf("templates = %v", templates)
+	}
+}
+
+func TestGetTemplate(t *testing.T) {
+	tests := []struct {
+		name       string
+		templateID string
+		wantErr    bool
+	}{
+		{name: "existing template", templateID: "tpl_1"},
+		{name: "empty id", templateID: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests := 0
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				if r.Method != http.MethodGet || r.URL.Path != "/v1/copilot/templates/tpl_1" {
+					t.Errorf("request = %s %s, want GET /v1/copilot/templates/tpl_1", r.Method, r.URL.Path)
+				}
+				writeData(t, w, json.RawMessage(`{
+					"id": "tpl_1",
+					"name": "Summer Anthem",
+					"description": "Upbeat festival pop",
+					"genre": "pop",
+					"style": "anthemic",
+					"mood": "happy",
+					"duration": 180,
+					"structure": ["intro", "verse", "chorus", "verse", "chorus", "outro"]
+				}`))
+			})
+
+			template, err := client.Copilot.GetTemplate(context.Background(), tt.templateID)
+			if tt.wantErr {
+				var apiErr *APIError
+				if !errors.As(err, &apiErr) || apiErr.Code != "INVALID_REQUEST" || requests != 0 {
+					t.Errorf("err = %v after %d requests, want an INVALID_REQUEST error before sending", err, requests)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetTemplate: %v", err)
+			}
+			want := Template{
+				ID:          "tpl_1",
+				Name:        "Summer Anthem",
+				Description: "Upbeat festival pop",
+				Genre:       "pop",
+				Style:       "anthemic",
+				Mood:        "happy",
+				Duration:    180,
+				Structure:   []string{"intro", "verse", "chorus", "verse", "chorus", "outro"},
+			}
+			if !reflect.DeepEqual(*template, want) {
+				t.Errorf("template = %+v, want %+v", *template, want)
+			}
+		})
+	}
+}
+
+func TestCompleteSongWithProgress(t *testing.T) {
+	tests := []struct {
+		name      string
+		states    []string
+		want      []GenerationProgress
+		wantPolls int
+	}{
+		{
+			name: "progress through stages",
+			states: []string{
+				`{"id": "gen_1", "status": "pending"}`,
+				`{"id": "gen_1", "status": "processing", "stage": "composition", "progress": 0.25}`,
+				`{"id": "gen_1", "status": "processing", "stage": "composition", "progress": 0.25}`,
+				`{"id": "gen_1", "status": "processing", "stage": "mixing", "progress": 0.75}`,
+				`{"id": "gen_1", "status": "completed", "stage": "mixing", "progress": 1}`,
+			},
+			want: []GenerationProgress{
+				{GenerationID: "gen_1", Status: "pending"},
+				{GenerationID: "gen_1", Status: "processing", Stage: "composition", Progress: 0.25},
+				{GenerationID: "gen_1", Status: "processing", Stage: "mixing", Progress: 0.75},
+				{GenerationID: "gen_1", Status: "completed", Stage: "mixing", Progress: 1},
+			},
+			wantPolls: 4,
+		},
+		{
+			name:   "completed immediately",
+			states: []string{`{"id": "gen_1", "status": "completed", "progress": 1}`},
+			want:   []GenerationProgress{{GenerationID: "gen_1", Status: "completed", Progress: 1}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			polls := 0
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/v1/copilot/complete-song":
+					if fields := requestFields(t, r); fields["templateId"] != "tpl_1" {
+						t.Errorf("templateId = %q, want tpl_1", fields["templateId"])
+					}
+					writeData(t, w, json.RawMessage(tt.states[0]))
+				case "/v1/copilot/generations/gen_1":
+					polls++
+					writeData(t, w, json.RawMessage(tt.states[min(polls, len(tt.states)-1)]))
+				default:
+					t.Errorf("unexpected path %s", r.URL.Path)
+				}
+			}, WithClock(advancingClock(t)))
+
+			var got []GenerationProgress
+			generation, err := client.Copilot.CompleteSongWithProgress(context.Background(), SongOptions{TemplateID: "tpl_1"}, func(p GenerationProgress) {
+				got = append(got, p)
+			})
+			if err != nil {
+				t.Fatalf("CompleteSongWithProgress: %v", err)
+			}
+			if generation.Status != "completed" {
+				t.Errorf("generation = %+v, want completed", generation)
+			}
+			if polls != tt.wantPolls {
+				t.Errorf("polls = %d, want %d", polls, tt.wantPolls)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("progress = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("generation fails", func(t *testing.T) {
+		client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/v1/copilot/complete-song" {
+				writeData(t, w, map[string]interface{}{"id": "gen_1", "status": "processing"})
+				return
+			}
+			writeError(t, w, http.StatusNotFound, "NOT_FOUND", "Generation not found")
+		}, WithClock(advancingClock(t)), WithMaxRetries(0))
+
+		calls := 0
+		_, err := client.Copilot.CompleteSongWithProgress(context.Background(), SongOptions{TemplateID: "tpl_1"}, func(GenerationProgress) {
+			calls++
+		})
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) || apiErr.Code != "NOT_FOUND" {
+			t.Errorf("err = %v, want NOT_FOUND", err)
+		}
+		if calls != 1 {
+			t.Errorf("progress calls = %d, want 1", calls)
+		}
+	})
+}
+
+// advancingClock returns a fake clock that immediately fires every timer it
+// creates, so polling loops run without waiting
+func advancingClock(t *testing.T) *fakeClock {
+	clock := newFakeClock(time.Unix(1700000000, 0))
+	done := make(chan struct{})
+	t.Cleanup(func() { close(done) })
+	go func() {
+		for {
+			select {
+			case d := <-clock.waiting:
+				clock.Advance(d)
+			case <-done:
+				return
+			}
+		}
+	}()
+	return clock
+}
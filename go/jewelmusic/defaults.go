@@ -0,0 +1,37 @@
+package jewelmusic
+
+// Per-resource default options apply when a method is called with nil
+// options. Options passed to a call always take precedence over the
+// defaults; they are not merged field by field.
+
+// WithDefaultAnalysisOptions sets the options used by Analysis.UploadTrack
+// when called with nil options
+func WithDefaultAnalysisOptions(opts AnalysisOptions) ClientOption {
+	return func(c *Client) {
+		c.defaultAnalysisOptions = &opts
+	}
+}
+
+// WithDefaultQualityCheckOptions sets the options used by
+// Analysis.AudioQualityCheck when called with nil options
+func WithDefaultQualityCheckOptions(opts QualityCheckOptions) ClientOption {
+	return func(c *Client) {
+		c.defaultQualityCheckOptions = &opts
+	}
+}
+
+// WithDefaultUploadOptions sets the options used by Tracks.Upload when called
+// with nil options
+func WithDefaultUploadOptions(opts UploadOptions) ClientOption {
+	return func(c *Client) {
+		c.defaultUploadOptions = &opts
+	}
+}
+
+// WithDefaultTranscriptionOptions sets the options used when creating
+// transcriptions with nil options
+func WithDefaultTranscriptionOptions(opts TranscriptionOptions) ClientOption {
+	return func(c *Client) {
+		c.defaultTranscriptionOptions = &opts
+	}
+}
@@ -0,0 +1,137 @@
+package jewelmusic
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestDefaultOptions(t *testing.T) {
+	defaults := []ClientOption{
+		WithDefaultAnalysisOptions(AnalysisOptions{DetailedReport: true, CulturalContext: "latin"}),
+		WithDefaultQualityCheckOptions(QualityCheckOptions{CheckClipping: true}),
+		WithDefaultUploadOptions(UploadOptions{ChunkSize: 4096}),
+		WithDefaultTranscriptionOptions(TranscriptionOptions{Languages: []string{"es"}, IncludeTimestamps: true}),
+	}
+
+	tests := []struct {
+		name string
+		call func(ctx context.Context, client *Client) error
+		// wantDefault and wantOverride are the request fields expected with
+		// nil options and with explicit per-call options
+		wantDefault  map[string]string
+		wantOverride map[string]string
+	}{
+		{
+			name: "analysis",
+			call: func(ctx context.Context, client *Client) error {
+				_, err := client.Analysis.UploadTrack(ctx, strings.NewReader("audio"), "a.wav", nil)
+				if err != nil {
+					return err
+				}
+				_, err = client.Analysis.UploadTrack(ctx, strings.NewReader("audio"), "a.wav", &AnalysisOptions{CulturalContext: "k-pop"})
+				return err
+			},
+			wantDefault:  map[string]string{"detailedReport": "true", "culturalContext": "latin"},
+			wantOverride: map[string]string{"detailedReport": "", "culturalContext": "k-pop"},
+		},
+		{
+			name: "quality check",
+			call: func(ctx context.Context, client *Client) error {
+				_, err := client.Analysis.AudioQualityCheck(ctx, strings.NewReader("audio"), "a.wav", nil)
+				if err != nil {
+					return err
+				}
+				_, err = client.Analysis.AudioQualityCheck(ctx, strings.NewReader("audio"), "a.wav", &QualityCheckOptions{CheckPhaseIssues: true})
+				return err
+			},
+			wantDefault:  map[string]string{"checkClipping": "true", "checkPhaseIssues": ""},
+			wantOverride: map[string]string{"checkClipping": "", "checkPhaseIssues": "true"},
+		},
+		{
+			name: "upload",
+			call: func(ctx context.Context, client *Client) error {
+				metadata := TrackMetadata{Title: "Song", Artist: "Artist"}
+				_, err := client.Tracks.Upload(ctx, strings.NewReader("audio"), "a.wav", metadata, nil)
+				if err != nil {
+					return err
+				}
+				_, err = client.Tracks.Upload(ctx, strings.NewReader("audio"), "a.wav", metadata, &UploadOptions{ChunkSize: 1024})
+				return err
+			},
+			wantDefault:  map[string]string{"chunkSize": "4096"},
+			wantOverride: map[string]string{"chunkSize": "1024"},
+		},
+		{
+			name: "transcription",
+			call: func(ctx context.Context, client *Client) error {
+				_, err := client.Transcription.CreateFromURL(ctx, "https://cdn.example.com/a.wav", nil)
+				if err != nil {
+					return err
+				}
+				_, err = client.Transcription.CreateFromURL(ctx, "https://cdn.example.com/a.wav", &TranscriptionOptions{Languages: []string{"pt"}})
+				return err
+			},
+			wantDefault:  map[string]string{"languages": `["es"]`, "includeTimestamps": "true"},
+			wantOverride: map[string]string{"languages": `["pt"]`, "includeTimestamps": ""},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests []map[string]string
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				requests = append(requests, requestFields(t, r))
+				writeData(t, w, map[string]interface{}{"id": "id_1"})
+			}, defaults...)
+
+			if err := tt.call(context.Background(), client); err != nil {
+				t.Fatalf("request: %v", err)
+			}
+			if len(requests) != 2 {
+				t.Fatalf("requests = %d, want 2", len(requests))
+			}
+			for i, want := range []map[string]string{tt.wantDefault, tt.wantOverride} {
+				for field, value := range want {
+					if got := requests[i][field]; got != value {
+						t.Errorf("request %d field %s = %q, want %q", i+1, field, got, value)
+					}
+				}
+			}
+		})
+	}
+}
+
+// requestFields returns the form fields of a multipart request, or the
+// top-level JSON fields of a JSON request with non-string values encoded
+func requestFields(t *testing.T, r *http.Request) map[string]string {
+	t.Helper()
+	fields := make(map[string]string)
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/") {
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Errorf("parsing upload: %v", err)
+			return fields
+		}
+		for k, v := range r.MultipartForm.Value {
+			fields[k] = v[0]
+		}
+		return fields
+	}
+
+	var body map[string]json.RawMessage
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		t.Errorf("decoding body: %v", err)
+		return fields
+	}
+	for k, v := range body {
+		var s string
+		if json.Unmarshal(v, &s) == nil {
+			fields[k] = s
+		} else {
+			fields[k] = string(v)
+		}
+	}
+	return fields
+}
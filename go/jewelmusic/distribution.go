@@ -1,6 +1,11 @@
 package jewelmusic
 
-import "context"
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+)
 
 // DistributionResource manages music distribution to streaming platforms
 type DistributionResource struct {
@@ -20,6 +25,9 @@ type CreateReleaseOptions struct {
 	Copyright   string         `json:"copyright,omitempty"`
 	Genre       string         `json:"genre,omitempty"`
 	Explicit    bool           `json:"explicit,omitempty"`
+	// DetectExplicit runs explicit content detection on every track and sets
+	// Explicit if any track is flagged
+	DetectExplicit bool `json:"-"`
 }
 
 // SubmissionOptions represents options for platform submission
@@ -39,16 +47,35 @@ type TakedownOptions struct {
 
 // ReleaseFilter represents filters for listing releases
 type ReleaseFilter struct {
-	Status      string `json:"status,omitempty"`
-	Type        string `json:"type,omitempty"`
-	Artist      string `json:"artist,omitempty"`
-	DateFrom    string `json:"dateFrom,omitempty"`
-	DateTo      string `json:"dateTo,omitempty"`
-	Platform    string `json:"platform,omitempty"`
+	Status   string `json:"status,omitempty"`
+	Type     string `json:"type,omitempty"`
+	Artist   string `json:"artist,omitempty"`
+	DateFrom string `json:"dateFrom,omitempty"`
+	DateTo   string `json:"dateTo,omitempty"`
+	Platform string `json:"platform,omitempty"`
 }
 
-// CreateRelease creates a new release for distribution
+// CreateRelease creates a new release for distribution. Territories must be
+// ISO 3166-1 alpha-2 codes or TerritoryWorldwide; otherwise a
+// *ValidationError is returned.
 func (d *DistributionResource) CreateRelease(ctx context.Context, options CreateReleaseOptions) (*Release, error) {
+	if err := validateTerritories(options.Territories); err != nil {
+		return nil, err
+	}
+
+	if options.DetectExplicit && !options.Explicit {
+		for _, track := range options.Tracks {
+			report, err := d.client.Analysis.DetectExplicit(detachCallOptions(ctx), track.TrackID)
+			if err != nil {
+				return nil, err
+			}
+			if report.Explicit {
+				options.Explicit = true
+				break
+			}
+		}
+	}
+
 	var result Release
 	err := d.client.Post(ctx, "/distribution/releases", options, &result)
 	return &result, err
@@ -56,11 +83,8 @@ func (d *DistributionResource) CreateRelease(ctx context.Context, options Create
 
 // GetReleases lists releases with filtering and pagination
 func (d *DistributionResource) GetReleases(ctx context.Context, page, perPage int, filter *ReleaseFilter) (*ListResponse, error) {
-	params := map[string]string{
-		"page":    string(rune(page)),
-		"perPage": string(rune(perPage)),
-	}
-	
+	params := pageParams(page, perPage)
+
 	if filter != nil {
 		if filter.Status != "" {
 			params["status"] = filter.Status
@@ -115,11 +139,89 @@ func (d *DistributionResource) SubmitToPlatforms(ctx context.Context, releaseID
 	return result, err
 }
 
-// GetDistributionStatus gets the distribution status of a release
-func (d *DistributionResource) GetDistributionStatus(ctx context.Context, releaseID string) (map[string]interface{}, error) {
-	var result map[string]interface{}
-	err := d.client.Get(ctx, "/distribution/releases/"+releaseID+"/status", nil, &result)
-	return result, err
+// GetDistributionStatus gets the distribution status of a release. When the
+// API does not list per-platform errors separately, they are collected from
+// the platforms that report one.
+func (d *DistributionResource) GetDistributionStatus(ctx context.Context, releaseID string) (*DistributionStatus, error) {
+	var result DistributionStatus
+	if err := d.client.Get(ctx, "/distribution/releases/"+releaseID+"/status", nil, &result); err != nil {
+		return nil, err
+	}
+
+	if len(result.Errors) == 0 {
+		for _, platform := range result.Platforms {
+			if platform.Error != "" || platform.ErrorCode != "" {
+				result.Errors = append(result.Errors, PlatformError{
+					Platform: platform.Platform,
+					Code:     platform.ErrorCode,
+					Message:  platform.Error,
+				})
+			}
+		}
+	}
+	return &result, nil
+}
+
+// validateTerritories checks that every territory is a known territory code
+func validateTerritories(territories []string) error {
+	var messages []string
+	for _, territory := range territories {
+		if !IsValidTerritoryCode(territory) {
+			messages = append(messages, fmt.Sprintf("%q is not an ISO 3166-1 alpha-2 code", territory))
+		}
+	}
+	if len(messages) == 0 {
+		return nil
+	}
+	return &ValidationError{
+		APIError: &APIError{Code: "VALIDATION_ERROR", Message: "Invalid territory codes"},
+		Fields:   map[string][]string{"territories": messages},
+	}
+}
+
+// EstimateGoLive returns the expected go-live time of a release on each of
+// the given platforms, which differ in lead time. No platforms means every
+// platform the release is submitted to.
+func (d *DistributionResource) EstimateGoLive(ctx context.Context, releaseID string, platforms []string) (map[string]time.Time, error) {
+	var lists map[string][]string
+	if len(platforms) > 0 {
+		lists = map[string][]string{"platforms": platforms}
+	}
+
+	var result []struct {
+		Platform string    `json:"platform"`
+		GoLiveAt time.Time `json:"goLiveAt"`
+	}
+	if err := d.client.getWithLists(ctx, "/distribution/releases/"+releaseID+"/go-live", nil, lists, &result); err != nil {
+		return nil, err
+	}
+
+	estimates := make(map[string]time.Time, len(result))
+	for _, estimate := range result {
+		estimates[estimate.Platform] = estimate.GoLiveAt
+	}
+	return estimates, nil
+}
+
+// UploadReleaseArtwork uploads the cover art of a release, separate from the
+// artwork of its tracks. The image must be a square JPEG or PNG of at least
+// MinArtworkSize pixels; other images are rejected before uploading.
+func (d *DistributionResource) UploadReleaseArtwork(ctx context.Context, releaseID string, file io.Reader, filename string) (*ArtworkResult, error) {
+	artwork, err := validateArtwork(file)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := d.client.UploadFile(ctx, "/distribution/releases/"+releaseID+"/artwork", artwork, filename, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var result ArtworkResult
+	if err := resp.DecodeData(&result); err != nil {
+		return nil, err
+	}
+	return &result, nil
 }
 
 // TakedownFromPlatforms removes a release from platforms
@@ -156,4 +258,4 @@ func (d *DistributionResource) GeneratePreview(ctx context.Context, releaseID st
 	var result map[string]interface{}
 	err := d.client.Post(ctx, "/distribution/releases/"+releaseID+"/preview", nil, &result)
 	return result, err
-}
\ No newline at end of file
+}
@@ -0,0 +1,351 @@
+package jewelmusic
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCreateReleaseDetectsExplicit(t *testing.T) {
+	tests := []struct {
+		name         string
+		explicit     map[string]bool
+		options      CreateReleaseOptions
+		wantChecked  int
+		wantExplicit bool
+	}{
+		{
+			name:         "flagged track",
+			explicit:     map[string]bool{"trk_1": false, "trk_2": true, "trk_3": false},
+			options:      CreateReleaseOptions{DetectExplicit: true},
+			wantChecked:  2,
+			wantExplicit: true,
+		},
+		{
+			name:        "clean tracks",
+			explicit:    map[string]bool{"trk_1": false, "trk_2": false, "trk_3": false},
+			options:     CreateReleaseOptions{DetectExplicit: true},
+			wantChecked: 3,
+		},
+		{
+			name:         "already explicit",
+			explicit:     map[string]bool{"trk_1": false, "trk_2": false, "trk_3": false},
+			options:      CreateReleaseOptions{DetectExplicit: true, Explicit: true},
+			wantExplicit: true,
+		},
+		{
+			name:     "detection off",
+			explicit: map[string]bool{"trk_1": true, "trk_2": true, "trk_3": true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var checked int
+			var release map[string]interface{}
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				var body map[string]interface{}
+				if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+					t.Errorf("decoding body: %v", err)
+				}
+				switch r.URL.Path {
+				case "/v1/analysis/explicit":
+					checked++
+					trackID, _ := body["trackId"].(string)
+					writeData(t, w, map[string]interface{}{"trackId": trackID, "explicit": tt.explicit[trackID]})
+				case "/v1/distribution/releases":
+					release = body
+					writeData(t, w, map[string]interface{}{"id": "rel_1"})
+				default:
+					t.Errorf("unexpected path %s", r.URL.Path)
+				}
+			})
+
+			options := tt.options
+			options.Title, options.Artist, options.Type = "Album", "Artist", "album"
+			options.Tracks = []ReleaseTrack{{TrackID: "trk_1"}, {TrackID: "trk_2"}, {TrackID: "trk_3"}}
+			if _, err := client.Distribution.CreateRelease(context.Background(), options); err != nil {
+				t.Fatalf("CreateRelease: %v", err)
+			}
+			if checked != tt.wantChecked {
+				t.Errorf("tracks checked = %d, want %d", checked, tt.wantChecked)
+			}
+			if explicit, _ := release["explicit"].(bool); explicit != tt.wantExplicit {
+				t.Errorf("release explicit = %v, want %v", explicit, tt.wantExplicit)
+			}
+			if _, ok := release["detectExplicit"]; ok {
+				t.Error("detectExplicit sent to the server")
+			}
+		})
+	}
+}
+
+func TestGetDistributionStatusPlatformErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		response   string
+		wantErrors []PlatformError
+	}{
+		{
+			name: "errors on platforms",
+			response: `{
+				"releaseId": "rel_1",
+				"status": "partially_live",
+				"platforms": [
+					{"platform": "spotify", "status": "live", "liveDate": "2026-10-01", "storeUrl": "https://open.spotify.com/album/1"},
+					{"platform": "apple-music", "status": "rejected", "errorCode": "ARTWORK_TEXT", "error": "Artwork contains a URL"},
+					{"platform": "deezer", "status": "processing"}
+				]
+			}`,
+			wantErrors: []PlatformError{{Platform: "apple-music", Code: "ARTWORK_TEXT", Message: "Artwork contains a URL"}},
+		},
+		{
+			name: "errors listed separately",
+			response: `{
+				"releaseId": "rel_1",
+				"status": "partially_live",
+				"platforms": [
+					{"platform": "spotify", "status": "live"},
+					{"platform": "apple-music", "status": "rejected", "error": "Rejected"},
+					{"platform": "deezer", "status": "live"}
+				],
+				"errors": [{"platform": "apple-music", "code": "ARTWORK_TEXT", "message": "Artwork contains a URL"}]
+			}`,
+			wantErrors: []PlatformError{{Platform: "apple-music", Code: "ARTWORK_TEXT", Message: "Artwork contains a URL"}},
+		},
+		{
+			name:     "all accepted",
+			response: `{"releaseId": "rel_1", "status": "live", "platforms": [{"platform": "spotify", "status": "live"}]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/v1/distribution/releases/rel_1/status" {
+					t.Errorf("path = %s", r.URL.Path)
+				}
+				writeData(t, w, json.RawMessage(tt.response))
+			})
+
+			status, err := client.Distribution.GetDistributionStatus(context.Background(), "rel_1")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if status.ReleaseID != "rel_1" || len(status.Platforms) == 0 {
+				t.Errorf("status = %+v", status)
+			}
+			if len(status.Errors) != len(tt.wantErrors) {
+				t.Fatalf("errors = %+v, want %+v", status.Errors, tt.wantErrors)
+			}
+			for i, want := range tt.wantErrors {
+				if status.Errors[i] != want {
+					t.Errorf("error %d = %+v, want %+v", i, status.Errors[i], want)
+				}
+			}
+		})
+	}
+}
+
+// encodeImage returns a blank width x height image in format
+func encodeImage(t *testing.T, format string, width, height int) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	img := image.NewGray(image.Rect(0, 0, width, height))
+	var err error
+	if format == "jpeg" {
+		err = jpeg.Encode(&buf, img, nil)
+	} else {
+		err = png.Encode(&buf, img)
+	}
+	if err != nil {
+		t.Fatalf("encoding %s: %v", format, err)
+	}
+	return buf.Bytes()
+}
+
+func TestUploadReleaseArtwork(t *testing.T) {
+	tests := []struct {
+		name    string
+		image   []byte
+		wantErr bool
+	}{
+		{name: "square png", image: encodeImage(t, "png", MinArtworkSize, MinArtworkSize)},
+		{name: "large jpeg", image: encodeImage(t, "jpeg", 3000, 3000)},
+		{name: "not square", image: encodeImage(t, "png", 3000, 1400), wantErr: true},
+		{name: "too small", image: encodeImage(t, "jpeg", 1000, 1000), wantErr: true},
+		{name: "not an image", image: []byte("GIF89a not supported"), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var uploaded []byte
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/v1/distribution/releases/rel_1/artwork" {
+					t.Errorf("path = %s", r.URL.Path)
+				}
+				file, _, err := r.FormFile("file")
+				if err != nil {
+					t.Errorf("reading file part: %v", err)
+					return
+				}
+				uploaded, _ = io.ReadAll(file)
+				writeData(t, w, map[string]interface{}{"url": "https://cdn.example.com/rel_1/cover.png", "width": 3000, "height": 3000, "format": "png"})
+			})
+
+			result, err := client.Distribution.UploadReleaseArtwork(context.Background(), "rel_1", bytes.NewReader(tt.image), "cover.png")
+			if tt.wantErr {
+				var apiErr *APIError
+				if !errors.As(err, &apiErr) || apiErr.Code != "INVALID_REQUEST" {
+					t.Errorf("err = %v, want an INVALID_REQUEST error", err)
+				}
+				if uploaded != nil {
+					t.Error("invalid artwork was uploaded")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !bytes.Equal(uploaded, tt.image) {
+				t.Errorf("uploaded %d bytes, want the complete %d byte image", len(uploaded), len(tt.image))
+			}
+			want := ArtworkResult{URL: "https://cdn.example.com/rel_1/cover.png", Width: 3000, Height: 3000, Format: "png"}
+			if !reflect.DeepEqual(*result, want) {
+				t.Errorf("result = %+v, want %+v", *result, want)
+			}
+		})
+	}
+}
+
+func TestCreateReleaseValidatesTerritories(t *testing.T) {
+	tests := []struct {
+		name        string
+		territories []string
+		wantInvalid []string
+	}{
+		{name: "country codes", territories: []string{"US", "GB", "JP", "BR"}},
+		{name: "worldwide", territories: []string{TerritoryWorldwide}},
+		{name: "none", territories: nil},
+		{name: "alpha-3 code", territories: []string{"US", "USA"}, wantInvalid: []string{"USA"}},
+		{name: "lowercase and unknown", territories: []string{"gb", "XX", "DE"}, wantInvalid: []string{"gb", "XX"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sent []string
+			requests := 0
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				var body struct {
+					Territories []string `json:"territories"`
+				}
+				if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+					t.Errorf("decoding body: %v", err)
+				}
+				sent = body.Territories
+				writeData(t, w, map[string]interface{}{"id": "rel_1"})
+			})
+
+			_, err := client.Distribution.CreateRelease(context.Background(), CreateReleaseOptions{
+				Type:        "single",
+				Title:       "Single",
+				Artist:      "Artist",
+				Tracks:      []ReleaseTrack{{TrackID: "trk_1"}},
+				Territories: tt.territories,
+			})
+			if tt.wantInvalid == nil {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if len(sent) != len(tt.territories) {
+					t.Errorf("territories sent = %v, want %v", sent, tt.territories)
+				}
+				return
+			}
+
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("err = %v, want a *ValidationError", err)
+			}
+			messages := validationErr.Fields["territories"]
+			if len(messages) != len(tt.wantInvalid) {
+				t.Fatalf("messages = %v, want one for each of %v", messages, tt.wantInvalid)
+			}
+			for i, code := range tt.wantInvalid {
+				if !strings.Contains(messages[i], `"`+code+`"`) {
+					t.Errorf("message %q does not name %s", messages[i], code)
+				}
+			}
+			if requests != 0 {
+				t.Error("release with invalid territories was sent")
+			}
+		})
+	}
+}
+
+func TestEstimateGoLive(t *testing.T) {
+	const payload = `[
+		{"platform": "spotify", "goLiveAt": "2024-03-01T00:00:00Z"},
+		{"platform": "apple", "goLiveAt": "2024-03-04T09:30:00+01:00"},
+		{"platform": "tidal", "goLiveAt": "2024-03-08T00:00:00Z"}
+	]`
+	want := map[string]time.Time{
+		"spotify": time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+		"apple":   time.Date(2024, 3, 4, 8, 30, 0, 0, time.UTC),
+		"tidal":   time.Date(2024, 3, 8, 0, 0, 0, 0, time.UTC),
+	}
+
+	tests := []struct {
+		name      string
+		platforms []string
+		opts      []ClientOption
+		wantQuery []string
+	}{
+		{name: "all platforms"},
+		{name: "selected platforms", platforms: []string{"spotify", "apple", "tidal"}, wantQuery: []string{"spotify,apple,tidal"}},
+		{
+			name:      "repeated parameters",
+			platforms: []string{"spotify", "apple", "tidal"},
+			opts:      []ClientOption{WithListParamStyle(ListParamRepeat)},
+			wantQuery: []string{"spotify", "apple", "tidal"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodGet || r.URL.Path != "/v1/distribution/releases/rel_1/go-live" {
+					t.Errorf("request = %s %s", r.Method, r.URL.Path)
+				}
+				if got := r.URL.Query()["platforms"]; !reflect.DeepEqual(got, tt.wantQuery) {
+					t.Errorf("platforms = %q, want %q", got, tt.wantQuery)
+				}
+				writeData(t, w, json.RawMessage(payload))
+			}, tt.opts...)
+
+			got, err := client.Distribution.EstimateGoLive(context.Background(), "rel_1", tt.platforms)
+			if err != nil {
+				t.Fatalf("EstimateGoLive: %v", err)
+			}
+			if len(got) != len(want) {
+				t.Fatalf("estimates = %v, want %v", got, want)
+			}
+			for platform, wantTime := range want {
+				if !got[platform].Equal(wantTime) {
+					t.Errorf("%s go-live = %v, want %v", platform, got[platform], wantTime)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package jewelmusic
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+)
+
+// ErrAccepted is matched by errors returned when the API accepted a request
+// for asynchronous processing without returning a result
+var ErrAccepted = errors.New("jewelmusic: request accepted for asynchronous processing")
+
+// AcceptedError is returned for 202 Accepted responses that carry no data.
+// JobID identifies the asynchronous job and is taken from the last segment
+// of the Location header. 202 responses with data are decoded as usual.
+type AcceptedError struct {
+	JobID     string
+	Location  string
+	RequestID string
+}
+
+// Error implements the error interface
+func (e *AcceptedError) Error() string {
+	if e.JobID != "" {
+		return ErrAccepted.Error() + ": job " + e.JobID
+	}
+	return ErrAccepted.Error()
+}
+
+// Is reports whether target is ErrAccepted
+func (e *AcceptedError) Is(target error) bool {
+	return target == ErrAccepted
+}
+
+// acceptedError builds an AcceptedError from a 202 response
+func acceptedError(resp *http.Response, requestID string) *AcceptedError {
+	location := resp.Header.Get("Location")
+	var jobID string
+	if location != "" {
+		jobID = path.Base(strings.TrimSuffix(location, "/"))
+	}
+	return &AcceptedError{JobID: jobID, Location: location, RequestID: requestID}
+}
+
+// ErrQuotaExceeded is matched by errors returned when a batch would exceed
+// the account's remaining quota
+var ErrQuotaExceeded = errors.New("jewelmusic: quota exceeded")
+
+// QuotaExceededError is returned by batch helpers that were rejected before
+// starting because the batch is larger than the remaining quota
+type QuotaExceededError struct {
+	Resource  string
+	Requested int
+	Remaining int
+}
+
+// Error implements the error interface
+func (e *QuotaExceededError) Error() string {
+	return fmt.Sprintf("%s: %d %s requested, %d remaining", ErrQuotaExceeded.Error(), e.Requested, e.Resource, e.Remaining)
+}
+
+// Is reports whether target is ErrQuotaExceeded
+func (e *QuotaExceededError) Is(target error) bool {
+	return target == ErrQuotaExceeded
+}
+
+// ValidationError is returned when the API rejects a request as invalid,
+// either with HTTP 422 or a VALIDATION_ERROR code. Fields maps each invalid
+// field to its messages. It unwraps to the underlying *APIError.
+type ValidationError struct {
+	*APIError
+	Fields map[string][]string
+}
+
+// Error implements the error interface
+func (e *ValidationError) Error() string {
+	if len(e.Fields) == 0 {
+		return e.APIError.Error()
+	}
+	names := make([]string, 0, len(e.Fields))
+	for name := range e.Fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		parts = append(parts, fmt.Sprintf("%s: %s", name, strings.Join(e.Fields[name], "; ")))
+	}
+	return e.APIError.Error() + " [" + strings.Join(parts, ", ") + "]"
+}
+
+// Unwrap returns the underlying API error
+func (e *ValidationError) Unwrap() error {
+	return e.APIError
+}
+
+// typedError converts an API error into the most specific error type for
+// its status code
+func typedError(apiErr *APIError) error {
+	if apiErr.StatusCode == http.StatusUnprocessableEntity || apiErr.Code == "VALIDATION_ERROR" {
+		return &ValidationError{APIError: apiErr, Fields: validationFields(apiErr.Details)}
+	}
+	return apiErr
+}
+
+// validationFields extracts per-field messages from error details. Details
+// may map fields directly to messages or nest them under "fields" or "errors".
+func validationFields(details map[string]interface{}) map[string][]string {
+	for _, key := range []string{"fields", "errors"} {
+		if nested, ok := details[key].(map[string]interface{}); ok {
+			details = nested
+			break
+		}
+	}
+
+	fields := make(map[string][]string)
+	for name, value := range details {
+		switch v := value.(type) {
+		case string:
+			fields[name] = append(fields[name], v)
+		case []interface{}:
+			for _, item := range v {
+				if message, ok := item.(string); ok {
+					fields[name] = append(fields[name], message)
+				}
+			}
+		}
+	}
+	return fields
+}
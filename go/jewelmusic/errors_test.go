@@ -0,0 +1,157 @@
+package jewelmusic
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestUploadValidationError(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     int
+		code       string
+		details    map[string]interface{}
+		wantFields map[string][]string
+	}{
+		{
+			name:       "flat details",
+			status:     http.StatusUnprocessableEntity,
+			code:       "UNPROCESSABLE",
+			details:    map[string]interface{}{"file": "Unsupported audio format", "duration": []string{"Too long", "Max 20 minutes"}},
+			wantFields: map[string][]string{"file": {"Unsupported audio format"}, "duration": {"Too long", "Max 20 minutes"}},
+		},
+		{
+			name:       "nested fields",
+			status:     http.StatusUnprocessableEntity,
+			code:       "VALIDATION_ERROR",
+			details:    map[string]interface{}{"fields": map[string]interface{}{"sampleRate": []string{"Below 44.1 kHz"}}},
+			wantFields: map[string][]string{"sampleRate": {"Below 44.1 kHz"}},
+		},
+		{
+			name:       "validation code on 400",
+			status:     http.StatusBadRequest,
+			code:       "VALIDATION_ERROR",
+			details:    map[string]interface{}{"errors": map[string]interface{}{"file": "Empty file"}},
+			wantFields: map[string][]string{"file": {"Empty file"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				if _, _, err := r.FormFile("file"); err != nil {
+					t.Errorf("reading file part: %v", err)
+				}
+				w.Header().Set("Content-Type", "application/json")
+				w.Header().Set("X-Request-ID", "req_422")
+				w.WriteHeader(tt.status)
+				json.NewEncoder(w).Encode(map[string]interface{}{
+					"success": false,
+					"error":   map[string]interface{}{"code": tt.code, "message": "Upload rejected", "details": tt.details},
+				})
+			})
+
+			_, err := client.UploadFile(context.Background(), "/tracks/upload", strings.NewReader("audio"), "song.ogg", nil)
+
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("error = %T %v, want *ValidationError", err, err)
+			}
+			if validationErr.StatusCode != tt.status || validationErr.RequestID != "req_422" {
+				t.Errorf("status = %d, request ID = %q", validationErr.StatusCode, validationErr.RequestID)
+			}
+			if len(validationErr.Fields) != len(tt.wantFields) {
+				t.Errorf("fields = %v, want %v", validationErr.Fields, tt.wantFields)
+			}
+			for field, want := range tt.wantFields {
+				if got := validationErr.Fields[field]; strings.Join(got, "|") != strings.Join(want, "|") {
+					t.Errorf("fields[%s] = %v, want %v", field, got, want)
+				}
+			}
+
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) || apiErr.Code != tt.code {
+				t.Errorf("error does not unwrap to the APIError: %v", err)
+			}
+		})
+	}
+}
+
+func TestAcceptedResponse(t *testing.T) {
+	tests := []struct {
+		name         string
+		location     string
+		body         string
+		wantJobID    string
+		wantAccepted bool
+		wantStatus   string
+	}{
+		{
+			name:         "empty body",
+			location:     "/v1/analysis/ana_9",
+			wantJobID:    "ana_9",
+			wantAccepted: true,
+		},
+		{
+			name:         "envelope without data",
+			location:     "https://api.jewelmusic.art/v1/analysis/ana_9/",
+			body:         `{"success":true,"data":null}`,
+			wantJobID:    "ana_9",
+			wantAccepted: true,
+		},
+		{
+			name:         "no location",
+			body:         `{"success":true}`,
+			wantAccepted: true,
+		},
+		{
+			name:       "with data",
+			location:   "/v1/analysis/ana_9",
+			body:       `{"success":true,"data":{"id":"ana_9","status":"queued"}}`,
+			wantStatus: "queued",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("X-Request-ID", "req_202")
+				if tt.location != "" {
+					w.Header().Set("Location", tt.location)
+				}
+				w.WriteHeader(http.StatusAccepted)
+				w.Write([]byte(tt.body))
+			})
+
+			var result map[string]interface{}
+			err := client.Post(context.Background(), "/analysis", map[string]string{"trackId": "trk_1"}, &result)
+			if !tt.wantAccepted {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if result["status"] != tt.wantStatus {
+					t.Errorf("status = %v, want %q", result["status"], tt.wantStatus)
+				}
+				return
+			}
+
+			if !errors.Is(err, ErrAccepted) {
+				t.Fatalf("err = %v, want ErrAccepted", err)
+			}
+			var accepted *AcceptedError
+			if !errors.As(err, &accepted) {
+				t.Fatalf("err = %T, want *AcceptedError", err)
+			}
+			if accepted.JobID != tt.wantJobID || accepted.Location != tt.location || accepted.RequestID != "req_202" {
+				t.Errorf("accepted = %+v, want job %q at %q", accepted, tt.wantJobID, tt.location)
+			}
+			if tt.wantJobID != "" && !strings.Contains(err.Error(), tt.wantJobID) {
+				t.Errorf("message %q does not mention job %s", err.Error(), tt.wantJobID)
+			}
+		})
+	}
+}
@@ -6,9 +6,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"mime"
 	"mime/multipart"
 	"net/http"
+	"net/textproto"
 	"net/url"
+	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
@@ -40,8 +44,8 @@ func NewHTTPClient(baseURL, apiKey string, httpClient *http.Client) *HTTPClient
 
 // APIResponse represents a standard API response
 type APIResponse struct {
-	Success bool        `json:"success"`
-	Data    interface{} `json:"data"`
+	Success bool            `json:"success"`
+	Data    json.RawMessage `json:"data"`
 	Meta    struct {
 		Timestamp string `json:"timestamp"`
 		RequestID string `json:"requestId"`
@@ -54,22 +58,97 @@ type APIResponse struct {
 	Error *APIError `json:"error,omitempty"`
 }
 
+// DecodeData decodes the response data into v
+func (r *APIResponse) DecodeData(v interface{}) error {
+	return decodeData(r.Data, v)
+}
+
+// exactNumbers wraps a result whose interface{} values should keep numbers
+// as json.Number, so that large integers such as analytics stream counts
+// don't lose precision in a float64
+type exactNumbers struct{ v interface{} }
+
+// decodeData decodes raw response data into v, which may be wrapped in
+// exactNumbers
+func decodeData(data json.RawMessage, v interface{}) error {
+	if isEmptyData(data) {
+		return nil
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	if exact, ok := v.(exactNumbers); ok {
+		v = exact.v
+		decoder.UseNumber()
+	}
+	if err := decoder.Decode(v); err != nil {
+		return fmt.Errorf("failed to unmarshal response data: %w", err)
+	}
+	return nil
+}
+
 // APIError represents an API error response
 type APIError struct {
-	Code    string                 `json:"code"`
-	Message string                 `json:"message"`
-	Details map[string]interface{} `json:"details"`
+	Code       string                 `json:"code"`
+	Message    string                 `json:"message"`
+	Details    map[string]interface{} `json:"details"`
+	StatusCode int                    `json:"-"`
+	RequestID  string                 `json:"-"`
 }
 
 // Error implements the error interface
 func (e *APIError) Error() string {
+	if e.RequestID != "" {
+		return fmt.Sprintf("API Error %s: %s (request ID: %s)", e.Code, e.Message, e.RequestID)
+	}
 	return fmt.Sprintf("API Error %s: %s", e.Code, e.Message)
 }
 
+// requestID returns the request ID of a response, preferring the one in the
+// response metadata over the X-Request-ID header
+func requestID(resp *http.Response, apiResp *APIResponse) string {
+	if apiResp.Meta.RequestID != "" {
+		return apiResp.Meta.RequestID
+	}
+	return resp.Header.Get("X-Request-ID")
+}
+
+// setAuthHeader sets the API key header on req using the configured scheme
+func (c *Client) setAuthHeader(req *http.Request) {
+	name, format := c.authHeader, c.authFormat
+	if name == "" {
+		name, format = "Authorization", "Bearer %s"
+	} else if format == "" {
+		format = "%s"
+	}
+	req.Header.Set(name, fmt.Sprintf(format, c.apiKey))
+}
+
+// buildURL composes the full request URL from the base URL, optional base
+// path, API version, and resource path
+func (c *Client) buildURL(path string) string {
+	var b strings.Builder
+	b.WriteString(strings.TrimSuffix(c.baseURL, "/"))
+	if basePath := strings.Trim(c.basePath, "/"); basePath != "" {
+		b.WriteString("/" + basePath)
+	}
+	if version := strings.Trim(c.apiVersion, "/"); version != "" {
+		b.WriteString("/" + version)
+	}
+	if path != "" && !strings.HasPrefix(path, "/") {
+		b.WriteString("/")
+	}
+	b.WriteString(path)
+	return b.String()
+}
+
 // makeRequest performs an HTTP request with retries and error handling
-func (c *Client) makeRequest(ctx context.Context, method, path string, body interface{}, result interface{}) error {
+func (c *Client) makeRequest(ctx context.Context, method, path string, body interface{}, result interface{}) (err error) {
+	var audited auditRecord
+	if c.auditing(method) {
+		defer func() { c.audit(method, path, audited, err) }()
+	}
+
 	// Build URL
-	url := c.baseURL + "/v1" + path
+	url := c.buildURL(path)
 
 	// Prepare request body
 	var bodyReader io.Reader
@@ -81,6 +160,14 @@ func (c *Client) makeRequest(ctx context.Context, method, path string, body inte
 			// For file uploads
 			bodyReader = strings.NewReader("") // Placeholder, actual implementation would be different
 			contentType = v.FormDataContentType()
+		case mergePatch:
+			// JSON merge patch body
+			jsonBody, err := json.Marshal(map[string]interface{}(v))
+			if err != nil {
+				return fmt.Errorf("failed to marshal request body: %w", err)
+			}
+			bodyReader = bytes.NewReader(jsonBody)
+			contentType = "application/merge-patch+json"
 		default:
 			// JSON body
 			jsonBody, err := json.Marshal(body)
@@ -99,20 +186,30 @@ func (c *Client) makeRequest(ctx context.Context, method, path string, body inte
 	}
 
 	// Set headers
-	req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	c.setAuthHeader(req)
 	req.Header.Set("User-Agent", c.httpClient.(*HTTPClient).userAgent)
 	req.Header.Set("Accept", "application/json")
-	
+	if since := ifModifiedSince(ctx); method == "GET" && !since.IsZero() {
+		req.Header.Set("If-Modified-Since", since.UTC().Format(http.TimeFormat))
+	}
+
 	if contentType != "" {
 		req.Header.Set("Content-Type", contentType)
 	}
 
 	// Perform request with retries
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.doRequest(req, path)
 	if err != nil {
 		return fmt.Errorf("request failed: %w", err)
 	}
 	defer resp.Body.Close()
+	audited.statusCode = resp.StatusCode
+	audited.requestID = resp.Header.Get("X-Request-ID")
+
+	if resp.StatusCode == http.StatusNotModified {
+		recordResponseMeta(ctx, resp.Header.Get("X-Request-ID"), resp)
+		return ErrNotModified
+	}
 
 	// Read response body
 	respBody, err := io.ReadAll(resp.Body)
@@ -120,43 +217,174 @@ func (c *Client) makeRequest(ctx context.Context, method, path string, body inte
 		return fmt.Errorf("failed to read response body: %w", err)
 	}
 
+	// Accepted for asynchronous processing without a body
+	if resp.StatusCode == http.StatusAccepted && len(bytes.TrimSpace(respBody)) == 0 {
+		reqID := resp.Header.Get("X-Request-ID")
+		recordResponseMeta(ctx, reqID, resp)
+		return acceptedError(resp, reqID)
+	}
+
+	// No content, e.g. a 204 response to DELETE, leaves result untouched
+	if resp.StatusCode == http.StatusNoContent || len(bytes.TrimSpace(respBody)) == 0 {
+		recordResponseMeta(ctx, resp.Header.Get("X-Request-ID"), resp)
+		if resp.StatusCode >= 400 {
+			return fmt.Errorf("API request failed with status %d", resp.StatusCode)
+		}
+		return nil
+	}
+
 	// Parse response
 	var apiResp APIResponse
 	if err := json.Unmarshal(respBody, &apiResp); err != nil {
 		return fmt.Errorf("failed to parse response: %w", err)
 	}
 
+	reqID := requestID(resp, &apiResp)
+	recordResponseMeta(ctx, reqID, resp)
+	audited.requestID = reqID
+	audited.data = apiResp.Data
+	if c.limiter != nil {
+		rateLimit := apiResp.Meta.RateLimit
+		c.limiter.update(c.clock.Now(), rateLimit.Limit, rateLimit.Remaining, rateLimit.Reset)
+	}
+
 	// Handle errors
 	if resp.StatusCode >= 400 {
 		if apiResp.Error != nil {
-			return apiResp.Error
+			apiResp.Error.StatusCode = resp.StatusCode
+			apiResp.Error.RequestID = reqID
+			return typedError(apiResp.Error)
 		}
 		return fmt.Errorf("API request failed with status %d", resp.StatusCode)
 	}
 
+	// A 202 without data only carries a reference to the asynchronous job
+	if resp.StatusCode == http.StatusAccepted && isEmptyData(apiResp.Data) {
+		return acceptedError(resp, reqID)
+	}
+
 	// Extract data if result is provided
-	if result != nil && apiResp.Data != nil {
-		dataBytes, err := json.Marshal(apiResp.Data)
-		if err != nil {
-			return fmt.Errorf("failed to marshal response data: %w", err)
+	if result != nil {
+		if err := decodeData(apiResp.Data, result); err != nil {
+			return err
 		}
+	}
 
-		if err := json.Unmarshal(dataBytes, result); err != nil {
-			return fmt.Errorf("failed to unmarshal response data: %w", err)
+	return nil
+}
+
+// isEmptyData reports whether response data is absent
+func isEmptyData(data json.RawMessage) bool {
+	return len(data) == 0 || string(data) == "null"
+}
+
+// send performs a single attempt of a prepared request, throttling, limiting
+// concurrency, signing, and tracing it if enabled and reporting it to
+// registered observers
+func (c *Client) send(req *http.Request, path string) (*http.Response, error) {
+	if c.limiter != nil {
+		if err := c.limiter.wait(req.Context(), c.clock); err != nil {
+			return nil, err
 		}
 	}
 
-	return nil
+	if c.signingSecret != "" {
+		if err := c.signRequest(req); err != nil {
+			return nil, err
+		}
+	}
+
+	if c.tracer != nil {
+		if c.authHeader != "" {
+			c.tracer.traceRequest(req, c.authHeader)
+		} else {
+			c.tracer.traceRequest(req)
+		}
+	}
+
+	if err := c.acquireSlot(req.Context()); err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	resp, err := c.httpClient.Do(req)
+	duration := time.Since(start)
+
+	statusCode := 0
+	if resp != nil {
+		statusCode = resp.StatusCode
+		if c.limiter != nil {
+			c.limiter.updateFromHeaders(c.clock.Now(), resp.Header)
+		}
+		if c.tracer != nil {
+			c.tracer.traceResponse(resp)
+		}
+	}
+
+	// Tracing replaces the body, so wrap it afterwards
+	if err != nil {
+		c.releaseSlot()
+	} else if c.requestSlots != nil {
+		resp.Body = &slotBody{ReadCloser: resp.Body, release: c.releaseSlot}
+	}
+	c.observe(req.Method, path, statusCode, duration, err)
+
+	return resp, err
+}
+
+// ListParamStyle controls how slice values are encoded in query strings
+type ListParamStyle int
+
+const (
+	// ListParamCSV joins values with commas: platforms=spotify,apple
+	ListParamCSV ListParamStyle = iota
+	// ListParamRepeat repeats the key for each value: platforms=spotify&platforms=apple
+	ListParamRepeat
+)
+
+// encodeQuery encodes scalar and multi-valued parameters into a query string
+func encodeQuery(params map[string]string, lists map[string][]string, style ListParamStyle) string {
+	query := url.Values{}
+	for k, v := range params {
+		query.Set(k, v)
+	}
+	for k, values := range lists {
+		if len(values) == 0 {
+			continue
+		}
+		if style == ListParamRepeat {
+			for _, v := range values {
+				query.Add(k, v)
+			}
+		} else {
+			query.Set(k, strings.Join(values, ","))
+		}
+	}
+	return query.Encode()
 }
 
 // Get performs a GET request
 func (c *Client) Get(ctx context.Context, path string, params map[string]string, result interface{}) error {
-	if params != nil && len(params) > 0 {
-		query := url.Values{}
+	return c.getWithLists(ctx, path, params, nil, result)
+}
+
+// getWithLists performs a GET request with additional multi-valued parameters
+// encoded according to the client's list parameter style
+func (c *Client) getWithLists(ctx context.Context, path string, params map[string]string, lists map[string][]string, result interface{}) error {
+	if extra := extraParams(ctx); len(extra) > 0 {
+		merged := make(map[string]string, len(params)+len(extra))
+		for k, v := range extra {
+			if _, ok := lists[k]; !ok {
+				merged[k] = v
+			}
+		}
 		for k, v := range params {
-			query.Add(k, v)
+			merged[k] = v
 		}
-		path += "?" + query.Encode()
+		params = merged
+	}
+	if query := encodeQuery(params, lists, c.listParamStyle); query != "" {
+		path += "?" + query
 	}
 	return c.makeRequest(ctx, "GET", path, nil, result)
 }
@@ -171,55 +399,205 @@ func (c *Client) Put(ctx context.Context, path string, body interface{}, result
 	return c.makeRequest(ctx, "PUT", path, body, result)
 }
 
+// mergePatch is a request body sent as a JSON merge patch (RFC 7396)
+type mergePatch map[string]interface{}
+
+// Patch performs a PATCH request with a JSON merge patch body
+func (c *Client) Patch(ctx context.Context, path string, patch map[string]interface{}, result interface{}) error {
+	return c.makeRequest(ctx, "PATCH", path, mergePatch(patch), result)
+}
+
 // Delete performs a DELETE request
 func (c *Client) Delete(ctx context.Context, path string, result interface{}) error {
 	return c.makeRequest(ctx, "DELETE", path, nil, result)
 }
 
-// UploadFile uploads a file with metadata
-func (c *Client) UploadFile(ctx context.Context, path string, file io.Reader, filename string, metadata map[string]string) (*APIResponse, error) {
-	var buf bytes.Buffer
-	writer := multipart.NewWriter(&buf)
+// openStream performs a GET request and returns the raw response body for
+// the caller to stream. Error responses are decoded like JSON requests.
+func (c *Client) openStream(ctx context.Context, path string) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", c.buildURL(path), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	c.setAuthHeader(req)
+
+	resp, err := c.doRequest(req, path)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	if resp.StatusCode < 400 {
+		recordResponseMeta(ctx, resp.Header.Get("X-Request-ID"), resp)
+		return resp.Body, nil
+	}
+	defer resp.Body.Close()
+
+	var apiResp APIResponse
+	if body, err := io.ReadAll(resp.Body); err == nil {
+		json.Unmarshal(body, &apiResp)
+	}
+	reqID := requestID(resp, &apiResp)
+	recordResponseMeta(ctx, reqID, resp)
+	if apiResp.Error != nil {
+		apiResp.Error.StatusCode = resp.StatusCode
+		apiResp.Error.RequestID = reqID
+		return nil, typedError(apiResp.Error)
+	}
+	return nil, fmt.Errorf("API request failed with status %d", resp.StatusCode)
+}
+
+// audioContentTypes maps common media file extensions to content types
+var audioContentTypes = map[string]string{
+	".mp3":  "audio/mpeg",
+	".wav":  "audio/wav",
+	".flac": "audio/flac",
+	".aac":  "audio/aac",
+	".m4a":  "audio/mp4",
+	".ogg":  "audio/ogg",
+	".opus": "audio/opus",
+	".aif":  "audio/aiff",
+	".aiff": "audio/aiff",
+	".jpg":  "image/jpeg",
+	".jpeg": "image/jpeg",
+	".png":  "image/png",
+	".webp": "image/webp",
+}
 
+// ContentTypeForFilename returns the content type for a file based on its
+// extension, falling back to application/octet-stream
+func ContentTypeForFilename(filename string) string {
+	ext := strings.ToLower(filepath.Ext(filename))
+	if contentType, ok := audioContentTypes[ext]; ok {
+		return contentType
+	}
+	if contentType := mime.TypeByExtension(ext); contentType != "" {
+		return contentType
+	}
+	return "application/octet-stream"
+}
+
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+// createFilePart creates a multipart file part with an explicit content type
+func createFilePart(writer *multipart.Writer, fieldName, filename, contentType string) (io.Writer, error) {
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"; filename="%s"`,
+		quoteEscaper.Replace(fieldName), quoteEscaper.Replace(filename)))
+	header.Set("Content-Type", contentType)
+	return writer.CreatePart(header)
+}
+
+// multipartBody returns the multipart form for an upload and its content
+// type. The form is streamed through a pipe so that large files are not held
+// in memory; streamed uploads are not retried. When requests are signed the
+// signature covers the body, so the form is buffered instead.
+func (c *Client) multipartBody(file io.Reader, filename, contentType string, metadata map[string]string) (io.Reader, string, error) {
+	if c.signingSecret != "" {
+		var buf bytes.Buffer
+		writer := multipart.NewWriter(&buf)
+		if err := writeMultipart(writer, file, filename, contentType, metadata); err != nil {
+			return nil, "", err
+		}
+		return &buf, writer.FormDataContentType(), nil
+	}
+
+	pr, pw := io.Pipe()
+	writer := multipart.NewWriter(pw)
+	go func() {
+		// CloseWithError(nil) closes the pipe normally; any other error is
+		// returned to the reader so the request fails instead of hanging
+		pw.CloseWithError(writeMultipart(writer, file, filename, contentType, metadata))
+	}()
+	return pr, writer.FormDataContentType(), nil
+}
+
+// writeMultipart writes the metadata fields and the file part to writer and
+// closes it
+func writeMultipart(writer *multipart.Writer, file io.Reader, filename, contentType string, metadata map[string]string) error {
 	// Add metadata fields
 	for key, value := range metadata {
 		if err := writer.WriteField(key, value); err != nil {
-			return nil, fmt.Errorf("failed to write field %s: %w", key, err)
+			return fmt.Errorf("failed to write field %s: %w", key, err)
 		}
 	}
 
 	// Add file
-	part, err := writer.CreateFormFile("file", filename)
+	part, err := createFilePart(writer, "file", filename, contentType)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create form file: %w", err)
+		return fmt.Errorf("failed to create form file: %w", err)
 	}
 
 	if _, err := io.Copy(part, file); err != nil {
-		return nil, fmt.Errorf("failed to copy file: %w", err)
+		return fmt.Errorf("failed to copy file: %w", err)
 	}
 
 	if err := writer.Close(); err != nil {
-		return nil, fmt.Errorf("failed to close writer: %w", err)
+		return fmt.Errorf("failed to close writer: %w", err)
+	}
+	return nil
+}
+
+// uploadFromPath opens the file at path, passes it to upload along with its
+// base name, and closes it afterwards
+func uploadFromPath[T any](path string, upload func(file io.Reader, filename string) (T, error)) (T, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		var zero T
+		return zero, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer file.Close()
+
+	return upload(file, filepath.Base(path))
+}
+
+// UploadFile uploads a file with metadata. The file part's content type is
+// derived from the filename extension.
+func (c *Client) UploadFile(ctx context.Context, path string, file io.Reader, filename string, metadata map[string]string) (*APIResponse, error) {
+	return c.UploadFileWithContentType(ctx, path, file, filename, "", metadata)
+}
+
+// UploadFileWithContentType uploads a file with metadata using an explicit
+// content type for the file part. An empty contentType is derived from the
+// filename extension. The file is streamed rather than read into memory.
+func (c *Client) UploadFileWithContentType(ctx context.Context, path string, file io.Reader, filename, contentType string, metadata map[string]string) (_ *APIResponse, err error) {
+	var audited auditRecord
+	if c.auditing("POST") {
+		defer func() { c.audit("POST", path, audited, err) }()
+	}
+
+	if contentType == "" {
+		contentType = ContentTypeForFilename(filename)
+	}
+
+	body, formContentType, err := c.multipartBody(file, filename, contentType, metadata)
+	if err != nil {
+		return nil, err
+	}
+	if pr, ok := body.(*io.PipeReader); ok {
+		// Unblock the writer goroutine if the request ends before the body
+		// has been fully read
+		defer pr.Close()
 	}
 
 	// Create request
-	url := c.baseURL + "/v1" + path
-	req, err := http.NewRequestWithContext(ctx, "POST", url, &buf)
+	url := c.buildURL(path)
+	req, err := http.NewRequestWithContext(ctx, "POST", url, body)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	// Set headers
-	req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	c.setAuthHeader(req)
 	req.Header.Set("User-Agent", c.httpClient.(*HTTPClient).userAgent)
-	req.Header.Set("Content-Type", writer.FormDataContentType())
+	req.Header.Set("Content-Type", formContentType)
 
 	// Perform request
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.doRequest(req, path)
 	if err != nil {
 		return nil, fmt.Errorf("upload failed: %w", err)
 	}
 	defer resp.Body.Close()
+	audited.statusCode = resp.StatusCode
+	audited.requestID = resp.Header.Get("X-Request-ID")
 
 	// Parse response
 	var apiResp APIResponse
@@ -232,12 +610,19 @@ func (c *Client) UploadFile(ctx context.Context, path string, file io.Reader, fi
 		return nil, fmt.Errorf("failed to parse response: %w", err)
 	}
 
+	reqID := requestID(resp, &apiResp)
+	recordResponseMeta(ctx, reqID, resp)
+	audited.requestID = reqID
+	audited.data = apiResp.Data
+
 	if resp.StatusCode >= 400 {
 		if apiResp.Error != nil {
-			return nil, apiResp.Error
+			apiResp.Error.StatusCode = resp.StatusCode
+			apiResp.Error.RequestID = reqID
+			return nil, typedError(apiResp.Error)
 		}
 		return nil, fmt.Errorf("upload failed with status %d", resp.StatusCode)
 	}
 
 	return &apiResp, nil
-}
\ No newline at end of file
+}
@@ -0,0 +1,312 @@
+package jewelmusic
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+// failingReader returns some data and then err
+type failingReader struct {
+	data io.Reader
+	err  error
+}
+
+func (r *failingReader) Read(p []byte) (int, error) {
+	n, err := r.data.Read(p)
+	if err == io.EOF {
+		return n, r.err
+	}
+	return n, err
+}
+
+func TestUploadFailingReaderClosesPipe(t *testing.T) {
+	errRead := errors.New("disk read failed")
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		writeData(t, w, map[string]interface{}{"id": "trk_1"})
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	before := runtime.NumGoroutine()
+	for i := 0; i < 10; i++ {
+		file := &failingReader{data: strings.NewReader(strings.Repeat("a", 64*1024)), err: errRead}
+		_, err := client.UploadFile(ctx, "/tracks/upload", file, "track.wav", nil)
+		if !errors.Is(err, errRead) {
+			t.Fatalf("UploadFile error = %v, want %v", err, errRead)
+		}
+	}
+
+	// Give finished goroutines, such as the transport's, time to exit
+	client.httpClient.CloseIdleConnections()
+	deadline := time.Now().Add(2 * time.Second)
+	for runtime.NumGoroutine() > before && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if after := runtime.NumGoroutine(); after > before {
+		t.Errorf("goroutines grew from %d to %d after failed uploads", before, after)
+	}
+}
+
+func TestHTTPTraceSummarizesStreamedUpload(t *testing.T) {
+	var trace bytes.Buffer
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		writeData(t, w, map[string]interface{}{"id": "trk_1"})
+	}, WithHTTPTrace(&trace))
+
+	_, err := client.UploadFile(context.Background(), "/tracks/upload", strings.NewReader("audio data"), "track.wav", nil)
+	if err != nil {
+		t.Fatalf("UploadFile: %v", err)
+	}
+	dump := trace.String()
+	if !strings.Contains(dump, "[multipart body omitted: streamed]") {
+		t.Errorf("streamed upload not summarized:\n%s", dump)
+	}
+	if strings.Contains(dump, "audio data") {
+		t.Errorf("trace contains the upload body:\n%s", dump)
+	}
+}
+
+func TestListParamStyles(t *testing.T) {
+	query := AnalyticsQuery{
+		StartDate: "2024-01-01",
+		EndDate:   "2024-01-31",
+		Platforms: []string{"spotify", "apple"},
+	}
+
+	tests := []struct {
+		name          string
+		opts          []ClientOption
+		wantPlatforms []string
+	}{
+		{"csv by default", nil, []string{"spotify,apple"}},
+		{"repeated keys", []ClientOption{WithListParamStyle(ListParamRepeat)}, []string{"spotify", "apple"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got url.Values
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				got = r.URL.Query()
+				writeData(t, w, map[string]interface{}{})
+			}, tt.opts...)
+
+			if _, err := client.Analytics.GetStreams(context.Background(), query); err != nil {
+				t.Fatalf("GetStreams: %v", err)
+			}
+			if strings.Join(got["platforms"], "|") != strings.Join(tt.wantPlatforms, "|") {
+				t.Errorf("platforms = %q, want %q", got["platforms"], tt.wantPlatforms)
+			}
+			if got.Get("startDate") != "2024-01-01" {
+				t.Errorf("startDate = %q", got.Get("startDate"))
+			}
+			if _, ok := got["territories"]; ok {
+				t.Error("empty list parameter was sent")
+			}
+		})
+	}
+}
+
+func TestUploadFilePartContentType(t *testing.T) {
+	tests := []struct {
+		name        string
+		filename    string
+		contentType string
+		want        string
+	}{
+		{"mp3", "song.mp3", "", "audio/mpeg"},
+		{"wav upper case", "SONG.WAV", "", "audio/wav"},
+		{"flac", "master.flac", "", "audio/flac"},
+		{"cover art", "cover.png", "", "image/png"},
+		{"unknown extension", "song.xyz123", "", "application/octet-stream"},
+		{"override", "song.mp3", "audio/x-custom", "audio/x-custom"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var partType, partName string
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				reader, err := r.MultipartReader()
+				if err != nil {
+					t.Errorf("reading multipart body: %v", err)
+					return
+				}
+				for {
+					part, err := reader.NextPart()
+					if err != nil {
+						break
+					}
+					if part.FormName() == "file" {
+						partType = part.Header.Get("Content-Type")
+						partName = part.FileName()
+					}
+				}
+				writeData(t, w, map[string]interface{}{})
+			})
+
+			_, err := client.UploadFileWithContentType(context.Background(), "/tracks/upload", strings.NewReader("audio"), tt.filename, tt.contentType, nil)
+			if err != nil {
+				t.Fatalf("upload: %v", err)
+			}
+			if partType != tt.want {
+				t.Errorf("part Content-Type = %q, want %q", partType, tt.want)
+			}
+			if partName != tt.filename {
+				t.Errorf("part filename = %q, want %q", partName, tt.filename)
+			}
+		})
+	}
+}
+
+func TestUploadFromPath(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "demo.flac")
+	if err := os.WriteFile(path, []byte("flac audio"), 0o600); err != nil {
+		t.Fatalf("writing temp file: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		wantPath string
+		upload   func(ctx context.Context, client *Client, path string) error
+	}{
+		{
+			name:     "tracks",
+			wantPath: "/v1/tracks/upload",
+			upload: func(ctx context.Context, client *Client, path string) error {
+				_, err := client.Tracks.UploadPath(ctx, path, TrackMetadata{Title: "Demo", Artist: "Artist"}, nil)
+				return err
+			},
+		},
+		{
+			name:     "analysis",
+			wantPath: "/v1/analysis/upload",
+			upload: func(ctx context.Context, client *Client, path string) error {
+				_, err := client.Analysis.UploadTrackPath(ctx, path, nil)
+				return err
+			},
+		},
+		{
+			name:     "transcription",
+			wantPath: "/v1/transcription/create",
+			upload: func(ctx context.Context, client *Client, path string) error {
+				_, err := client.Transcription.CreateFromPath(ctx, path, nil)
+				return err
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requestPath, filename, content string
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				requestPath = r.URL.Path
+				file, header, err := r.FormFile("file")
+				if err != nil {
+					t.Errorf("reading file part: %v", err)
+					return
+				}
+				defer file.Close()
+				data, _ := io.ReadAll(file)
+				filename, content = header.Filename, string(data)
+				writeData(t, w, map[string]interface{}{"id": "id_1"})
+			})
+
+			if err := tt.upload(context.Background(), client, path); err != nil {
+				t.Fatalf("upload: %v", err)
+			}
+			if requestPath != tt.wantPath {
+				t.Errorf("path = %s, want %s", requestPath, tt.wantPath)
+			}
+			if filename != "demo.flac" || content != "flac audio" {
+				t.Errorf("file part = %q with %q, want demo.flac with the file contents", filename, content)
+			}
+
+			err := tt.upload(context.Background(), client, filepath.Join(dir, "missing.wav"))
+			if !errors.Is(err, fs.ErrNotExist) {
+				t.Errorf("missing file error = %v, want fs.ErrNotExist", err)
+			}
+		})
+	}
+}
+
+func TestDeleteNoContent(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		call    func(ctx context.Context, client *Client) (map[string]interface{}, error)
+		wantErr bool
+	}{
+		{
+			name:   "track 204",
+			status: http.StatusNoContent,
+			call: func(ctx context.Context, client *Client) (map[string]interface{}, error) {
+				return client.Tracks.Delete(ctx, "trk_1")
+			},
+		},
+		{
+			name:   "webhook 204",
+			status: http.StatusNoContent,
+			call: func(ctx context.Context, client *Client) (map[string]interface{}, error) {
+				return client.Webhooks.Delete(ctx, "wh_1")
+			},
+		},
+		{
+			name:   "api key 200 with empty body",
+			status: http.StatusOK,
+			call: func(ctx context.Context, client *Client) (map[string]interface{}, error) {
+				return client.User.RevokeAPIKey(ctx, "key_1")
+			},
+		},
+		{
+			name:   "404 with empty body",
+			status: http.StatusNotFound,
+			call: func(ctx context.Context, client *Client) (map[string]interface{}, error) {
+				return client.Tracks.Delete(ctx, "trk_404")
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodDelete {
+					t.Errorf("method = %s, want DELETE", r.Method)
+				}
+				w.Header().Set("X-Request-ID", "req_1")
+				w.WriteHeader(tt.status)
+			}, WithMaxRetries(0))
+
+			var meta ResponseMeta
+			result, err := tt.call(WithResponseMeta(context.Background(), &meta), client)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result != nil {
+				t.Errorf("result = %v, want nil", result)
+			}
+			if meta.RequestID != "req_1" || meta.StatusCode != tt.status {
+				t.Errorf("meta = %+v, want request req_1 with status %d", meta, tt.status)
+			}
+		})
+	}
+}
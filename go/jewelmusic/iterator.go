@@ -0,0 +1,80 @@
+package jewelmusic
+
+import "context"
+
+// iteratorPageSize is the page size requested by iterators
+const iteratorPageSize = 100
+
+// Iterator walks every item of a paginated list, fetching pages on demand.
+//
+//	it := client.Analysis.Iterator("completed")
+//	for it.Next(ctx) {
+//		analysis := it.Value()
+//		...
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type Iterator[T any] struct {
+	fetch   func(ctx context.Context, page, perPage int) (*ListResponse, error)
+	page    int
+	seen    int
+	items   []T
+	index   int
+	current T
+	done    bool
+	err     error
+}
+
+// newIterator creates an iterator over the pages returned by fetch
+func newIterator[T any](fetch func(ctx context.Context, page, perPage int) (*ListResponse, error)) *Iterator[T] {
+	return &Iterator[T]{fetch: fetch}
+}
+
+// Next advances to the next item, fetching the next page when needed. It
+// returns false when all items have been read or an error occurred.
+func (it *Iterator[T]) Next(ctx context.Context) bool {
+	for it.index >= len(it.items) {
+		if it.done || it.err != nil {
+			return false
+		}
+
+		it.page++
+		resp, err := it.fetch(detachCallOptions(ctx), it.page, iteratorPageSize)
+		if err != nil {
+			it.err = err
+			return false
+		}
+
+		var items []T
+		if err := resp.DecodeItems(&items); err != nil {
+			it.err = err
+			return false
+		}
+		it.items = items
+		it.index = 0
+		it.seen += len(items)
+
+		// Stop on the last page, and defensively on an empty page or once
+		// the reported total is reached, so that inconsistent pagination
+		// metadata cannot cause an endless loop
+		pagination := resp.Pagination
+		it.done = len(items) == 0 ||
+			it.page >= pagination.TotalPages ||
+			(pagination.Total > 0 && it.seen >= pagination.Total)
+	}
+
+	it.current = it.items[it.index]
+	it.index++
+	return true
+}
+
+// Value returns the current item
+func (it *Iterator[T]) Value() T {
+	return it.current
+}
+
+// Err returns the error that stopped iteration, if any
+func (it *Iterator[T]) Err() error {
+	return it.err
+}
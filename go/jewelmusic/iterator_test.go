@@ -0,0 +1,282 @@
+package jewelmusic
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// pagedHandler serves ids at path two per page, ignoring the requested page
+// size, and records the filter query parameter named filterKey
+func pagedHandler(t *testing.T, path string, ids []string, filterKey string, filters *[]string) http.HandlerFunc {
+	const pageSize = 2
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != path {
+			t.Errorf("path = %s, want %s", r.URL.Path, path)
+		}
+		*filters = append(*filters, r.URL.Query().Get(filterKey))
+
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		start := min((page-1)*pageSize, len(ids))
+		end := min(start+pageSize, len(ids))
+		items := make([]map[string]interface{}, 0, end-start)
+		for _, id := range ids[start:end] {
+			items = append(items, map[string]interface{}{"id": id})
+		}
+		totalPages := (len(ids) + pageSize - 1) / pageSize
+		writePage(t, w, items, page, totalPages, len(ids))
+	}
+}
+
+func TestResourceIterators(t *testing.T) {
+	tests := []struct {
+		name       string
+		path       string
+		filterKey  string
+		wantFilter string
+		collect    func(ctx context.Context, client *Client) ([]string, error)
+	}{
+		{
+			name:       "webhook deliveries",
+			path:       "/v1/webhooks/wh_1/deliveries",
+			filterKey:  "status",
+			wantFilter: "failed",
+			collect: func(ctx context.Context, client *Client) ([]string, error) {
+				var ids []string
+				it := client.Webhooks.DeliveriesIterator("wh_1", &DeliveryFilter{Status: "failed"})
+				for it.Next(ctx) {
+					ids = append(ids, it.Value()["id"].(string))
+				}
+				return ids, it.Err()
+			},
+		},
+		{
+			name:       "analyses",
+			path:       "/v1/analysis",
+			filterKey:  "status",
+			wantFilter: "completed",
+			collect: func(ctx context.Context, client *Client) ([]string, error) {
+				var ids []string
+				it := client.Analysis.Iterator("completed")
+				for it.Next(ctx) {
+					ids = append(ids, it.Value().ID)
+				}
+				return ids, it.Err()
+			},
+		},
+		{
+			name:       "generations",
+			path:       "/v1/copilot/generations",
+			filterKey:  "type",
+			wantFilter: "melody",
+			collect: func(ctx context.Context, client *Client) ([]string, error) {
+				var ids []string
+				it := client.Copilot.GenerationsIterator("melody")
+				for it.Next(ctx) {
+					ids = append(ids, it.Value().ID)
+				}
+				return ids, it.Err()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		for _, n := range []int{0, 1, 4, 5} {
+			t.Run(tt.name+"/"+strconv.Itoa(n)+" items", func(t *testing.T) {
+				ids := make([]string, n)
+				for i := range ids {
+					ids[i] = "id_" + strconv.Itoa(i)
+				}
+				var filters []string
+				client := newTestClient(t, pagedHandler(t, tt.path, ids, tt.filterKey, &filters))
+
+				got, err := tt.collect(context.Background(), client)
+				if err != nil {
+					t.Fatalf("iteration: %v", err)
+				}
+				if strings.Join(got, ",") != strings.Join(ids, ",") {
+					t.Errorf("items = %v, want %v", got, ids)
+				}
+				if wantPages := max((n+1)/2, 1); len(filters) != wantPages {
+					t.Errorf("pages fetched = %d, want %d", len(filters), wantPages)
+				}
+				for _, filter := range filters {
+					if filter != tt.wantFilter {
+						t.Errorf("%s = %q, want %q", tt.filterKey, filter, tt.wantFilter)
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestIteratorStopsOnError(t *testing.T) {
+	calls := 0
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 2 {
+			writeError(t, w, http.StatusBadRequest, "INVALID_REQUEST", "Bad page")
+			return
+		}
+		writePage(t, w, []map[string]interface{}{{"id": "ana_1"}}, 1, 3, 3)
+	})
+
+	it := client.Analysis.Iterator("")
+	var n int
+	for it.Next(context.Background()) {
+		n++
+	}
+	if n != 1 || it.Err() == nil {
+		t.Errorf("items = %d, err = %v; want 1 item and an error", n, it.Err())
+	}
+	if it.Next(context.Background()) || calls != 2 {
+		t.Errorf("iterator continued after an error (%d requests)", calls)
+	}
+}
+
+func TestIteratorInconsistentPagination(t *testing.T) {
+	const maxRequests = 10
+	// pageSizes is the number of items on each page; later pages are empty
+	tests := []struct {
+		name      string
+		pageSizes []int
+		total     int
+		wantItems int
+		wantPages int
+	}{
+		{
+			name:      "empty page before total pages",
+			pageSizes: []int{2},
+			total:     100,
+			wantItems: 2,
+			wantPages: 2,
+		},
+		{
+			name:      "total reached before total pages",
+			pageSizes: []int{2, 2, 2, 2},
+			total:     3,
+			wantItems: 4,
+			wantPages: 2,
+		},
+		{
+			name:      "unknown total with empty page",
+			pageSizes: []int{1, 1},
+			wantItems: 2,
+			wantPages: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests := 0
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+				n := 0
+				if page <= len(tt.pageSizes) {
+					n = tt.pageSizes[page-1]
+				}
+				if requests > maxRequests {
+					t.Errorf("iterator is still paging after %d requests", maxRequests)
+					n = 0
+				}
+				items := make([]map[string]interface{}, n)
+				for i := range items {
+					items[i] = map[string]interface{}{"id": "ana_" + strconv.Itoa(page) + "_" + strconv.Itoa(i)}
+				}
+				// Always claim more pages remain
+				writePage(t, w, items, page, 1000, tt.total)
+			})
+
+			it := client.Analysis.Iterator("")
+			n := 0
+			for it.Next(context.Background()) {
+				n++
+			}
+			if err := it.Err(); err != nil {
+				t.Fatalf("iteration: %v", err)
+			}
+			if n != tt.wantItems {
+				t.Errorf("items = %d, want %d", n, tt.wantItems)
+			}
+			if requests != tt.wantPages {
+				t.Errorf("pages fetched = %d, want %d", requests, tt.wantPages)
+			}
+		})
+	}
+}
+
+func TestListPageDefaults(t *testing.T) {
+	lists := map[string]func(ctx context.Context, client *Client, page, perPage int) error{
+		"tracks": func(ctx context.Context, client *Client, page, perPage int) error {
+			_, err := client.Tracks.List(ctx, page, perPage, nil)
+			return err
+		},
+		"trashed tracks": func(ctx context.Context, client *Client, page, perPage int) error {
+			_, err := client.Tracks.ListTrashed(ctx, page, perPage)
+			return err
+		},
+		"track analyses": func(ctx context.Context, client *Client, page, perPage int) error {
+			_, err := client.Tracks.ListAnalyses(ctx, "trk_1", page, perPage)
+			return err
+		},
+		"collections": func(ctx context.Context, client *Client, page, perPage int) error {
+			_, err := client.Tracks.ListCollections(ctx, page, perPage)
+			return err
+		},
+		"analyses": func(ctx context.Context, client *Client, page, perPage int) error {
+			_, err := client.Analysis.ListAnalyses(ctx, page, perPage, "")
+			return err
+		},
+		"generations": func(ctx context.Context, client *Client, page, perPage int) error {
+			_, err := client.Copilot.ListGenerations(ctx, page, perPage, "")
+			return err
+		},
+		"releases": func(ctx context.Context, client *Client, page, perPage int) error {
+			_, err := client.Distribution.GetReleases(ctx, page, perPage, nil)
+			return err
+		},
+		"transcriptions": func(ctx context.Context, client *Client, page, perPage int) error {
+			_, err := client.Transcription.List(ctx, page, perPage, "", "")
+			return err
+		},
+		"webhooks": func(ctx context.Context, client *Client, page, perPage int) error {
+			_, err := client.Webhooks.List(ctx, page, perPage, nil)
+			return err
+		},
+		"webhook deliveries": func(ctx context.Context, client *Client, page, perPage int) error {
+			_, err := client.Webhooks.GetDeliveries(ctx, "wh_1", page, perPage, nil)
+			return err
+		},
+	}
+	tests := []struct {
+		name          string
+		page, perPage int
+		wantPage      string
+		wantPerPage   string
+	}{
+		{"zero values", 0, 0, "1", strconv.Itoa(DefaultPerPage)},
+		{"negative values", -2, -10, "1", strconv.Itoa(DefaultPerPage)},
+		{"explicit values", 3, 50, "3", "50"},
+		{"per page capped", 2, MaxPerPage + 1, "2", strconv.Itoa(MaxPerPage)},
+	}
+
+	for name, list := range lists {
+		for _, tt := range tests {
+			t.Run(name+"/"+tt.name, func(t *testing.T) {
+				client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+					query := r.URL.Query()
+					if query.Get("page") != tt.wantPage || query.Get("perPage") != tt.wantPerPage {
+						t.Errorf("page = %q, perPage = %q; want %q, %q", query.Get("page"), query.Get("perPage"), tt.wantPage, tt.wantPerPage)
+					}
+					writePage(t, w, []map[string]interface{}{}, 1, 1, 0)
+				})
+				if err := list(context.Background(), client, tt.page, tt.perPage); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package jewelmusic
+
+import (
+	"context"
+	"sync"
+)
+
+// lazy holds a value loaded on first use, such as a resource's cache of
+// server-side reference data. Concurrent first callers share a single load
+// through sync.Once. A failed load is not cached; the next caller retries it.
+// The zero value is ready to use.
+type lazy[T any] struct {
+	mu      sync.Mutex
+	current *lazyLoad[T]
+}
+
+// lazyLoad is one attempt at loading a lazy value
+type lazyLoad[T any] struct {
+	once  sync.Once
+	value T
+	err   error
+}
+
+// get returns the loaded value, calling load if no load has succeeded yet
+func (l *lazy[T]) get(ctx context.Context, load func(ctx context.Context) (T, error)) (T, error) {
+	l.mu.Lock()
+	if l.current == nil {
+		l.current = new(lazyLoad[T])
+	}
+	attempt := l.current
+	l.mu.Unlock()
+
+	attempt.once.Do(func() {
+		attempt.value, attempt.err = load(detachCallOptions(ctx))
+		if attempt.err != nil {
+			l.mu.Lock()
+			if l.current == attempt {
+				l.current = nil
+			}
+			l.mu.Unlock()
+		}
+	})
+	return attempt.value, attempt.err
+}
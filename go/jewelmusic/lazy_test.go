@@ -0,0 +1,103 @@
+package jewelmusic
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestLazyConcurrentFirstUse(t *testing.T) {
+	const goroutines = 50
+	var l lazy[[]string]
+	var loads int32
+	load := func(ctx context.Context) ([]string, error) {
+		atomic.AddInt32(&loads, 1)
+		time.Sleep(10 * time.Millisecond)
+		return []string{"streaming", "club"}, nil
+	}
+
+	start := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			value, err := l.get(context.Background(), load)
+			if err != nil || len(value) != 2 {
+				t.Errorf("get = %v, %v", value, err)
+			}
+		}()
+	}
+	close(start)
+	wg.Wait()
+
+	if loads != 1 {
+		t.Errorf("loads = %d, want 1", loads)
+	}
+}
+
+func TestLazyRetriesFailedLoad(t *testing.T) {
+	errLoad := errors.New("load failed")
+	var l lazy[int]
+	loads := 0
+	load := func(ctx context.Context) (int, error) {
+		loads++
+		if loads == 1 {
+			return 0, errLoad
+		}
+		return 42, nil
+	}
+
+	if _, err := l.get(context.Background(), load); !errors.Is(err, errLoad) {
+		t.Fatalf("first get error = %v, want %v", err, errLoad)
+	}
+	for i := 0; i < 2; i++ {
+		if value, err := l.get(context.Background(), load); err != nil || value != 42 {
+			t.Errorf("get = %d, %v; want 42", value, err)
+		}
+	}
+	if loads != 2 {
+		t.Errorf("loads = %d, want 2", loads)
+	}
+}
+
+func TestMasteringPresetsLoadedOnce(t *testing.T) {
+	const goroutines = 20
+	var presetCalls int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/analysis/mastering-presets":
+			atomic.AddInt32(&presetCalls, 1)
+			time.Sleep(10 * time.Millisecond)
+			writeData(t, w, []map[string]interface{}{{"name": "streaming"}, {"name": "club"}})
+		case "/v1/copilot/complete-song":
+			writeData(t, w, map[string]interface{}{"id": "gen_1", "status": "pending"})
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	})
+
+	start := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			if _, err := client.Copilot.CompleteSong(context.Background(), SongOptions{Prompt: "anthem", MasteringPreset: "club"}); err != nil {
+				t.Errorf("CompleteSong: %v", err)
+			}
+		}()
+	}
+	close(start)
+	wg.Wait()
+
+	if presetCalls != 1 {
+		t.Errorf("preset requests = %d, want 1", presetCalls)
+	}
+}
@@ -0,0 +1,71 @@
+package jewelmusic
+
+import (
+	"strings"
+	"time"
+	"unicode"
+)
+
+// RequestInfo describes a completed API request
+type RequestInfo struct {
+	Method     string
+	Path       string
+	Resource   string
+	Operation  string
+	StatusCode int
+	Duration   time.Duration
+	Err        error
+}
+
+// RequestObserver is notified after every API request, for example to
+// record metrics. Implementations must be safe for concurrent use.
+type RequestObserver interface {
+	ObserveRequest(info RequestInfo)
+}
+
+// WithRequestObserver registers an observer notified after every request
+func WithRequestObserver(observer RequestObserver) ClientOption {
+	return func(c *Client) {
+		c.observers = append(c.observers, observer)
+	}
+}
+
+// observe notifies all registered observers about a completed request
+func (c *Client) observe(method, path string, statusCode int, duration time.Duration, err error) {
+	if len(c.observers) == 0 {
+		return
+	}
+
+	resource, operation := describePath(method, path)
+	info := RequestInfo{
+		Method:     method,
+		Path:       path,
+		Resource:   resource,
+		Operation:  operation,
+		StatusCode: statusCode,
+		Duration:   duration,
+		Err:        err,
+	}
+	for _, observer := range c.observers {
+		observer.ObserveRequest(info)
+	}
+}
+
+// describePath derives low-cardinality resource and operation labels from a
+// request path, e.g. GET /tracks/trk_123/waveform -> "tracks", "GET /tracks/{id}/waveform"
+func describePath(method, path string) (resource, operation string) {
+	if i := strings.IndexByte(path, '?'); i >= 0 {
+		path = path[:i]
+	}
+
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for i, segment := range segments {
+		if i > 0 && strings.IndexFunc(segment, unicode.IsDigit) >= 0 {
+			segments[i] = "{id}"
+		}
+	}
+
+	resource = segments[0]
+	operation = method + " /" + strings.Join(segments, "/")
+	return resource, operation
+}
@@ -0,0 +1,18 @@
+package jewelmusic
+
+// Bool returns a pointer to b, for optional fields such as
+// WebhookUpdate.Active where an explicit false must be distinguished from
+// leaving the field unchanged
+func Bool(b bool) *bool {
+	return &b
+}
+
+// String returns a pointer to s, for optional string fields
+func String(s string) *string {
+	return &s
+}
+
+// Int returns a pointer to i, for optional integer fields
+func Int(i int) *int {
+	return &i
+}
@@ -0,0 +1,77 @@
+package jewelmusic
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestActiveSerialization(t *testing.T) {
+	tests := []struct {
+		name       string
+		active     *bool
+		wantActive string
+		wantSent   bool
+	}{
+		{"omitted", nil, "", false},
+		{"explicit false", Bool(false), "false", true},
+		{"explicit true", Bool(true), "true", true},
+	}
+
+	updates := []struct {
+		name string
+		call func(ctx context.Context, client *Client, active *bool) error
+	}{
+		{
+			name: "webhook",
+			call: func(ctx context.Context, client *Client, active *bool) error {
+				_, err := client.Webhooks.Update(ctx, "wh_1", WebhookUpdate{Description: "Paused", Active: active})
+				return err
+			},
+		},
+		{
+			name: "api key",
+			call: func(ctx context.Context, client *Client, active *bool) error {
+				_, err := client.User.UpdateAPIKey(ctx, "key_1", APIKeyUpdate{Description: "Paused", Active: active})
+				return err
+			},
+		},
+	}
+
+	for _, update := range updates {
+		for _, tt := range tests {
+			t.Run(update.name+"/"+tt.name, func(t *testing.T) {
+				client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+					fields := requestFields(t, r)
+					active, sent := fields["active"]
+					if sent != tt.wantSent || active != tt.wantActive {
+						t.Errorf("active = %q (sent %t), want %q (sent %t)", active, sent, tt.wantActive, tt.wantSent)
+					}
+					if fields["description"] != "Paused" {
+						t.Errorf("description = %q, want Paused", fields["description"])
+					}
+					writeData(t, w, map[string]interface{}{"id": "res_1"})
+				})
+
+				if err := update.call(context.Background(), client, tt.active); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			})
+		}
+	}
+}
+
+func TestPointerHelpers(t *testing.T) {
+	if b := Bool(false); b == nil || *b {
+		t.Errorf("Bool(false) = %v", b)
+	}
+	if s := String(""); s == nil || *s != "" {
+		t.Errorf("String(\"\") = %v", s)
+	}
+	if i := Int(0); i == nil || *i != 0 {
+		t.Errorf("Int(0) = %v", i)
+	}
+	if Bool(true) == Bool(true) {
+		t.Error("Bool returned a shared pointer")
+	}
+}
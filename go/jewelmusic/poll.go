@@ -0,0 +1,144 @@
+package jewelmusic
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+)
+
+// ErrAlreadyCompleted is returned when cancelling a job that has already finished
+var ErrAlreadyCompleted = errors.New("jewelmusic: job has already completed")
+
+// PollOptions configures how asynchronous jobs are polled until they finish.
+// Clock is used for the delays between polls; nil means SystemClock.
+type PollOptions struct {
+	Interval    time.Duration
+	MaxInterval time.Duration
+	Multiplier  float64
+	Timeout     time.Duration
+	Jitter      Jitter
+	Clock       Clock
+}
+
+const (
+	defaultPollInterval    = 2 * time.Second
+	defaultPollMaxInterval = 30 * time.Second
+	defaultPollMultiplier  = 1.5
+)
+
+// withDefaults returns a copy of the options with zero values filled in
+func (o *PollOptions) withDefaults() PollOptions {
+	var opts PollOptions
+	if o != nil {
+		opts = *o
+	}
+	if opts.Interval <= 0 {
+		opts.Interval = defaultPollInterval
+	}
+	if opts.MaxInterval <= 0 {
+		opts.MaxInterval = defaultPollMaxInterval
+	}
+	if opts.MaxInterval < opts.Interval {
+		opts.MaxInterval = opts.Interval
+	}
+	if opts.Multiplier < 1 {
+		opts.Multiplier = defaultPollMultiplier
+	}
+	return opts
+}
+
+// Poll calls fetch until isTerminal reports true for the returned value.
+// The delay between calls grows by Multiplier up to MaxInterval, randomized
+// according to Jitter; a poll interval suggested by the server in the fetched
+// value's pollAfter field takes precedence. Polling stops
+// early when fetch returns an error, the context is cancelled, or the optional
+// Timeout elapses; in the latter two cases the last fetched value is returned
+// together with the context error.
+func Poll[T any](ctx context.Context, fetch func() (T, error), isTerminal func(T) bool, options *PollOptions) (T, error) {
+	opts := options.withDefaults()
+
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+
+	backoff := BackoffStrategy{
+		Base:       opts.Interval,
+		Max:        opts.MaxInterval,
+		Multiplier: opts.Multiplier,
+		Jitter:     opts.Jitter,
+	}
+	for attempt := 0; ; attempt++ {
+		value, err := fetch()
+		if err != nil {
+			return value, err
+		}
+		if isTerminal(value) {
+			return value, nil
+		}
+
+		delay := backoff.Delay(attempt)
+		if hinter, ok := any(value).(pollHinter); ok {
+			if suggested := hinter.suggestedPollInterval(); suggested > 0 {
+				delay = suggested
+			}
+		}
+		if err := sleepContext(ctx, clockOrSystem(opts.Clock), delay); err != nil {
+			return value, err
+		}
+	}
+}
+
+// pollHinter is implemented by job types whose status responses may carry a
+// server-suggested poll interval
+type pollHinter interface {
+	suggestedPollInterval() time.Duration
+}
+
+// secondsToDuration converts a number of seconds to a time.Duration
+func secondsToDuration(seconds float64) time.Duration {
+	return time.Duration(seconds * float64(time.Second))
+}
+
+func (a *Analysis) suggestedPollInterval() time.Duration { return secondsToDuration(a.PollAfter) }
+
+func (g *Generation) suggestedPollInterval() time.Duration { return secondsToDuration(g.PollAfter) }
+
+func (s *TranscriptionStatus) suggestedPollInterval() time.Duration {
+	return secondsToDuration(s.PollAfter)
+}
+
+func (j *StemJob) suggestedPollInterval() time.Duration { return secondsToDuration(j.PollAfter) }
+
+func (j *ExportJob) suggestedPollInterval() time.Duration { return secondsToDuration(j.PollAfter) }
+
+// IsTerminalStatus reports whether a job status is final
+func IsTerminalStatus(status string) bool {
+	switch status {
+	case "completed", "failed", "cancelled", "error":
+		return true
+	}
+	return false
+}
+
+// cancelJob posts a cancel request for an asynchronous job and decodes the
+// updated job into result. status extracts the job status from result.
+// ErrAlreadyCompleted is returned if the job finished before it could be
+// cancelled.
+func (c *Client) cancelJob(ctx context.Context, path string, result interface{}, status func() string) error {
+	err := c.Post(ctx, path, nil, result)
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusConflict {
+		return ErrAlreadyCompleted
+	}
+	if err != nil {
+		return err
+	}
+
+	if s := status(); IsTerminalStatus(s) && s != "cancelled" {
+		return ErrAlreadyCompleted
+	}
+	return nil
+}
@@ -0,0 +1,217 @@
+package jewelmusic
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestPoll(t *testing.T) {
+	errFetch := errors.New("fetch failed")
+
+	tests := []struct {
+		name      string
+		statuses  []string
+		fetchErr  error
+		timeout   time.Duration
+		wantCalls int
+		wantErr   error
+	}{
+		{"terminal on first fetch", []string{"completed"}, nil, 0, 1, nil},
+		{"terminal after polling", []string{"pending", "processing", "failed"}, nil, 0, 3, nil},
+		{"fetch error stops polling", []string{"pending"}, errFetch, 0, 1, errFetch},
+		{"timeout", []string{"pending"}, nil, 20 * time.Millisecond, 0, context.DeadlineExceeded},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			fetch := func() (string, error) {
+				status := tt.statuses[min(calls, len(tt.statuses)-1)]
+				calls++
+				return status, tt.fetchErr
+			}
+			options := &PollOptions{Interval: time.Millisecond, Timeout: tt.timeout}
+
+			status, err := Poll(context.Background(), fetch, IsTerminalStatus, options)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Poll error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantCalls > 0 && calls != tt.wantCalls {
+				t.Errorf("fetch calls = %d, want %d", calls, tt.wantCalls)
+			}
+			if tt.wantErr == nil && status != tt.statuses[len(tt.statuses)-1] {
+				t.Errorf("status = %q, want %q", status, tt.statuses[len(tt.statuses)-1])
+			}
+		})
+	}
+}
+
+func TestPollCancellation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	fetch := func() (string, error) {
+		calls++
+		if calls == 2 {
+			cancel()
+		}
+		return "processing", nil
+	}
+
+	status, err := Poll(ctx, fetch, IsTerminalStatus, &PollOptions{Interval: time.Millisecond})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Poll error = %v, want %v", err, context.Canceled)
+	}
+	if status != "processing" {
+		t.Errorf("status = %q, want the last fetched value", status)
+	}
+	if calls != 2 {
+		t.Errorf("fetch calls = %d, want 2", calls)
+	}
+}
+
+func TestCancel(t *testing.T) {
+	cancels := []struct {
+		name   string
+		path   string
+		cancel func(ctx context.Context, client *Client) (string, error)
+	}{
+		{
+			name: "analysis",
+			path: "/v1/analysis/ana_1/cancel",
+			cancel: func(ctx context.Context, client *Client) (string, error) {
+				analysis, err := client.Analysis.Cancel(ctx, "ana_1")
+				return analysis.Status, err
+			},
+		},
+		{
+			name: "generation",
+			path: "/v1/copilot/generations/gen_1/cancel",
+			cancel: func(ctx context.Context, client *Client) (string, error) {
+				generation, err := client.Copilot.CancelGeneration(ctx, "gen_1")
+				return generation.Status, err
+			},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		httpStatus int
+		jobStatus  string
+		wantErr    error
+	}{
+		{"cancelled", http.StatusOK, "cancelled", nil},
+		{"already completed", http.StatusOK, "completed", ErrAlreadyCompleted},
+		{"conflict", http.StatusConflict, "", ErrAlreadyCompleted},
+	}
+
+	for _, c := range cancels {
+		for _, tt := range tests {
+			t.Run(c.name+"/"+tt.name, func(t *testing.T) {
+				client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+					if r.Method != http.MethodPost || r.URL.Path != c.path {
+						t.Errorf("request = %s %s, want POST %s", r.Method, r.URL.Path, c.path)
+					}
+					if tt.httpStatus != http.StatusOK {
+						writeError(t, w, tt.httpStatus, "CONFLICT", "Job has already finished")
+						return
+					}
+					writeData(t, w, map[string]interface{}{"id": "job_1", "status": tt.jobStatus})
+				})
+
+				status, err := c.cancel(context.Background(), client)
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("error = %v, want %v", err, tt.wantErr)
+				}
+				if tt.wantErr == nil && status != "cancelled" {
+					t.Errorf("status = %q, want cancelled", status)
+				}
+			})
+		}
+	}
+}
+
+func TestWaitForHonorsPollAfter(t *testing.T) {
+	tests := []struct {
+		name      string
+		path      string
+		pollAfter float64
+		wantDelay time.Duration
+		wait      func(ctx context.Context, client *Client, options *PollOptions) (string, error)
+	}{
+		{
+			name:      "analysis with suggested interval",
+			path:      "/v1/analysis/ana_1",
+			pollAfter: 45,
+			wantDelay: 45 * time.Second,
+			wait: func(ctx context.Context, client *Client, options *PollOptions) (string, error) {
+				analysis, err := client.Analysis.WaitForAnalysis(ctx, "ana_1", options)
+				return analysis.Status, err
+			},
+		},
+		{
+			name:      "generation with fractional interval",
+			path:      "/v1/copilot/generations/gen_1",
+			pollAfter: 2.5,
+			wantDelay: 2500 * time.Millisecond,
+			wait: func(ctx context.Context, client *Client, options *PollOptions) (string, error) {
+				generation, err := client.Copilot.WaitForGeneration(ctx, "gen_1", options)
+				return generation.Status, err
+			},
+		},
+		{
+			name:      "analysis without suggestion",
+			path:      "/v1/analysis/ana_1",
+			wantDelay: time.Second,
+			wait: func(ctx context.Context, client *Client, options *PollOptions) (string, error) {
+				analysis, err := client.Analysis.WaitForAnalysis(ctx, "ana_1", options)
+				return analysis.Status, err
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			polls := 0
+			clock := newFakeClock(time.Unix(1700000000, 0))
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != tt.path {
+					t.Errorf("path = %s, want %s", r.URL.Path, tt.path)
+				}
+				polls++
+				status := map[string]interface{}{"status": "processing"}
+				if polls > 1 {
+					status["status"] = "completed"
+				} else if tt.pollAfter > 0 {
+					status["pollAfter"] = tt.pollAfter
+				}
+				writeData(t, w, status)
+			}, WithClock(clock))
+
+			type result struct {
+				status string
+				err    error
+			}
+			done := make(chan result, 1)
+			go func() {
+				status, err := tt.wait(context.Background(), client, &PollOptions{Interval: time.Second})
+				done <- result{status, err}
+			}()
+
+			if delay := <-clock.waiting; delay != tt.wantDelay {
+				t.Errorf("poll delay = %v, want %v", delay, tt.wantDelay)
+			}
+			clock.Advance(time.Minute)
+
+			got := <-done
+			if got.err != nil {
+				t.Fatalf("unexpected error: %v", got.err)
+			}
+			if got.status != "completed" || polls != 2 {
+				t.Errorf("status = %q after %d polls, want completed after 2", got.status, polls)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+// Package prommetrics records JewelMusic API client metrics with Prometheus.
+//
+// It lives in its own package so that applications which don't use
+// Prometheus don't pull in the dependency.
+package prommetrics
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/jewelmusic/sdk/go/jewelmusic"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Collector records request count, errors, and latency for API requests.
+// It implements both prometheus.Collector and jewelmusic.RequestObserver.
+type Collector struct {
+	requests *prometheus.CounterVec
+	errors   *prometheus.CounterVec
+	latency  *prometheus.HistogramVec
+}
+
+// NewCollector creates a new metrics collector
+func NewCollector() *Collector {
+	labels := []string{"resource", "operation", "status"}
+	return &Collector{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "jewelmusic",
+			Subsystem: "client",
+			Name:      "requests_total",
+			Help:      "Total number of JewelMusic API requests.",
+		}, labels),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "jewelmusic",
+			Subsystem: "client",
+			Name:      "request_errors_total",
+			Help:      "Total number of failed JewelMusic API requests.",
+		}, labels),
+		latency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "jewelmusic",
+			Subsystem: "client",
+			Name:      "request_duration_seconds",
+			Help:      "Latency of JewelMusic API requests.",
+			Buckets:   prometheus.DefBuckets,
+		}, labels),
+	}
+}
+
+// Describe implements prometheus.Collector
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	c.requests.Describe(ch)
+	c.errors.Describe(ch)
+	c.latency.Describe(ch)
+}
+
+// Collect implements prometheus.Collector
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	c.requests.Collect(ch)
+	c.errors.Collect(ch)
+	c.latency.Collect(ch)
+}
+
+// ObserveRequest implements jewelmusic.RequestObserver
+func (c *Collector) ObserveRequest(info jewelmusic.RequestInfo) {
+	status := "error"
+	if info.StatusCode > 0 {
+		status = strconv.Itoa(info.StatusCode)
+	}
+
+	c.requests.WithLabelValues(info.Resource, info.Operation, status).Inc()
+	if info.Err != nil || info.StatusCode >= 400 {
+		c.errors.WithLabelValues(info.Resource, info.Operation, status).Inc()
+	}
+	c.latency.WithLabelValues(info.Resource, info.Operation, status).Observe(info.Duration.Seconds())
+}
+
+// WithMetrics registers a Collector with registerer and returns a client
+// option that records every request. If an equivalent collector is already
+// registered it is reused, so multiple clients can share one registry.
+// It panics if registration fails for any other reason.
+func WithMetrics(registerer prometheus.Registerer) jewelmusic.ClientOption {
+	collector := NewCollector()
+	if err := registerer.Register(collector); err != nil {
+		var already prometheus.AlreadyRegisteredError
+		if !errors.As(err, &already) {
+			panic(err)
+		}
+		existing, ok := already.ExistingCollector.(*Collector)
+		if !ok {
+			panic(err)
+		}
+		collector = existing
+	}
+	return jewelmusic.WithRequestObserver(collector)
+}
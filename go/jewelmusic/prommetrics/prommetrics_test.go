@@ -0,0 +1,85 @@
+package prommetrics
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jewelmusic/sdk/go/jewelmusic"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestWithMetrics(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/v1/tracks/trk_404" {
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(map[string]interface{}{"success": false, "error": map[string]interface{}{"code": "NOT_FOUND", "message": "Track not found"}})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"success": true, "data": map[string]interface{}{"id": "trk_1"}})
+	}))
+	defer server.Close()
+
+	registry := prometheus.NewRegistry()
+	client := jewelmusic.NewClient("jml_live_test", jewelmusic.WithBaseURL(server.URL), jewelmusic.WithMaxRetries(0), WithMetrics(registry))
+	// A second client registering with the same registry shares the collector
+	other := jewelmusic.NewClient("jml_live_test", jewelmusic.WithBaseURL(server.URL), WithMetrics(registry))
+
+	ctx := context.Background()
+	for _, id := range []string{"trk_1", "trk_2"} {
+		if _, err := client.Tracks.Get(ctx, id); err != nil {
+			t.Fatalf("Get(%s): %v", id, err)
+		}
+	}
+	if _, err := other.Tracks.Get(ctx, "trk_3"); err != nil {
+		t.Fatalf("Get(trk_3): %v", err)
+	}
+	if _, err := client.Tracks.Get(ctx, "trk_404"); err == nil {
+		t.Fatal("expected an error for a missing track")
+	}
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("gathering metrics: %v", err)
+	}
+
+	// counts maps metric name and status label to the recorded count
+	counts := make(map[string]uint64)
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			labels := make(map[string]string)
+			for _, label := range metric.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+			if labels["resource"] != "tracks" || labels["operation"] != "GET /tracks/{id}" {
+				t.Errorf("%s labels = %v", family.GetName(), labels)
+			}
+			key := family.GetName() + "/" + labels["status"]
+			switch {
+			case metric.GetCounter() != nil:
+				counts[key] = uint64(metric.GetCounter().GetValue())
+			case metric.GetHistogram() != nil:
+				counts[key] = metric.GetHistogram().GetSampleCount()
+			}
+		}
+	}
+
+	want := map[string]uint64{
+		"jewelmusic_client_requests_total/200":           3,
+		"jewelmusic_client_requests_total/404":           1,
+		"jewelmusic_client_request_errors_total/404":     1,
+		"jewelmusic_client_request_duration_seconds/200": 3,
+		"jewelmusic_client_request_duration_seconds/404": 1,
+	}
+	for key, n := range want {
+		if counts[key] != n {
+			t.Errorf("%s = %d, want %d", key, counts[key], n)
+		}
+	}
+	if len(counts) != len(want) {
+		t.Errorf("metrics = %v, want %v", counts, want)
+	}
+}
@@ -0,0 +1,109 @@
+package jewelmusic
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// rateLimitSpacingFraction is the fraction of the limit below which the
+// limiter starts spacing requests evenly over the rest of the window
+const rateLimitSpacingFraction = 0.1
+
+// WithRateLimiter enables client-side throttling based on the rate limit
+// reported by the API. Once the remaining quota is low, requests are spaced
+// out over the rest of the window, and when it is exhausted they block until
+// the window resets instead of failing with 429. The limiter is shared by all
+// goroutines using the client.
+func WithRateLimiter() ClientOption {
+	return func(c *Client) {
+		c.limiter = &rateLimiter{}
+	}
+}
+
+// rateLimiter is a token bucket refilled at each rate limit window reset
+type rateLimiter struct {
+	mu        sync.Mutex
+	known     bool
+	limit     int
+	remaining int
+	reset     time.Time
+	next      time.Time
+}
+
+// wait blocks until a request may be sent without exceeding the rate limit,
+// measuring time with clock
+func (l *rateLimiter) wait(ctx context.Context, clock Clock) error {
+	for {
+		l.mu.Lock()
+		now := clock.Now()
+		if !l.known || !now.Before(l.reset) {
+			l.known = false
+			l.mu.Unlock()
+			return nil
+		}
+
+		if l.remaining > 0 {
+			var delay time.Duration
+			if float64(l.remaining) <= float64(l.limit)*rateLimitSpacingFraction {
+				spacing := l.reset.Sub(now) / time.Duration(l.remaining+1)
+				if l.next.After(now) {
+					delay = l.next.Sub(now)
+				}
+				l.next = now.Add(delay + spacing)
+			}
+			l.remaining--
+			l.mu.Unlock()
+			if delay <= 0 {
+				return nil
+			}
+			return sleepContext(ctx, clock, delay)
+		}
+
+		delay := l.reset.Sub(now)
+		l.mu.Unlock()
+		if err := sleepContext(ctx, clock, delay); err != nil {
+			return err
+		}
+	}
+}
+
+// update records the rate limit state reported by the API at now. reset is
+// either a Unix timestamp or a number of seconds until the window resets.
+func (l *rateLimiter) update(now time.Time, limit, remaining, reset int) {
+	if limit <= 0 || reset <= 0 {
+		return
+	}
+
+	resetAt := time.Unix(int64(reset), 0)
+	if reset < 1e9 {
+		resetAt = now.Add(time.Duration(reset) * time.Second)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.known = true
+	l.limit = limit
+	l.remaining = remaining
+	l.reset = resetAt
+}
+
+// updateFromHeaders records the rate limit state from X-RateLimit headers
+// received at now
+func (l *rateLimiter) updateFromHeaders(now time.Time, header http.Header) {
+	limit, err := strconv.Atoi(header.Get("X-RateLimit-Limit"))
+	if err != nil {
+		return
+	}
+	remaining, err := strconv.Atoi(header.Get("X-RateLimit-Remaining"))
+	if err != nil {
+		return
+	}
+	reset, err := strconv.Atoi(header.Get("X-RateLimit-Reset"))
+	if err != nil {
+		return
+	}
+	l.update(now, limit, remaining, reset)
+}
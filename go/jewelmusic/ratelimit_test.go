@@ -0,0 +1,119 @@
+package jewelmusic
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// waitDelay runs limiter.wait and returns how long it slept on clock,
+// advancing the clock past the sleep
+func waitDelay(t *testing.T, limiter *rateLimiter, clock *fakeClock) time.Duration {
+	t.Helper()
+	done := make(chan error, 1)
+	go func() {
+		done <- limiter.wait(context.Background(), clock)
+	}()
+
+	var slept time.Duration
+	for {
+		select {
+		case d := <-clock.waiting:
+			slept += d
+			clock.Advance(d)
+		case err := <-done:
+			if err != nil {
+				t.Fatalf("wait: %v", err)
+			}
+			return slept
+		case <-time.After(5 * time.Second):
+			t.Fatal("wait did not return")
+		}
+	}
+}
+
+func TestRateLimiterSpacing(t *testing.T) {
+	tests := []struct {
+		name       string
+		limit      int
+		remaining  int
+		reset      int
+		wantDelays []time.Duration
+	}{
+		{"plenty remaining", 100, 50, 60, []time.Duration{0, 0, 0, 0}},
+		{"near the limit", 100, 5, 60, []time.Duration{0, 10 * time.Second, 12 * time.Second, 12500 * time.Millisecond}},
+		{"exhausted", 100, 0, 30, []time.Duration{30 * time.Second, 0}},
+		{"unknown limit", 0, 0, 0, []time.Duration{0, 0}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := newFakeClock(time.Unix(1700000000, 0))
+			limiter := &rateLimiter{}
+			limiter.update(clock.Now(), tt.limit, tt.remaining, tt.reset)
+
+			for i, want := range tt.wantDelays {
+				if got := waitDelay(t, limiter, clock); got != want {
+					t.Errorf("call %d waited %v, want %v", i+1, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestRateLimiterSharedAcrossGoroutines(t *testing.T) {
+	clock := newFakeClock(time.Unix(1700000000, 0))
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Limit", "100")
+		w.Header().Set("X-RateLimit-Remaining", "3")
+		w.Header().Set("X-RateLimit-Reset", strconv.Itoa(40))
+		writeData(t, w, map[string]interface{}{})
+	}, WithRateLimiter(), WithClock(clock))
+
+	// The first response seeds the limiter: 3 requests left in 40 seconds
+	if err := client.Get(context.Background(), "/tracks", nil, nil); err != nil {
+		t.Fatalf("seeding request: %v", err)
+	}
+	clone := client.With(WithMaxRetries(0))
+
+	const n = 3
+	done := make(chan error, n)
+	for i := 0; i < n; i++ {
+		c := client
+		if i%2 == 1 {
+			c = clone
+		}
+		go func() {
+			done <- c.Get(context.Background(), "/tracks", nil, nil)
+		}()
+	}
+
+	// One request goes out immediately; the others are spaced on the clock
+	var waits []time.Duration
+	for finished := 0; finished < n; {
+		select {
+		case d := <-clock.waiting:
+			waits = append(waits, d)
+		case err := <-done:
+			if err != nil {
+				t.Fatalf("Get: %v", err)
+			}
+			finished++
+			continue
+		case <-time.After(5 * time.Second):
+			t.Fatalf("requests blocked; waits so far %v", waits)
+		}
+		clock.Advance(waits[len(waits)-1])
+	}
+
+	if len(waits) != n-1 {
+		t.Fatalf("waits = %v, want %d spaced requests", waits, n-1)
+	}
+	for _, d := range waits {
+		if d <= 0 || d > 40*time.Second {
+			t.Errorf("wait %v outside the rate limit window", d)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package jewelmusic
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DefaultParagraphPause is the silence in seconds that starts a new paragraph
+const DefaultParagraphPause = 2.0
+
+// RenderTextOptions controls how a transcription is rendered as plain text
+type RenderTextOptions struct {
+	// ParagraphPause is the gap in seconds between segments that starts a
+	// new paragraph. Defaults to DefaultParagraphPause.
+	ParagraphPause float64
+	// SplitOnSpeaker starts a new paragraph whenever the speaker changes
+	SplitOnSpeaker bool
+	// IncludeTimestamps prefixes each paragraph with its start time
+	IncludeTimestamps bool
+	// IncludeSpeakers prefixes each paragraph with the speaker label
+	IncludeSpeakers bool
+}
+
+// RenderText renders the transcription as plain text, grouping segments into
+// paragraphs separated by blank lines. When the transcription has no
+// segments, Text is returned unchanged.
+func (t *Transcription) RenderText(options *RenderTextOptions) string {
+	if len(t.Segments) == 0 {
+		return t.Text
+	}
+
+	var opts RenderTextOptions
+	if options != nil {
+		opts = *options
+	}
+	if opts.ParagraphPause <= 0 {
+		opts.ParagraphPause = DefaultParagraphPause
+	}
+
+	var paragraphs []string
+	var current []string
+	var start float64
+	var speaker string
+
+	flush := func() {
+		if len(current) == 0 {
+			return
+		}
+		var prefix string
+		if opts.IncludeTimestamps {
+			prefix += "[" + formatTimestamp(start) + "] "
+		}
+		if opts.IncludeSpeakers && speaker != "" {
+			prefix += speaker + ": "
+		}
+		paragraphs = append(paragraphs, prefix+strings.Join(current, " "))
+		current = nil
+	}
+
+	for i, segment := range t.Segments {
+		text := strings.TrimSpace(segment.Text)
+		if text == "" {
+			continue
+		}
+		if i > 0 && len(current) > 0 {
+			prev := t.Segments[i-1]
+			pause := segment.StartTime-prev.EndTime >= opts.ParagraphPause
+			speakerChange := opts.SplitOnSpeaker && segment.Speaker != speaker
+			if pause || speakerChange {
+				flush()
+			}
+		}
+		if len(current) == 0 {
+			start = segment.StartTime
+			speaker = segment.Speaker
+		}
+		current = append(current, text)
+	}
+	flush()
+
+	return strings.Join(paragraphs, "\n\n")
+}
+
+// formatTimestamp formats seconds as mm:ss, or h:mm:ss for an hour or more
+func formatTimestamp(seconds float64) string {
+	total := int(seconds)
+	h, m, s := total/3600, (total%3600)/60, total%60
+	if h > 0 {
+		return fmt.Sprintf("%d:%02d:%02d", h, m, s)
+	}
+	return fmt.Sprintf("%02d:%02d", m, s)
+}
@@ -0,0 +1,60 @@
+package jewelmusic
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite golden files in testdata")
+
+func TestRenderText(t *testing.T) {
+	transcription := &Transcription{
+		Text: "unused when segments are present",
+		Segments: []Segment{
+			{Text: "Welcome back to the show.", StartTime: 0.5, EndTime: 2.1, Speaker: "Host"},
+			{Text: "Today we have a special guest.", StartTime: 2.3, EndTime: 4.8, Speaker: "Host"},
+			{Text: "Thanks for having me.", StartTime: 5.0, EndTime: 6.2, Speaker: "Guest"},
+			{Text: "  ", StartTime: 6.3, EndTime: 6.4, Speaker: "Guest"},
+			{Text: "Let's start with the new single.", StartTime: 9.5, EndTime: 11.9, Speaker: "Host"},
+			{Text: "It was recorded in one take.", StartTime: 3725, EndTime: 3728.4, Speaker: "Guest"},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		options *RenderTextOptions
+	}{
+		{"default", nil},
+		{"speakers", &RenderTextOptions{SplitOnSpeaker: true, IncludeSpeakers: true}},
+		{"timestamps", &RenderTextOptions{ParagraphPause: 1, IncludeTimestamps: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := transcription.RenderText(tt.options)
+
+			golden := filepath.Join("testdata", "render_"+tt.name+".golden")
+			if *update {
+				if err := os.WriteFile(golden, []byte(got), 0o644); err != nil {
+					t.Fatalf("writing golden file: %v", err)
+				}
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatalf("reading golden file: %v", err)
+			}
+			if got != string(want) {
+				t.Errorf("RenderText output differs from %s\ngot:\n%s\nwant:\n%s", golden, got, want)
+			}
+		})
+	}
+
+	t.Run("no segments", func(t *testing.T) {
+		plain := &Transcription{Text: "just the text"}
+		if got := plain.RenderText(nil); got != "just the text" {
+			t.Errorf("RenderText = %q, want Text unchanged", got)
+		}
+	})
+}
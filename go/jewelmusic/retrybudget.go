@@ -0,0 +1,60 @@
+package jewelmusic
+
+import (
+	"sync"
+	"time"
+)
+
+// WithRetryBudget caps the total number of retries across all requests made
+// with the client to maxRetries per window, so that per-request retries in a
+// large concurrent batch cannot multiply into a retry storm. The budget is a
+// token bucket refilled continuously over the window. Once it is exhausted,
+// failed requests return their failure immediately instead of retrying.
+func WithRetryBudget(maxRetries int, window time.Duration) ClientOption {
+	return func(c *Client) {
+		if maxRetries > 0 && window > 0 {
+			c.retryBudget = newRetryBudget(maxRetries, window)
+		} else {
+			c.retryBudget = nil
+		}
+	}
+}
+
+// retryBudget is a token bucket of retries shared by all requests
+type retryBudget struct {
+	mu       sync.Mutex
+	capacity float64
+	tokens   float64
+	rate     float64 // tokens per second
+	last     time.Time
+}
+
+// newRetryBudget creates a full budget of maxRetries per window
+func newRetryBudget(maxRetries int, window time.Duration) *retryBudget {
+	return &retryBudget{
+		capacity: float64(maxRetries),
+		tokens:   float64(maxRetries),
+		rate:     float64(maxRetries) / window.Seconds(),
+	}
+}
+
+// take consumes one retry from the budget at time now, reporting false if
+// none is left
+func (b *retryBudget) take(now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !b.last.IsZero() && now.After(b.last) {
+		b.tokens += now.Sub(b.last).Seconds() * b.rate
+		if b.tokens > b.capacity {
+			b.tokens = b.capacity
+		}
+	}
+	b.last = now
+
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
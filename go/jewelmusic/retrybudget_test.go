@@ -0,0 +1,91 @@
+package jewelmusic
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRetryBudgetCapsAttempts(t *testing.T) {
+	const calls, maxRetries = 20, 3
+
+	tests := []struct {
+		name         string
+		opts         []ClientOption
+		wantAttempts int32
+	}{
+		{
+			name:         "no budget",
+			wantAttempts: calls * (1 + maxRetries),
+		},
+		{
+			name:         "budget of five retries",
+			opts:         []ClientOption{WithRetryBudget(5, time.Minute)},
+			wantAttempts: calls + 5,
+		},
+		{
+			name:         "disabled budget",
+			opts:         []ClientOption{WithRetryBudget(5, time.Minute), WithRetryBudget(0, time.Minute)},
+			wantAttempts: calls * (1 + maxRetries),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts int32
+			opts := append([]ClientOption{
+				WithMaxRetries(maxRetries),
+				WithBackoffStrategy(BackoffStrategy{Base: time.Millisecond, Max: time.Millisecond, Multiplier: 1}),
+			}, tt.opts...)
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(&attempts, 1)
+				writeError(t, w, http.StatusServiceUnavailable, "UNAVAILABLE", "Overloaded")
+			}, opts...)
+
+			var wg sync.WaitGroup
+			for i := 0; i < calls; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					if _, err := client.Tracks.Get(context.Background(), "trk_1"); err == nil {
+						t.Error("expected an error")
+					}
+				}()
+			}
+			wg.Wait()
+
+			if attempts != tt.wantAttempts {
+				t.Errorf("attempts = %d, want %d", attempts, tt.wantAttempts)
+			}
+		})
+	}
+}
+
+func TestRetryBudgetRefills(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	budget := newRetryBudget(2, time.Minute)
+
+	steps := []struct {
+		advance time.Duration
+		want    bool
+	}{
+		{0, true},
+		{0, true},
+		{0, false},
+		{15 * time.Second, false},
+		{15 * time.Second, true},
+		{0, false},
+		{time.Hour, true},
+		{0, true},
+		{0, false},
+	}
+	for i, step := range steps {
+		now = now.Add(step.advance)
+		if got := budget.take(now); got != step.want {
+			t.Errorf("step %d: take = %v, want %v", i, got, step.want)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package jewelmusic
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// MetadataSchema describes the expected shape of TrackMetadata.Custom
+type MetadataSchema struct {
+	// Required lists keys that must be present with a non-empty value
+	Required []string
+	// Patterns maps keys to patterns their values must match when present
+	Patterns map[string]*regexp.Regexp
+}
+
+// WithMetadataSchema validates TrackMetadata.Custom against schema before
+// tracks are uploaded or updated. Violations are returned as a
+// *ValidationError keyed by "custom.<key>".
+func WithMetadataSchema(schema MetadataSchema) ClientOption {
+	return func(c *Client) {
+		c.metadataSchema = &schema
+	}
+}
+
+// Validate checks custom metadata against the schema
+func (s *MetadataSchema) Validate(custom map[string]string) error {
+	fields := make(map[string][]string)
+	for _, key := range s.Required {
+		if custom[key] == "" {
+			fields["custom."+key] = append(fields["custom."+key], "is required")
+		}
+	}
+	for key, pattern := range s.Patterns {
+		value, ok := custom[key]
+		if ok && !pattern.MatchString(value) {
+			fields["custom."+key] = append(fields["custom."+key], fmt.Sprintf("must match %s", pattern))
+		}
+	}
+
+	if len(fields) == 0 {
+		return nil
+	}
+	return &ValidationError{
+		APIError: &APIError{Code: "VALIDATION_ERROR", Message: "Custom metadata does not match schema"},
+		Fields:   fields,
+	}
+}
+
+// validateMetadata checks metadata against the client's schema, if any
+func (c *Client) validateMetadata(metadata TrackMetadata) error {
+	if c.metadataSchema == nil {
+		return nil
+	}
+	return c.metadataSchema.Validate(metadata.Custom)
+}
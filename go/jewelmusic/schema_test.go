@@ -0,0 +1,68 @@
+package jewelmusic
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestMetadataSchema(t *testing.T) {
+	schema := MetadataSchema{
+		Required: []string{"catalogId", "label"},
+		Patterns: map[string]*regexp.Regexp{
+			"catalogId": regexp.MustCompile(`^CAT-\d{4}$`),
+			"isrc":      regexp.MustCompile(`^[A-Z]{2}[A-Z0-9]{3}\d{7}$`),
+		},
+	}
+
+	tests := []struct {
+		name       string
+		custom     map[string]string
+		wantFields []string
+	}{
+		{"valid", map[string]string{"catalogId": "CAT-0042", "label": "Indie", "isrc": "USRC17607839"}, nil},
+		{"optional pattern key absent", map[string]string{"catalogId": "CAT-0042", "label": "Indie"}, nil},
+		{"missing required key", map[string]string{"catalogId": "CAT-0042"}, []string{"custom.label"}},
+		{"empty required value", map[string]string{"catalogId": "CAT-0042", "label": ""}, []string{"custom.label"}},
+		{"pattern mismatch", map[string]string{"catalogId": "CAT-42", "label": "Indie", "isrc": "bad"}, []string{"custom.catalogId", "custom.isrc"}},
+		{"no custom metadata", nil, []string{"custom.catalogId", "custom.label"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uploads := 0
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				uploads++
+				writeData(t, w, map[string]interface{}{"id": "trk_1"})
+			}, WithMetadataSchema(schema))
+
+			metadata := TrackMetadata{Title: "Song", Artist: "Artist", Custom: tt.custom}
+			_, err := client.Tracks.Upload(context.Background(), strings.NewReader("audio"), "song.wav", metadata, nil)
+			if tt.wantFields == nil {
+				if err != nil || uploads != 1 {
+					t.Fatalf("Upload = %v after %d requests, want success", err, uploads)
+				}
+				return
+			}
+
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("error = %v, want *ValidationError", err)
+			}
+			if uploads != 0 {
+				t.Error("invalid metadata was uploaded")
+			}
+			if len(validationErr.Fields) != len(tt.wantFields) {
+				t.Errorf("fields = %v, want keys %v", validationErr.Fields, tt.wantFields)
+			}
+			for _, field := range tt.wantFields {
+				if len(validationErr.Fields[field]) == 0 {
+					t.Errorf("no violation reported for %s in %v", field, validationErr.Fields)
+				}
+			}
+		})
+	}
+}
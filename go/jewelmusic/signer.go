@@ -0,0 +1,48 @@
+package jewelmusic
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// ClientSignatureHeader is the header carrying the client request signature
+const ClientSignatureHeader = "X-JewelMusic-Client-Signature"
+
+// WithRequestSigner signs every request with an HMAC-SHA256 over the method,
+// request URI, and body, in addition to the bearer token. The header value
+// uses the same "t=timestamp,v1=hash" format as webhook signatures.
+func WithRequestSigner(secret string) ClientOption {
+	return func(c *Client) {
+		c.signingSecret = secret
+	}
+}
+
+// signRequest sets the client signature header on req
+func (c *Client) signRequest(req *http.Request) error {
+	var body []byte
+	if req.GetBody != nil {
+		rc, err := req.GetBody()
+		if err != nil {
+			return fmt.Errorf("failed to read request body for signing: %w", err)
+		}
+		body, err = io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			return fmt.Errorf("failed to read request body for signing: %w", err)
+		}
+	}
+
+	req.Header.Set(ClientSignatureHeader, CreateSignatureWithClock(c.clock, requestSigningPayload(req.Method, req.URL.RequestURI(), body), c.signingSecret))
+	return nil
+}
+
+// requestSigningPayload builds the content covered by the client signature
+func requestSigningPayload(method, requestURI string, body []byte) []byte {
+	payload := make([]byte, 0, len(method)+len(requestURI)+len(body)+2)
+	payload = append(payload, method...)
+	payload = append(payload, '\n')
+	payload = append(payload, requestURI...)
+	payload = append(payload, '\n')
+	return append(payload, body...)
+}
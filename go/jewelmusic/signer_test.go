@@ -0,0 +1,99 @@
+package jewelmusic
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestWithRequestSigner(t *testing.T) {
+	const secret = "client_secret"
+
+	tests := []struct {
+		name      string
+		send      func(ctx context.Context, client *Client) error
+		wantURI   string
+		wantBody  string
+		multipart bool
+	}{
+		{
+			name: "json body",
+			send: func(ctx context.Context, client *Client) error {
+				return client.Post(ctx, "/tracks/trk_1/notes", map[string]string{"note": "hello"}, nil)
+			},
+			wantURI:  "/v1/tracks/trk_1/notes",
+			wantBody: `{"note":"hello"}`,
+		},
+		{
+			name: "query without body",
+			send: func(ctx context.Context, client *Client) error {
+				return client.Get(ctx, "/tracks", map[string]string{"page": "2"}, nil)
+			},
+			wantURI: "/v1/tracks?page=2",
+		},
+		{
+			name: "buffered upload",
+			send: func(ctx context.Context, client *Client) error {
+				_, err := client.UploadFile(ctx, "/tracks/upload", strings.NewReader("audio"), "song.wav", nil)
+				return err
+			},
+			wantURI:   "/v1/tracks/upload",
+			multipart: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var header, uri, method string
+			var body []byte
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				header = r.Header.Get(ClientSignatureHeader)
+				uri, method = r.URL.RequestURI(), r.Method
+				body, _ = io.ReadAll(r.Body)
+				writeData(t, w, map[string]interface{}{})
+			}, WithRequestSigner(secret))
+
+			if err := tt.send(context.Background(), client); err != nil {
+				t.Fatalf("request: %v", err)
+			}
+			if uri != tt.wantURI {
+				t.Errorf("request URI = %s, want %s", uri, tt.wantURI)
+			}
+			if !tt.multipart && string(body) != tt.wantBody {
+				t.Errorf("body = %q, want %q", body, tt.wantBody)
+			}
+
+			var timestamp int64
+			if _, err := fmt.Sscanf(header, "t=%d,", &timestamp); err != nil {
+				t.Fatalf("signature header %q: %v", header, err)
+			}
+			mac := hmac.New(sha256.New, []byte(secret))
+			fmt.Fprintf(mac, "%d.%s\n%s\n%s", timestamp, method, uri, body)
+			want := fmt.Sprintf("t=%d,v1=%s", timestamp, hex.EncodeToString(mac.Sum(nil)))
+			if header != want {
+				t.Errorf("signature = %q, want %q", header, want)
+			}
+		})
+	}
+}
+
+func TestRequestSigningPayload(t *testing.T) {
+	var timestamp int64 = 1700000000
+	payload := requestSigningPayload("POST", "/v1/tracks?x=1", []byte(`{"a":1}`))
+	if string(payload) != "POST\n/v1/tracks?x=1\n{\"a\":1}" {
+		t.Errorf("payload = %q", payload)
+	}
+
+	// The expected hash was computed independently with openssl
+	got := CreateSignature(payload, "secret", &timestamp)
+	want := "t=1700000000,v1=4d1ca70bdf269195c39d98f310ac1c97f0057a8e72208f94f18e4674cd369a57"
+	if got != want {
+		t.Errorf("signature = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,93 @@
+package jewelmusic
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httputil"
+	"strings"
+	"sync"
+)
+
+// redactedHeaders lists request headers whose values are never written to traces
+var redactedHeaders = []string{"Authorization"}
+
+// httpTracer writes raw HTTP exchanges to a writer for debugging
+type httpTracer struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// WithHTTPTrace writes a dump of every HTTP request and response to w, in the
+// format of httputil.DumpRequestOut and httputil.DumpResponse. Credentials
+// are redacted, and multipart upload bodies and non-JSON response bodies such
+// as audio downloads are summarized rather than dumped.
+func WithHTTPTrace(w io.Writer) ClientOption {
+	return func(c *Client) {
+		c.tracer = &httpTracer{w: w}
+	}
+}
+
+// traceRequest dumps an outgoing request. Headers named in redact are
+// redacted in addition to redactedHeaders.
+func (t *httpTracer) traceRequest(req *http.Request, redact ...string) {
+	summarize := strings.HasPrefix(req.Header.Get("Content-Type"), "multipart/")
+
+	dump, err := httputil.DumpRequestOut(req, !summarize)
+	if err != nil {
+		t.write(fmt.Sprintf(">>> failed to dump request: %v\n\n", err))
+		return
+	}
+	dump = redactHeaders(dump, append(redact, redactedHeaders...))
+	// Streamed bodies, such as piped uploads, cannot be replayed and report
+	// a zero content length
+	if summarize && req.GetBody == nil {
+		dump = append(dump, "[multipart body omitted: streamed]\n"...)
+	} else if summarize {
+		dump = append(dump, fmt.Sprintf("[multipart body omitted: %d bytes]\n", req.ContentLength)...)
+	}
+	t.write(">>> request\n" + string(dump) + "\n\n")
+}
+
+// traceResponse dumps an incoming response. Only JSON bodies are dumped;
+// others are left unread so that downloads keep streaming.
+func (t *httpTracer) traceResponse(resp *http.Response) {
+	summarize := !strings.Contains(resp.Header.Get("Content-Type"), "json")
+
+	dump, err := httputil.DumpResponse(resp, !summarize)
+	if err != nil {
+		t.write(fmt.Sprintf("<<< failed to dump response: %v\n\n", err))
+		return
+	}
+	if summarize && resp.ContentLength < 0 {
+		dump = append(dump, "[body omitted: streamed]\n"...)
+	} else if summarize {
+		dump = append(dump, fmt.Sprintf("[body omitted: %d bytes]\n", resp.ContentLength)...)
+	}
+	t.write("<<< response\n" + string(dump) + "\n\n")
+}
+
+func (t *httpTracer) write(s string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	io.WriteString(t.w, s)
+}
+
+// redactHeaders replaces the values of the named headers in a raw dump
+func redactHeaders(dump []byte, names []string) []byte {
+	lines := bytes.Split(dump, []byte("\r\n"))
+	for i, line := range lines {
+		if len(line) == 0 {
+			// End of headers
+			break
+		}
+		for _, name := range names {
+			prefix := []byte(name + ":")
+			if len(line) >= len(prefix) && bytes.EqualFold(line[:len(prefix)], prefix) {
+				lines[i] = []byte(name + ": [REDACTED]")
+			}
+		}
+	}
+	return bytes.Join(lines, []byte("\r\n"))
+}
@@ -0,0 +1,74 @@
+package jewelmusic
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestHTTPTrace(t *testing.T) {
+	audio := strings.Repeat("\x00\xff", 512)
+
+	tests := []struct {
+		name        string
+		path        string
+		handler     http.HandlerFunc
+		wantDump    []string
+		notWantDump []string
+	}{
+		{
+			name: "JSON response is dumped",
+			path: "/tracks/trk_1",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				writeData(t, w, map[string]interface{}{"id": "trk_1"})
+			},
+			wantDump:    []string{">>> request", "GET /v1/tracks/trk_1", "Authorization: [REDACTED]", "<<< response", `"id":"trk_1"`},
+			notWantDump: []string{"jml_live_test"},
+		},
+		{
+			name: "binary response is summarized",
+			path: "/tracks/trk_1/stream",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "audio/mpeg")
+				io.WriteString(w, audio)
+			},
+			wantDump:    []string{"Content-Type: audio/mpeg", "[body omitted: 1024 bytes]"},
+			notWantDump: []string{"jml_live_test", audio[:16]},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var trace bytes.Buffer
+			client := newTestClient(t, tt.handler, WithHTTPTrace(&trace))
+
+			body, err := client.openStream(context.Background(), tt.path)
+			if err != nil {
+				t.Fatalf("request failed: %v", err)
+			}
+			data, err := io.ReadAll(body)
+			body.Close()
+			if err != nil {
+				t.Fatalf("reading body: %v", err)
+			}
+			if len(data) == 0 {
+				t.Error("traced response body was consumed")
+			}
+
+			dump := trace.String()
+			for _, want := range tt.wantDump {
+				if !strings.Contains(dump, want) {
+					t.Errorf("trace does not contain %q:\n%s", want, dump)
+				}
+			}
+			for _, notWant := range tt.notWantDump {
+				if strings.Contains(dump, notWant) {
+					t.Errorf("trace contains %q:\n%s", notWant, dump)
+				}
+			}
+		})
+	}
+}
@@ -2,8 +2,12 @@ package jewelmusic
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"io"
+	"net/http"
 	"strconv"
+	"strings"
 )
 
 // TracksResource manages track upload, metadata, and organization
@@ -13,22 +17,43 @@ type TracksResource struct {
 
 // TrackFilter represents filters for listing tracks
 type TrackFilter struct {
-	Status            string `json:"status,omitempty"`
-	Genre             string `json:"genre,omitempty"`
-	Artist            string `json:"artist,omitempty"`
-	Album             string `json:"album,omitempty"`
-	UploadedAfter     string `json:"uploadedAfter,omitempty"`
-	UploadedBefore    string `json:"uploadedBefore,omitempty"`
-	DurationMin       int    `json:"durationMin,omitempty"`
-	DurationMax       int    `json:"durationMax,omitempty"`
-	Search            string `json:"search,omitempty"`
+	Status         string `json:"status,omitempty"`
+	Genre          string `json:"genre,omitempty"`
+	Artist         string `json:"artist,omitempty"`
+	Album          string `json:"album,omitempty"`
+	UploadedAfter  string `json:"uploadedAfter,omitempty"`
+	UploadedBefore string `json:"uploadedBefore,omitempty"`
+	DurationMin    int    `json:"durationMin,omitempty"`
+	DurationMax    int    `json:"durationMax,omitempty"`
+	Search         string `json:"search,omitempty"`
+	// Tags restricts the list to tracks with the given tags, combined
+	// according to TagMatch
+	Tags     []string `json:"tags,omitempty"`
+	TagMatch TagMatch `json:"tagMatch,omitempty"`
 }
 
+// TagMatch selects how multiple tags in a TrackFilter are combined
+type TagMatch string
+
+const (
+	// TagMatchAll lists tracks that have every tag (AND), the default
+	TagMatchAll TagMatch = "all"
+	// TagMatchAny lists tracks that have at least one of the tags (OR)
+	TagMatchAny TagMatch = "any"
+)
+
 // UploadOptions represents options for track upload
 type UploadOptions struct {
 	ChunkSize int `json:"chunkSize,omitempty"`
 }
 
+// WaitForReadyOptions configures Tracks.WaitForReady
+type WaitForReadyOptions struct {
+	// RequireAnalysis also waits for the track's first analysis to complete
+	RequireAnalysis bool
+	Poll            *PollOptions
+}
+
 // BatchUpdateItem represents an item in batch metadata update
 type BatchUpdateItem struct {
 	ID       string        `json:"id"`
@@ -38,7 +63,7 @@ type BatchUpdateItem struct {
 // BatchProcessOptions represents options for batch processing
 type BatchProcessOptions struct {
 	Operations []string `json:"operations,omitempty"`
-	Priority   string   `json:"priority,omitempty"`
+	Priority   Priority `json:"priority,omitempty"`
 	Notify     bool     `json:"notify,omitempty"`
 }
 
@@ -51,14 +76,29 @@ type WaveformOptions struct {
 	Samples int      `json:"samples,omitempty"`
 }
 
+// CollectionCreate represents collection creation data
+type CollectionCreate struct {
+	Name        string   `json:"name"`
+	Type        string   `json:"type,omitempty"`
+	Description string   `json:"description,omitempty"`
+	TrackIDs    []string `json:"trackIds,omitempty"`
+}
+
 // Upload uploads a track with metadata
 func (t *TracksResource) Upload(ctx context.Context, file io.Reader, filename string, metadata TrackMetadata, options *UploadOptions) (*Track, error) {
+	if options == nil {
+		options = t.client.defaultUploadOptions
+	}
+	if err := t.client.validateMetadata(metadata); err != nil {
+		return nil, err
+	}
+
 	// Convert metadata to map[string]string for upload
 	metadataMap := map[string]string{
 		"title":  metadata.Title,
 		"artist": metadata.Artist,
 	}
-	
+
 	if metadata.Album != "" {
 		metadataMap["album"] = metadata.Album
 	}
@@ -68,7 +108,26 @@ func (t *TracksResource) Upload(ctx context.Context, file io.Reader, filename st
 	if metadata.ReleaseDate != "" {
 		metadataMap["releaseDate"] = metadata.ReleaseDate
 	}
-	
+	if metadata.BPM < 0 {
+		return nil, &APIError{Code: "INVALID_REQUEST", Message: "BPM must not be negative"}
+	}
+	if metadata.BPM > 0 {
+		metadataMap["bpm"] = strconv.FormatFloat(metadata.BPM, 'f', -1, 64)
+	}
+	if metadata.Key != "" {
+		metadataMap["key"] = metadata.Key
+	}
+	switch metadata.Mode {
+	case "":
+	case "major", "minor":
+		metadataMap["mode"] = metadata.Mode
+	default:
+		return nil, &APIError{Code: "INVALID_REQUEST", Message: "Mode must be major or minor"}
+	}
+	if metadata.ForceReanalyze {
+		metadataMap["forceReanalyze"] = "true"
+	}
+
 	if options != nil && options.ChunkSize > 0 {
 		metadataMap["chunkSize"] = strconv.Itoa(options.ChunkSize)
 	}
@@ -83,13 +142,18 @@ func (t *TracksResource) Upload(ctx context.Context, file io.Reader, filename st
 	return &result, nil
 }
 
+// UploadPath uploads a track from a local file path
+func (t *TracksResource) UploadPath(ctx context.Context, path string, metadata TrackMetadata, options *UploadOptions) (*Track, error) {
+	return uploadFromPath(path, func(file io.Reader, filename string) (*Track, error) {
+		return t.Upload(ctx, file, filename, metadata, options)
+	})
+}
+
 // List gets list of tracks with filtering and pagination
 func (t *TracksResource) List(ctx context.Context, page, perPage int, filter *TrackFilter) (*ListResponse, error) {
-	params := map[string]string{
-		"page":    strconv.Itoa(page),
-		"perPage": strconv.Itoa(perPage),
-	}
-	
+	params := pageParams(page, perPage)
+	var lists map[string][]string
+
 	if filter != nil {
 		if filter.Status != "" {
 			params["status"] = filter.Status
@@ -118,10 +182,27 @@ func (t *TracksResource) List(ctx context.Context, page, perPage int, filter *Tr
 		if filter.Search != "" {
 			params["search"] = filter.Search
 		}
+		if len(filter.Tags) > 0 {
+			lists = map[string][]string{"tags": filter.Tags}
+		}
+		switch filter.TagMatch {
+		case "":
+		case TagMatchAll, TagMatchAny:
+			params["tagMatch"] = string(filter.TagMatch)
+		default:
+			return nil, &APIError{Code: "INVALID_REQUEST", Message: "TagMatch must be all or any"}
+		}
 	}
 
 	var result ListResponse
-	err := t.client.Get(ctx, "/tracks", params, &result)
+	err := t.client.getWithLists(ctx, "/tracks", params, lists, &result)
+	return &result, err
+}
+
+// ListWithOptions gets a page of tracks using common list options
+func (t *TracksResource) ListWithOptions(ctx context.Context, options ListOptions) (*TrackList, error) {
+	var result TrackList
+	err := t.client.Get(ctx, "/tracks", options.params(), &result)
 	return &result, err
 }
 
@@ -132,34 +213,214 @@ func (t *TracksResource) Get(ctx context.Context, trackID string) (*Track, error
 	return &result, err
 }
 
+// GetMany retrieves several tracks by ID. It uses the bulk tracks endpoint
+// and falls back to fetching tracks individually with bounded concurrency if
+// the server does not support it. Tracks that could not be fetched are
+// reported in the returned error map, keyed by ID.
+func (t *TracksResource) GetMany(ctx context.Context, trackIDs []string) (map[string]*Track, map[string]error) {
+	tracks := make(map[string]*Track, len(trackIDs))
+	errs := make(map[string]error)
+	if len(trackIDs) == 0 {
+		return tracks, errs
+	}
+
+	var bulk []Track
+	err := t.client.getWithLists(ctx, "/tracks/batch", nil, map[string][]string{"ids": trackIDs}, &bulk)
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && (apiErr.StatusCode == http.StatusNotFound || apiErr.StatusCode == http.StatusMethodNotAllowed) {
+		for _, result := range runBatch(ctx, trackIDs, DefaultBatchConcurrency, t.Get) {
+			if result.Err != nil {
+				errs[result.ID] = result.Err
+			} else {
+				tracks[result.ID] = result.Value
+			}
+		}
+		return tracks, errs
+	}
+	if err != nil {
+		for _, id := range trackIDs {
+			errs[id] = err
+		}
+		return tracks, errs
+	}
+
+	for i := range bulk {
+		tracks[bulk[i].ID] = &bulk[i]
+	}
+	for _, id := range trackIDs {
+		if _, ok := tracks[id]; !ok {
+			errs[id] = &APIError{Code: "NOT_FOUND", Message: "Track not found: " + id, StatusCode: http.StatusNotFound}
+		}
+	}
+	return tracks, errs
+}
+
 // Update updates track metadata
 func (t *TracksResource) Update(ctx context.Context, trackID string, metadata TrackMetadata) (*Track, error) {
+	if err := t.client.validateMetadata(metadata); err != nil {
+		return nil, err
+	}
+
 	var result Track
 	err := t.client.Put(ctx, "/tracks/"+trackID, metadata, &result)
 	return &result, err
 }
 
-// Delete deletes a track
-func (t *TracksResource) Delete(ctx context.Context, trackID string) (map[string]interface{}, error) {
+// Patch applies a JSON merge patch to a track. Keys set to nil are removed
+// from the track.
+func (t *TracksResource) Patch(ctx context.Context, trackID string, patch map[string]interface{}) (*Track, error) {
+	var result Track
+	err := t.client.Patch(ctx, "/tracks/"+trackID, patch, &result)
+	return &result, err
+}
+
+// AddTags adds tags to a track, keeping its existing tags, and returns the
+// updated track. Tags are trimmed and duplicates are ignored.
+func (t *TracksResource) AddTags(ctx context.Context, trackID string, tags []string) (*Track, error) {
+	return t.changeTags(ctx, trackID, "/tags", tags)
+}
+
+// RemoveTags removes tags from a track and returns the updated track. Tags
+// the track does not have are ignored.
+func (t *TracksResource) RemoveTags(ctx context.Context, trackID string, tags []string) (*Track, error) {
+	return t.changeTags(ctx, trackID, "/tags/remove", tags)
+}
+
+// changeTags sends a normalized tag list to a track's tag endpoint
+func (t *TracksResource) changeTags(ctx context.Context, trackID, endpoint string, tags []string) (*Track, error) {
+	tags = normalizeTags(tags)
+	if len(tags) == 0 {
+		return nil, &APIError{Code: "INVALID_REQUEST", Message: "At least one tag is required"}
+	}
+
+	requestData := map[string]interface{}{
+		"tags": tags,
+	}
+
+	var result Track
+	if err := t.client.Post(ctx, "/tracks/"+trackID+endpoint, requestData, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// normalizeTags trims tags and drops empty and duplicate ones, keeping the
+// order of first occurrence
+func normalizeTags(tags []string) []string {
+	seen := make(map[string]bool, len(tags))
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	return normalized
+}
+
+// DeleteOption configures track deletion
+type DeleteOption func(*deleteOptions)
+
+type deleteOptions struct {
+	soft bool
+}
+
+// WithSoftDelete moves the track to the trash instead of purging it, so that
+// it can later be recovered with Restore
+func WithSoftDelete() DeleteOption {
+	return func(o *deleteOptions) {
+		o.soft = true
+	}
+}
+
+// Delete deletes a track. By default the track is purged permanently; pass
+// WithSoftDelete to move it to the trash instead.
+func (t *TracksResource) Delete(ctx context.Context, trackID string, opts ...DeleteOption) (map[string]interface{}, error) {
+	var options deleteOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	path := "/tracks/" + trackID
+	if options.soft {
+		path += "?soft=true"
+	}
+
 	var result map[string]interface{}
-	err := t.client.Delete(ctx, "/tracks/"+trackID, &result)
+	err := t.client.Delete(ctx, path, &result)
 	return result, err
 }
 
-// UploadArtwork uploads artwork for a track
-func (t *TracksResource) UploadArtwork(ctx context.Context, trackID string, artworkFile io.Reader, filename string) (map[string]interface{}, error) {
-	resp, err := t.client.UploadFile(ctx, "/tracks/"+trackID+"/artwork", artworkFile, filename, nil)
+// Restore restores a soft-deleted track from the trash
+func (t *TracksResource) Restore(ctx context.Context, trackID string) (*Track, error) {
+	var result Track
+	err := t.client.Post(ctx, "/tracks/"+trackID+"/restore", nil, &result)
+	return &result, err
+}
+
+// ListTrashed lists soft-deleted tracks with pagination
+func (t *TracksResource) ListTrashed(ctx context.Context, page, perPage int) (*ListResponse, error) {
+	params := pageParams(page, perPage)
+
+	var result ListResponse
+	err := t.client.Get(ctx, "/tracks/trash", params, &result)
+	return &result, err
+}
+
+// ReplaceAudio uploads new audio for an existing track, keeping its ID and
+// metadata so that analytics continuity is preserved. The track is
+// reprocessed, so the returned track's status is reset to processing.
+func (t *TracksResource) ReplaceAudio(ctx context.Context, trackID string, file io.Reader, filename string) (*Track, error) {
+	resp, err := t.client.UploadFile(ctx, "/tracks/"+trackID+"/audio", file, filename, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var result Track
+	if err := resp.DecodeData(&result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// UploadArtwork uploads artwork for a track. The artwork must be a square
+// JPEG or PNG of at least MinArtworkSize pixels, which is checked before
+// uploading. With options, the server also converts the artwork to the
+// requested platform specifications, sizes, and formats and returns the
+// variants.
+func (t *TracksResource) UploadArtwork(ctx context.Context, trackID string, artworkFile io.Reader, filename string, options *ArtworkOptions) (*ArtworkResult, error) {
+	artwork, err := validateArtwork(artworkFile)
+	if err != nil {
+		return nil, err
+	}
+
+	var metadata map[string]string
+	if options != nil {
+		metadata = options.metadata()
+	}
+
+	resp, err := t.client.UploadFile(ctx, "/tracks/"+trackID+"/artwork", artwork, filename, metadata)
 	if err != nil {
 		return nil, err
 	}
 
-	result := make(map[string]interface{})
-	// Convert response data to map
-	return result, nil
+	var result ArtworkResult
+	if err := resp.DecodeData(&result); err != nil {
+		return nil, err
+	}
+	return &result, nil
 }
 
 // BatchUpdateMetadata updates metadata for multiple tracks
 func (t *TracksResource) BatchUpdateMetadata(ctx context.Context, updates []BatchUpdateItem) (map[string]interface{}, error) {
+	for _, update := range updates {
+		if err := t.client.validateMetadata(update.Metadata); err != nil {
+			return nil, err
+		}
+	}
+
 	requestData := map[string]interface{}{
 		"updates": updates,
 	}
@@ -171,10 +432,16 @@ func (t *TracksResource) BatchUpdateMetadata(ctx context.Context, updates []Batc
 
 // BatchProcess queues tracks for batch processing
 func (t *TracksResource) BatchProcess(ctx context.Context, trackIDs []string, options *BatchProcessOptions) (map[string]interface{}, error) {
+	if options != nil {
+		if err := options.Priority.validate(); err != nil {
+			return nil, err
+		}
+	}
+
 	requestData := map[string]interface{}{
 		"trackIds": trackIDs,
 	}
-	
+
 	if options != nil {
 		if len(options.Operations) > 0 {
 			requestData["operations"] = options.Operations
@@ -190,6 +457,19 @@ func (t *TracksResource) BatchProcess(ctx context.Context, trackIDs []string, op
 	return result, err
 }
 
+// EstimateBatchCost returns the expected cost of running operations on the
+// given tracks with BatchProcess
+func (t *TracksResource) EstimateBatchCost(ctx context.Context, trackIDs []string, operations []string) (*CostEstimate, error) {
+	requestData := map[string]interface{}{
+		"trackIds":   trackIDs,
+		"operations": operations,
+	}
+
+	var result CostEstimate
+	err := t.client.Post(ctx, "/tracks/batch/estimate", requestData, &result)
+	return &result, err
+}
+
 // GetProcessingStatus gets track processing status
 func (t *TracksResource) GetProcessingStatus(ctx context.Context, trackID string) (map[string]interface{}, error) {
 	var result map[string]interface{}
@@ -200,7 +480,7 @@ func (t *TracksResource) GetProcessingStatus(ctx context.Context, trackID string
 // GenerateWaveform generates waveform visualization for a track
 func (t *TracksResource) GenerateWaveform(ctx context.Context, trackID string, options *WaveformOptions) (map[string]interface{}, error) {
 	requestData := make(map[string]interface{})
-	
+
 	if options != nil {
 		if options.Width > 0 {
 			requestData["width"] = options.Width
@@ -224,15 +504,243 @@ func (t *TracksResource) GenerateWaveform(ctx context.Context, trackID string, o
 	return result, err
 }
 
-// GetDownloadURL gets track download URL
-func (t *TracksResource) GetDownloadURL(ctx context.Context, trackID string, format, quality string) (map[string]interface{}, error) {
-	params := map[string]string{
-		"format":  format,
-		"quality": quality,
+// GenerateWaveforms generates waveforms for multiple tracks with at most
+// concurrency requests in flight. Each track's result or error is reported
+// individually, in the order of trackIDs.
+func (t *TracksResource) GenerateWaveforms(ctx context.Context, trackIDs []string, options *WaveformOptions, concurrency int) []BatchResult[map[string]interface{}] {
+	return runBatch(ctx, trackIDs, concurrency, func(ctx context.Context, trackID string) (map[string]interface{}, error) {
+		return t.GenerateWaveform(ctx, trackID, options)
+	})
+}
+
+// downloadQualities lists the qualities available for each download format
+var downloadQualities = map[string][]string{
+	"mp3":  {"128", "192", "256", "320"},
+	"wav":  {"lossless"},
+	"flac": {"lossless"},
+}
+
+// GetDownloadURL gets track download URL. format is mp3, wav, or flac;
+// quality is a bitrate (128, 192, 256, 320) for mp3 or lossless for wav and
+// flac. Empty values use the server defaults; a quality without a format
+// must be available for at least one format.
+func (t *TracksResource) GetDownloadURL(ctx context.Context, trackID string, format, quality string) (*DownloadURL, error) {
+	if format != "" {
+		qualities, ok := downloadQualities[format]
+		if !ok {
+			return nil, &APIError{Code: "INVALID_REQUEST", Message: "Unsupported download format: " + format}
+		}
+		if quality != "" && !containsString(qualities, quality) {
+			return nil, &APIError{Code: "INVALID_REQUEST", Message: "Quality " + quality + " is not available for " + format}
+		}
+	} else if quality != "" && !anyFormatHasQuality(quality) {
+		return nil, &APIError{Code: "INVALID_REQUEST", Message: "Unsupported download quality: " + quality}
 	}
 
-	var result map[string]interface{}
+	params := make(map[string]string)
+	if format != "" {
+		params["format"] = format
+	}
+	if quality != "" {
+		params["quality"] = quality
+	}
+
+	var result DownloadURL
 	err := t.client.Get(ctx, "/tracks/"+trackID+"/download", params, &result)
+	return &result, err
+}
+
+// anyFormatHasQuality reports whether quality is available for any download
+// format
+func anyFormatHasQuality(quality string) bool {
+	for _, qualities := range downloadQualities {
+		if containsString(qualities, quality) {
+			return true
+		}
+	}
+	return false
+}
+
+// containsString reports whether values contains s
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// DownloadResume downloads a track into dst, resuming from the current end
+// of dst with a Range request. If the server ignores the range, dst is
+// rewritten from the start. It returns the total number of bytes in dst.
+// The file is fetched from its presigned URL without the API's credentials,
+// rate limiting, or retries.
+func (t *TracksResource) DownloadResume(ctx context.Context, trackID string, format, quality string, dst io.WriteSeeker) (int64, error) {
+	info, err := t.GetDownloadURL(detachCallOptions(ctx), trackID, format, quality)
+	if err != nil {
+		return 0, err
+	}
+	if info.URL == "" {
+		return 0, fmt.Errorf("download URL missing from response")
+	}
+
+	offset, err := dst.Seek(0, io.SeekEnd)
+	if err != nil {
+		return 0, fmt.Errorf("failed to seek destination: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", info.URL, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+	if offset > 0 {
+		req.Header.Set("Range", "bytes="+strconv.FormatInt(offset, 10)+"-")
+	}
+
+	resp, err := t.client.httpClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusPartialContent:
+		if start := contentRangeStart(resp.Header.Get("Content-Range")); start != offset {
+			return 0, fmt.Errorf("unexpected content range %q for offset %d", resp.Header.Get("Content-Range"), offset)
+		}
+	case http.StatusOK:
+		// The server sent the whole file, so start over
+		if offset, err = dst.Seek(0, io.SeekStart); err != nil {
+			return 0, fmt.Errorf("failed to seek destination: %w", err)
+		}
+		if truncater, ok := dst.(interface{ Truncate(int64) error }); ok {
+			if err := truncater.Truncate(0); err != nil {
+				return 0, fmt.Errorf("failed to truncate destination: %w", err)
+			}
+		}
+	case http.StatusRequestedRangeNotSatisfiable:
+		// Nothing left to download
+		return offset, nil
+	default:
+		return 0, &APIError{
+			Code:       "DOWNLOAD_FAILED",
+			Message:    fmt.Sprintf("download failed with status %d", resp.StatusCode),
+			StatusCode: resp.StatusCode,
+		}
+	}
+
+	n, err := io.Copy(dst, resp.Body)
+	if err != nil {
+		return offset + n, fmt.Errorf("download interrupted: %w", err)
+	}
+	return offset + n, nil
+}
+
+// contentRangeStart returns the first byte position of a Content-Range
+// header such as "bytes 100-999/1000", or -1 if it cannot be parsed
+func contentRangeStart(header string) int64 {
+	spec := strings.TrimPrefix(header, "bytes ")
+	dash := strings.IndexByte(spec, '-')
+	if dash < 0 {
+		return -1
+	}
+	start, err := strconv.ParseInt(spec[:dash], 10, 64)
+	if err != nil {
+		return -1
+	}
+	return start
+}
+
+// ListAnalyses lists all analyses that have been run on a track
+func (t *TracksResource) ListAnalyses(ctx context.Context, trackID string, page, perPage int) (*ListResponse, error) {
+	params := pageParams(page, perPage)
+
+	var result ListResponse
+	err := t.client.Get(ctx, "/tracks/"+trackID+"/analyses", params, &result)
+	return &result, err
+}
+
+// WaitForReady polls a track until processing has finished and, if
+// RequireAnalysis is set, its first analysis has completed. An error is
+// returned if processing or the analysis fails.
+func (t *TracksResource) WaitForReady(ctx context.Context, trackID string, opts *WaitForReadyOptions) (*Track, error) {
+	var options WaitForReadyOptions
+	if opts != nil {
+		options = *opts
+	}
+	pollOptions := t.client.pollOptions(options.Poll)
+
+	track, err := Poll(ctx, func() (*Track, error) {
+		return t.Get(detachCallOptions(ctx), trackID)
+	}, func(track *Track) bool {
+		return isTrackProcessed(track.Status) || IsTerminalStatus(track.Status)
+	}, pollOptions)
+	if err != nil {
+		return track, err
+	}
+	if !isTrackProcessed(track.Status) {
+		return track, &APIError{Code: "PROCESSING_FAILED", Message: "Track processing ended with status " + track.Status}
+	}
+
+	if options.RequireAnalysis {
+		analysis, err := Poll(ctx, func() (*Analysis, error) {
+			return t.firstAnalysis(detachCallOptions(ctx), trackID)
+		}, func(analysis *Analysis) bool {
+			return IsTerminalStatus(analysis.Status)
+		}, pollOptions)
+		if err != nil {
+			return track, err
+		}
+		if analysis.Status != "completed" {
+			return track, &APIError{Code: "ANALYSIS_FAILED", Message: "Track analysis ended with status " + analysis.Status}
+		}
+	}
+
+	return track, nil
+}
+
+// isTrackProcessed reports whether a track status means processing finished
+func isTrackProcessed(status string) bool {
+	switch status {
+	case "processed", "ready", "completed":
+		return true
+	}
+	return false
+}
+
+// firstAnalysis returns the first listed analysis of a track, or an empty
+// analysis if none has been started yet
+func (t *TracksResource) firstAnalysis(ctx context.Context, trackID string) (*Analysis, error) {
+	resp, err := t.ListAnalyses(ctx, trackID, 1, 1)
+	if err != nil {
+		return nil, err
+	}
+	var analyses []Analysis
+	if err := resp.DecodeItems(&analyses); err != nil {
+		return nil, err
+	}
+	if len(analyses) == 0 {
+		return &Analysis{}, nil
+	}
+	return &analyses[0], nil
+}
+
+// Fingerprint gets the acoustic fingerprint of a track
+func (t *TracksResource) Fingerprint(ctx context.Context, trackID string) (*Fingerprint, error) {
+	var result Fingerprint
+	err := t.client.Get(ctx, "/tracks/"+trackID+"/fingerprint", nil, &result)
+	return &result, err
+}
+
+// MatchFingerprint finds tracks whose audio matches a fingerprint
+func (t *TracksResource) MatchFingerprint(ctx context.Context, fp *Fingerprint) ([]FingerprintMatch, error) {
+	if fp == nil || (fp.Fingerprint == "" && fp.Hash == "") {
+		return nil, &APIError{Code: "INVALID_REQUEST", Message: "Fingerprint or hash is required"}
+	}
+
+	var result []FingerprintMatch
+	err := t.client.Post(ctx, "/tracks/fingerprint/match", fp, &result)
 	return result, err
 }
 
@@ -248,4 +756,38 @@ func (t *TracksResource) FindSimilar(ctx context.Context, referenceTrackID strin
 	var result map[string]interface{}
 	err := t.client.Get(ctx, "/tracks/"+referenceTrackID+"/similar", params, &result)
 	return result, err
-}
\ No newline at end of file
+}
+
+// CreateCollection creates a new track collection (album or playlist)
+func (t *TracksResource) CreateCollection(ctx context.Context, collection CollectionCreate) (*Collection, error) {
+	var result Collection
+	err := t.client.Post(ctx, "/tracks/collections", collection, &result)
+	return &result, err
+}
+
+// ListCollections lists the user's track collections with pagination
+func (t *TracksResource) ListCollections(ctx context.Context, page, perPage int) (*ListResponse, error) {
+	params := pageParams(page, perPage)
+
+	var result ListResponse
+	err := t.client.Get(ctx, "/tracks/collections", params, &result)
+	return &result, err
+}
+
+// AddToCollection adds tracks to a collection
+func (t *TracksResource) AddToCollection(ctx context.Context, collectionID string, trackIDs []string) (*Collection, error) {
+	requestData := map[string]interface{}{
+		"trackIds": trackIDs,
+	}
+
+	var result Collection
+	err := t.client.Post(ctx, "/tracks/collections/"+collectionID+"/tracks", requestData, &result)
+	return &result, err
+}
+
+// RemoveFromCollection removes a track from a collection
+func (t *TracksResource) RemoveFromCollection(ctx context.Context, collectionID, trackID string) (*Collection, error) {
+	var result Collection
+	err := t.client.Delete(ctx, "/tracks/collections/"+collectionID+"/tracks/"+trackID, &result)
+	return &result, err
+}
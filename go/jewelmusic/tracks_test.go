@@ -0,0 +1,1157 @@
+package jewelmusic
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestUploadForwardsKnownMetadata(t *testing.T) {
+	tests := []struct {
+		name     string
+		metadata TrackMetadata
+		want     map[string]string
+		wantErr  bool
+	}{
+		{
+			name:     "tempo and key trusted",
+			metadata: TrackMetadata{Title: "Song", Artist: "Artist", BPM: 128, Key: "A", Mode: "minor"},
+			want:     map[string]string{"bpm": "128", "key": "A", "mode": "minor", "forceReanalyze": ""},
+		},
+		{
+			name:     "forced reanalysis",
+			metadata: TrackMetadata{Title: "Song", Artist: "Artist", BPM: 92.5, Key: "C", ForceReanalyze: true},
+			want:     map[string]string{"bpm": "92.5", "key": "C", "forceReanalyze": "true"},
+		},
+		{
+			name:     "unknown properties",
+			metadata: TrackMetadata{Title: "Song", Artist: "Artist"},
+			want:     map[string]string{"bpm": "", "key": "", "mode": "", "forceReanalyze": ""},
+		},
+		{
+			name:     "invalid mode",
+			metadata: TrackMetadata{Title: "Song", Artist: "Artist", Mode: "dorian"},
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var fields map[string]string
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				if err := r.ParseMultipartForm(1 << 20); err != nil {
+					t.Errorf("parsing upload: %v", err)
+				}
+				fields = make(map[string]string)
+				for k, v := range r.MultipartForm.Value {
+					fields[k] = v[0]
+				}
+				writeData(t, w, map[string]interface{}{"id": "trk_1"})
+			})
+
+			_, err := client.Tracks.Upload(context.Background(), strings.NewReader("audio"), "song.wav", tt.metadata, nil)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Upload: %v", err)
+			}
+			for k, want := range tt.want {
+				if got := fields[k]; got != want {
+					t.Errorf("field %s = %q, want %q", k, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestDownloadResume(t *testing.T) {
+	const content = "0123456789abcdefghij"
+
+	tests := []struct {
+		name         string
+		partial      string
+		honorRange   bool
+		wantRange    string
+		wantReturned int64
+	}{
+		{"resume partial file", content[:8], true, "bytes=8-", int64(len(content))},
+		{"range ignored", content[:8], false, "bytes=8-", int64(len(content))},
+		{"empty file", "", true, "", int64(len(content))},
+		{"already complete", content, true, "bytes=20-", int64(len(content))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotRange, cdnAuth string
+			var cdnURL string
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/cdn/track.mp3" {
+					writeData(t, w, map[string]interface{}{"url": cdnURL, "format": "mp3"})
+					return
+				}
+				gotRange = r.Header.Get("Range")
+				cdnAuth = r.Header.Get("Authorization")
+				offset := 0
+				if gotRange != "" && tt.honorRange {
+					fmt.Sscanf(gotRange, "bytes=%d-", &offset)
+					if offset >= len(content) {
+						w.WriteHeader(http.StatusRequestedRangeNotSatisfiable)
+						return
+					}
+					w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", offset, len(content)-1, len(content)))
+					w.WriteHeader(http.StatusPartialContent)
+				}
+				io.WriteString(w, content[offset:])
+			})
+			cdnURL = client.baseURL + "/cdn/track.mp3"
+
+			file, err := os.CreateTemp(t.TempDir(), "track-*.mp3")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer file.Close()
+			io.WriteString(file, tt.partial)
+
+			n, err := client.Tracks.DownloadResume(context.Background(), "trk_1", "mp3", "320", file)
+			if err != nil {
+				t.Fatalf("DownloadResume: %v", err)
+			}
+			if n != tt.wantReturned {
+				t.Errorf("returned %d bytes, want %d", n, tt.wantReturned)
+			}
+			if gotRange != tt.wantRange {
+				t.Errorf("Range = %q, want %q", gotRange, tt.wantRange)
+			}
+			if cdnAuth != "" {
+				t.Errorf("download URL request carries Authorization %q", cdnAuth)
+			}
+			data, err := os.ReadFile(file.Name())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(data) != content {
+				t.Errorf("file = %q, want %q", data, content)
+			}
+		})
+	}
+}
+
+func TestCollections(t *testing.T) {
+	tests := []struct {
+		name       string
+		call       func(ctx context.Context, tr *TracksResource) (*Collection, error)
+		wantMethod string
+		wantPath   string
+		wantBody   string
+	}{
+		{
+			name: "add",
+			call: func(ctx context.Context, tr *TracksResource) (*Collection, error) {
+				return tr.AddToCollection(ctx, "col_1", []string{"trk_1", "trk_2"})
+			},
+			wantMethod: http.MethodPost,
+			wantPath:   "/v1/tracks/collections/col_1/tracks",
+			wantBody:   `{"trackIds":["trk_1","trk_2"]}`,
+		},
+		{
+			name: "remove",
+			call: func(ctx context.Context, tr *TracksResource) (*Collection, error) {
+				return tr.RemoveFromCollection(ctx, "col_1", "trk_2")
+			},
+			wantMethod: http.MethodDelete,
+			wantPath:   "/v1/tracks/collections/col_1/tracks/trk_2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var method, path, body string
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				method, path = r.Method, r.URL.Path
+				data, _ := io.ReadAll(r.Body)
+				body = strings.TrimSpace(string(data))
+				writeData(t, w, map[string]interface{}{"id": "col_1", "name": "Mix", "trackIds": []string{"trk_1"}})
+			})
+
+			collection, err := tt.call(context.Background(), client.Tracks)
+			if err != nil {
+				t.Fatalf("request: %v", err)
+			}
+			if method != tt.wantMethod || path != tt.wantPath {
+				t.Errorf("request = %s %s, want %s %s", method, path, tt.wantMethod, tt.wantPath)
+			}
+			if tt.wantBody != "" && body != tt.wantBody {
+				t.Errorf("body = %s, want %s", body, tt.wantBody)
+			}
+			if collection.ID != "col_1" || len(collection.TrackIDs) != 1 {
+				t.Errorf("collection = %+v", collection)
+			}
+		})
+	}
+
+	t.Run("list", func(t *testing.T) {
+		var query string
+		client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/v1/tracks/collections" {
+				t.Errorf("path = %s", r.URL.Path)
+			}
+			query = r.URL.RawQuery
+			writePage(t, w, []map[string]interface{}{{"id": "col_1"}, {"id": "col_2"}}, 2, 3, 42)
+		})
+
+		list, err := client.Tracks.ListCollections(context.Background(), 2, 10)
+		if err != nil {
+			t.Fatalf("ListCollections: %v", err)
+		}
+		if query != "page=2&perPage=10" {
+			t.Errorf("query = %q", query)
+		}
+		if items, ok := list.Items.([]interface{}); !ok || len(items) != 2 {
+			t.Errorf("items = %v", list.Items)
+		}
+		if list.Pagination.Total != 42 {
+			t.Errorf("total = %d, want 42", list.Pagination.Total)
+		}
+	})
+}
+
+func TestSoftDeleteAndRestore(t *testing.T) {
+	// The server keeps track state so that each case can follow a delete
+	// with a restore
+	newTrackServer := func(t *testing.T) *Client {
+		state := map[string]string{"trk_1": "active"}
+		return newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.Method == http.MethodDelete && r.URL.Path == "/v1/tracks/trk_1":
+				if r.URL.Query().Get("soft") == "true" {
+					state["trk_1"] = "trashed"
+				} else {
+					delete(state, "trk_1")
+				}
+				writeData(t, w, map[string]interface{}{"deleted": true})
+			case r.Method == http.MethodPost && r.URL.Path == "/v1/tracks/trk_1/restore":
+				if state["trk_1"] != "trashed" {
+					writeError(t, w, http.StatusNotFound, "NOT_FOUND", "Track not found in trash")
+					return
+				}
+				state["trk_1"] = "active"
+				writeData(t, w, map[string]interface{}{"id": "trk_1", "status": "active"})
+			case r.Method == http.MethodGet && r.URL.Path == "/v1/tracks/trash":
+				var items []map[string]interface{}
+				if state["trk_1"] == "trashed" {
+					items = append(items, map[string]interface{}{"id": "trk_1"})
+				}
+				writePage(t, w, items, 1, 1, len(items))
+			default:
+				t.Errorf("unexpected request %s %s", r.Method, r.URL)
+			}
+		})
+	}
+
+	tests := []struct {
+		name        string
+		opts        []DeleteOption
+		wantTrashed int
+		wantRestore bool
+	}{
+		{"soft delete", []DeleteOption{WithSoftDelete()}, 1, true},
+		{"permanent purge", nil, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTrackServer(t)
+			ctx := context.Background()
+
+			if _, err := client.Tracks.Delete(ctx, "trk_1", tt.opts...); err != nil {
+				t.Fatalf("Delete: %v", err)
+			}
+
+			trashed, err := client.Tracks.ListTrashed(ctx, 1, 20)
+			if err != nil {
+				t.Fatalf("ListTrashed: %v", err)
+			}
+			if items, _ := trashed.Items.([]interface{}); len(items) != tt.wantTrashed {
+				t.Errorf("trashed items = %v, want %d", trashed.Items, tt.wantTrashed)
+			}
+
+			track, err := client.Tracks.Restore(ctx, "trk_1")
+			if !tt.wantRestore {
+				var apiErr *APIError
+				if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+					t.Fatalf("Restore after purge: err = %v, want a 404 APIError", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Restore: %v", err)
+			}
+			if track.ID != "trk_1" {
+				t.Errorf("restored track = %+v", track)
+			}
+		})
+	}
+}
+
+func TestReplaceAudio(t *testing.T) {
+	stored := map[string]interface{}{
+		"id": "trk_1", "title": "Song", "artist": "Artist", "album": "Debut",
+		"genre": "indie", "status": "ready", "tags": []string{"remaster"},
+	}
+	var fields map[string][]string
+	var filename string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/v1/tracks/trk_1/audio" {
+			t.Errorf("request = %s %s", r.Method, r.URL.Path)
+		}
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Errorf("parsing upload: %v", err)
+			return
+		}
+		fields = r.MultipartForm.Value
+		if files := r.MultipartForm.File["file"]; len(files) == 1 {
+			filename = files[0].Filename
+		}
+		// The server keeps the stored metadata and reprocesses the audio
+		stored["status"] = "processing"
+		writeData(t, w, stored)
+	})
+
+	track, err := client.Tracks.ReplaceAudio(context.Background(), "trk_1", strings.NewReader("remastered"), "song-remaster.wav")
+	if err != nil {
+		t.Fatalf("ReplaceAudio: %v", err)
+	}
+	if filename != "song-remaster.wav" {
+		t.Errorf("uploaded filename = %q", filename)
+	}
+	if len(fields) != 0 {
+		t.Errorf("metadata fields sent with replacement audio: %v", fields)
+	}
+	if track.ID != "trk_1" || track.Title != "Song" || track.Artist != "Artist" || track.Album != "Debut" || track.Genre != "indie" {
+		t.Errorf("metadata not preserved: %+v", track)
+	}
+	if track.Status != "processing" {
+		t.Errorf("status = %q, want processing", track.Status)
+	}
+}
+
+func TestListAnalyses(t *testing.T) {
+	tests := []struct {
+		name      string
+		page      int
+		perPage   int
+		wantQuery string
+	}{
+		{"defaults", 0, 0, "page=1&perPage=20"},
+		{"second page", 2, 5, "page=2&perPage=5"},
+		{"capped page size", 1, 500, "page=1&perPage=100"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var query string
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/v1/tracks/trk_1/analyses" {
+					t.Errorf("path = %s", r.URL.Path)
+				}
+				query = r.URL.Query().Encode()
+				items := []map[string]interface{}{{"id": "ana_1", "status": "completed"}, {"id": "ana_2", "status": "processing"}}
+				writePage(t, w, items, max(tt.page, 1), 3, 12)
+			})
+
+			list, err := client.Tracks.ListAnalyses(context.Background(), "trk_1", tt.page, tt.perPage)
+			if err != nil {
+				t.Fatalf("ListAnalyses: %v", err)
+			}
+			if query != tt.wantQuery {
+				t.Errorf("query = %q, want %q", query, tt.wantQuery)
+			}
+			if items, _ := list.Items.([]interface{}); len(items) != 2 {
+				t.Errorf("items = %v", list.Items)
+			}
+			if list.Pagination.Page != max(tt.page, 1) || list.Pagination.TotalPages != 3 || list.Pagination.Total != 12 {
+				t.Errorf("pagination = %+v", list.Pagination)
+			}
+		})
+	}
+}
+
+func TestWaitForReady(t *testing.T) {
+	tests := []struct {
+		name            string
+		trackStatuses   []string
+		analyses        [][]string
+		requireAnalysis bool
+		wantErr         bool
+	}{
+		{"processing then ready", []string{"processing", "processing", "ready"}, nil, false, false},
+		{"processing failed", []string{"processing", "failed"}, nil, false, true},
+		{"waits for analysis", []string{"processing", "ready"}, [][]string{{}, {"processing"}, {"completed"}}, true, false},
+		{"analysis failed", []string{"ready"}, [][]string{{"failed"}}, true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var trackPolls, analysisPolls int
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/v1/tracks/trk_1":
+					status := tt.trackStatuses[min(trackPolls, len(tt.trackStatuses)-1)]
+					trackPolls++
+					writeData(t, w, map[string]interface{}{"id": "trk_1", "status": status})
+				case "/v1/tracks/trk_1/analyses":
+					var items []map[string]interface{}
+					for _, status := range tt.analyses[min(analysisPolls, len(tt.analyses)-1)] {
+						items = append(items, map[string]interface{}{"id": "ana_1", "status": status})
+					}
+					analysisPolls++
+					writePage(t, w, items, 1, 1, len(items))
+				default:
+					t.Errorf("unexpected path %s", r.URL.Path)
+				}
+			})
+
+			options := &WaitForReadyOptions{RequireAnalysis: tt.requireAnalysis, Poll: &PollOptions{Interval: time.Millisecond}}
+			track, err := client.Tracks.WaitForReady(context.Background(), "trk_1", options)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("WaitForReady: %v", err)
+			}
+			if track.Status != "ready" {
+				t.Errorf("status = %q, want ready", track.Status)
+			}
+			if trackPolls != len(tt.trackStatuses) {
+				t.Errorf("track polls = %d, want %d", trackPolls, len(tt.trackStatuses))
+			}
+			if analysisPolls != len(tt.analyses) {
+				t.Errorf("analysis polls = %d, want %d", analysisPolls, len(tt.analyses))
+			}
+		})
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	payload := map[string]interface{}{
+		"trackId": "trk_1", "fingerprint": "AQADtEmUaEkSRZEGAAAA", "hash": "9f2c1e7a", "algorithm": "chromaprint", "duration": 183.4,
+	}
+	var matchBody map[string]interface{}
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/v1/tracks/trk_1/fingerprint":
+			writeData(t, w, payload)
+		case r.Method == http.MethodPost && r.URL.Path == "/v1/tracks/fingerprint/match":
+			if err := json.NewDecoder(r.Body).Decode(&matchBody); err != nil {
+				t.Errorf("decoding body: %v", err)
+			}
+			writeData(t, w, []map[string]interface{}{
+				{"trackId": "trk_7", "title": "Cover", "artist": "Someone", "similarity": 0.97, "offset": 12.5},
+				{"trackId": "trk_9", "similarity": 0.81},
+			})
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	})
+
+	ctx := context.Background()
+	fp, err := client.Tracks.Fingerprint(ctx, "trk_1")
+	if err != nil {
+		t.Fatalf("Fingerprint: %v", err)
+	}
+	want := Fingerprint{TrackID: "trk_1", Fingerprint: "AQADtEmUaEkSRZEGAAAA", Hash: "9f2c1e7a", Algorithm: "chromaprint", Duration: 183.4}
+	if *fp != want {
+		t.Errorf("fingerprint = %+v, want %+v", *fp, want)
+	}
+
+	matches, err := client.Tracks.MatchFingerprint(ctx, fp)
+	if err != nil {
+		t.Fatalf("MatchFingerprint: %v", err)
+	}
+	if matchBody["hash"] != "9f2c1e7a" || matchBody["fingerprint"] != "AQADtEmUaEkSRZEGAAAA" {
+		t.Errorf("match body = %v", matchBody)
+	}
+	wantMatches := []FingerprintMatch{
+		{TrackID: "trk_7", Title: "Cover", Artist: "Someone", Similarity: 0.97, Offset: 12.5},
+		{TrackID: "trk_9", Similarity: 0.81},
+	}
+	if len(matches) != len(wantMatches) {
+		t.Fatalf("matches = %+v, want %+v", matches, wantMatches)
+	}
+	for i := range wantMatches {
+		if matches[i] != wantMatches[i] {
+			t.Errorf("match %d = %+v, want %+v", i, matches[i], wantMatches[i])
+		}
+	}
+
+	for _, empty := range []*Fingerprint{nil, {TrackID: "trk_1"}} {
+		if _, err := client.Tracks.MatchFingerprint(ctx, empty); err == nil {
+			t.Errorf("MatchFingerprint(%+v) accepted an empty fingerprint", empty)
+		}
+	}
+}
+
+func TestGenerateWaveforms(t *testing.T) {
+	const concurrency = 2
+	var inFlight, maxInFlight int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			m := atomic.LoadInt32(&maxInFlight)
+			if n <= m || atomic.CompareAndSwapInt32(&maxInFlight, m, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+
+		var body map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body["width"] != float64(800) {
+			t.Errorf("body = %v (%v), want the waveform options", body, err)
+		}
+		trackID := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/v1/tracks/"), "/waveform")
+		if trackID == "trk_3" {
+			writeError(t, w, http.StatusNotFound, "NOT_FOUND", "Track not found")
+			return
+		}
+		writeData(t, w, map[string]interface{}{"trackId": trackID, "url": "https://cdn.example.com/" + trackID + ".png"})
+	})
+
+	ids := []string{"trk_1", "trk_2", "trk_3", "trk_4", "trk_5"}
+	results := client.Tracks.GenerateWaveforms(context.Background(), ids, &WaveformOptions{Width: 800}, concurrency)
+
+	if len(results) != len(ids) {
+		t.Fatalf("results = %d, want %d", len(results), len(ids))
+	}
+	for i, result := range results {
+		if result.ID != ids[i] {
+			t.Errorf("result %d ID = %s, want %s", i, result.ID, ids[i])
+		}
+		if result.ID == "trk_3" {
+			var apiErr *APIError
+			if !errors.As(result.Err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+				t.Errorf("trk_3 error = %v, want a 404", result.Err)
+			}
+			continue
+		}
+		if result.Err != nil || result.Value["trackId"] != result.ID {
+			t.Errorf("result %s = %v, %v", result.ID, result.Value, result.Err)
+		}
+	}
+	if maxInFlight > concurrency {
+		t.Errorf("max in flight = %d, want at most %d", maxInFlight, concurrency)
+	}
+}
+
+func TestGetDownloadURL(t *testing.T) {
+	tests := []struct {
+		name      string
+		format    string
+		quality   string
+		wantQuery string
+		wantErr   bool
+	}{
+		{"mp3 320", "mp3", "320", "format=mp3&quality=320", false},
+		{"flac lossless", "flac", "lossless", "format=flac&quality=lossless", false},
+		{"format only", "wav", "", "format=wav", false},
+		{"server defaults", "", "", "", false},
+		{"unknown format", "ogg", "", "", true},
+		{"lossless mp3", "mp3", "lossless", "", true},
+		{"bitrate for wav", "wav", "320", "", true},
+		{"unknown bitrate", "mp3", "64", "", true},
+		{"quality only", "", "320", "quality=320", false},
+		{"unknown quality without format", "", "999", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			var query string
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				calls++
+				query = r.URL.Query().Encode()
+				writeData(t, w, map[string]interface{}{
+					"url": "https://cdn.example.com/trk_1", "format": tt.format, "quality": tt.quality,
+					"size": 10485760, "expiresAt": "2024-05-01T12:00:00Z",
+				})
+			})
+
+			download, err := client.Tracks.GetDownloadURL(context.Background(), "trk_1", tt.format, tt.quality)
+			if tt.wantErr {
+				if err == nil || calls != 0 {
+					t.Fatalf("err = %v after %d requests, want a rejection before sending", err, calls)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetDownloadURL: %v", err)
+			}
+			if query != tt.wantQuery {
+				t.Errorf("query = %q, want %q", query, tt.wantQuery)
+			}
+			want := DownloadURL{
+				URL: "https://cdn.example.com/trk_1", Format: tt.format, Quality: tt.quality,
+				Size: 10485760, ExpiresAt: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+			}
+			if *download != want {
+				t.Errorf("download = %+v, want %+v", *download, want)
+			}
+		})
+	}
+}
+
+func TestPatch(t *testing.T) {
+	var method, contentType, body string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		method, contentType = r.Method, r.Header.Get("Content-Type")
+		data, _ := io.ReadAll(r.Body)
+		body = string(data)
+		writeData(t, w, map[string]interface{}{"id": "trk_1", "title": "New Title", "genre": ""})
+	})
+
+	patch := map[string]interface{}{"title": "New Title", "genre": nil, "metadata": map[string]interface{}{"mood": "calm"}}
+	track, err := client.Tracks.Patch(context.Background(), "trk_1", patch)
+	if err != nil {
+		t.Fatalf("Patch: %v", err)
+	}
+	if method != http.MethodPatch {
+		t.Errorf("method = %s, want PATCH", method)
+	}
+	if contentType != "application/merge-patch+json" {
+		t.Errorf("Content-Type = %q, want application/merge-patch+json", contentType)
+	}
+	if body != `{"genre":null,"metadata":{"mood":"calm"},"title":"New Title"}` {
+		t.Errorf("body = %s", body)
+	}
+	if track.Title != "New Title" {
+		t.Errorf("track = %+v", track)
+	}
+
+	// Other JSON requests keep the plain JSON content type
+	if err := client.Post(context.Background(), "/tracks/trk_1/notes", map[string]string{"note": "x"}, nil); err != nil {
+		t.Fatalf("Post: %v", err)
+	}
+	if contentType != "application/json" {
+		t.Errorf("POST Content-Type = %q, want application/json", contentType)
+	}
+}
+
+func TestGetMany(t *testing.T) {
+	tests := []struct {
+		name         string
+		bulkStatus   int
+		wantBulk     int32
+		wantSingle   int32
+		wantTracks   []string
+		wantNotFound []string
+		wantFailed   []string
+	}{
+		{
+			name:         "bulk endpoint",
+			bulkStatus:   http.StatusOK,
+			wantBulk:     1,
+			wantTracks:   []string{"trk_1", "trk_2", "trk_4", "trk_5", "trk_6"},
+			wantNotFound: []string{"trk_3"},
+		},
+		{
+			name:         "fan out without bulk endpoint",
+			bulkStatus:   http.StatusNotFound,
+			wantBulk:     1,
+			wantSingle:   6,
+			wantTracks:   []string{"trk_1", "trk_2", "trk_4", "trk_5", "trk_6"},
+			wantNotFound: []string{"trk_3"},
+		},
+		{
+			name:       "bulk request rejected",
+			bulkStatus: http.StatusBadRequest,
+			wantBulk:   1,
+			wantFailed: []string{"trk_1", "trk_2", "trk_3", "trk_4", "trk_5", "trk_6"},
+		},
+	}
+
+	ids := []string{"trk_1", "trk_2", "trk_3", "trk_4", "trk_5", "trk_6"}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var bulkCalls, singleCalls, inFlight, maxInFlight int32
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/v1/tracks/batch" {
+					atomic.AddInt32(&bulkCalls, 1)
+					if got := r.URL.Query().Get("ids"); got != strings.Join(ids, ",") {
+						t.Errorf("ids = %q, want %q", got, strings.Join(ids, ","))
+					}
+					switch tt.bulkStatus {
+					case http.StatusOK:
+						var tracks []map[string]interface{}
+						for _, id := range ids {
+							if id != "trk_3" {
+								tracks = append(tracks, map[string]interface{}{"id": id, "title": "Title " + id})
+							}
+						}
+						writeData(t, w, tracks)
+					case http.StatusNotFound:
+						writeError(t, w, http.StatusNotFound, "NOT_FOUND", "Route not found")
+					default:
+						writeError(t, w, tt.bulkStatus, "INVALID_REQUEST", "Too many ids")
+					}
+					return
+				}
+
+				atomic.AddInt32(&singleCalls, 1)
+				n := atomic.AddInt32(&inFlight, 1)
+				defer atomic.AddInt32(&inFlight, -1)
+				for {
+					m := atomic.LoadInt32(&maxInFlight)
+					if n <= m || atomic.CompareAndSwapInt32(&maxInFlight, m, n) {
+						break
+					}
+				}
+				time.Sleep(10 * time.Millisecond)
+
+				id := strings.TrimPrefix(r.URL.Path, "/v1/tracks/")
+				if id == "trk_3" {
+					writeError(t, w, http.StatusNotFound, "NOT_FOUND", "Track not found")
+					return
+				}
+				writeData(t, w, map[string]interface{}{"id": id, "title": "Title " + id})
+			})
+
+			tracks, errs := client.Tracks.GetMany(context.Background(), ids)
+
+			if bulkCalls != tt.wantBulk || singleCalls != tt.wantSingle {
+				t.Errorf("requests = %d bulk, %d single; want %d, %d", bulkCalls, singleCalls, tt.wantBulk, tt.wantSingle)
+			}
+			if maxInFlight > DefaultBatchConcurrency {
+				t.Errorf("max in flight = %d, want at most %d", maxInFlight, DefaultBatchConcurrency)
+			}
+			if len(tracks) != len(tt.wantTracks) {
+				t.Errorf("got %d tracks, want %d", len(tracks), len(tt.wantTracks))
+			}
+			for _, id := range tt.wantTracks {
+				if track := tracks[id]; track == nil || track.ID != id || track.Title != "Title "+id {
+					t.Errorf("track %s = %+v", id, track)
+				}
+			}
+			if len(errs) != len(tt.wantNotFound)+len(tt.wantFailed) {
+				t.Errorf("errors = %v", errs)
+			}
+			for _, id := range tt.wantNotFound {
+				var apiErr *APIError
+				if !errors.As(errs[id], &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+					t.Errorf("%s error = %v, want a 404", id, errs[id])
+				}
+			}
+			for _, id := range tt.wantFailed {
+				var apiErr *APIError
+				if !errors.As(errs[id], &apiErr) || apiErr.StatusCode != tt.bulkStatus {
+					t.Errorf("%s error = %v, want a %d", id, errs[id], tt.bulkStatus)
+				}
+			}
+		})
+	}
+
+	t.Run("no ids", func(t *testing.T) {
+		client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		})
+		tracks, errs := client.Tracks.GetMany(context.Background(), nil)
+		if len(tracks) != 0 || len(errs) != 0 {
+			t.Errorf("got %v, %v; want empty maps", tracks, errs)
+		}
+	})
+}
+
+func TestListWithOptions(t *testing.T) {
+	tests := []struct {
+		name      string
+		options   ListOptions
+		wantQuery string
+	}{
+		{
+			name:      "page and sort",
+			options:   ListOptions{Page: 3, PerPage: 50, Sort: "-createdAt"},
+			wantQuery: "page=3&perPage=50&sort=-createdAt",
+		},
+		{
+			name:      "filters",
+			options:   ListOptions{Page: 2, PerPage: 10, Filter: map[string]string{"genre": "house", "status": "ready"}},
+			wantQuery: "genre=house&page=2&perPage=10&status=ready",
+		},
+		{
+			name:      "filter cannot override pagination",
+			options:   ListOptions{Page: 2, PerPage: 10, Filter: map[string]string{"page": "9", "perPage": "1000"}},
+			wantQuery: "page=2&perPage=10",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/v1/tracks" {
+					t.Errorf("path = %s, want /v1/tracks", r.URL.Path)
+				}
+				if got := r.URL.Query().Encode(); got != tt.wantQuery {
+					t.Errorf("query = %q, want %q", got, tt.wantQuery)
+				}
+				writePage(t, w, []map[string]interface{}{{"id": "trk_1", "title": "First"}}, tt.options.Page, 4, 31)
+			})
+
+			list, err := client.Tracks.ListWithOptions(context.Background(), tt.options)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(list.Items) != 1 || list.Items[0].ID != "trk_1" || list.Items[0].Title != "First" {
+				t.Errorf("items = %+v", list.Items)
+			}
+			if list.Pagination.Page != tt.options.Page || list.Pagination.TotalPages != 4 || list.Pagination.Total != 31 {
+				t.Errorf("pagination = %+v", list.Pagination)
+			}
+		})
+	}
+}
+
+func TestPriority(t *testing.T) {
+	submissions := map[string]func(ctx context.Context, client *Client, priority Priority) error{
+		"batch process": func(ctx context.Context, client *Client, priority Priority) error {
+			_, err := client.Tracks.BatchProcess(ctx, []string{"trk_1"}, &BatchProcessOptions{Priority: priority})
+			return err
+		},
+		"analysis upload": func(ctx context.Context, client *Client, priority Priority) error {
+			_, err := client.Analysis.UploadTrack(ctx, strings.NewReader("RIFF"), "track.wav", &AnalysisOptions{Priority: priority})
+			return err
+		},
+		"melody generation": func(ctx context.Context, client *Client, priority Priority) error {
+			_, err := client.Copilot.GenerateMelody(ctx, MelodyOptions{Style: "pop", Priority: priority})
+			return err
+		},
+	}
+	tests := []struct {
+		name     string
+		priority Priority
+		wantSent string
+		wantErr  bool
+	}{
+		{name: "high", priority: PriorityHigh, wantSent: "high"},
+		{name: "low", priority: PriorityLow, wantSent: "low"},
+		{name: "unset", priority: ""},
+		{name: "unknown", priority: "urgent", wantErr: true},
+	}
+
+	for name, submit := range submissions {
+		for _, tt := range tests {
+			t.Run(name+"/"+tt.name, func(t *testing.T) {
+				requests := 0
+				client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+					requests++
+					fields := requestFields(t, r)
+					if priority, ok := fields["priority"]; priority != tt.wantSent || ok != (tt.wantSent != "") {
+						t.Errorf("priority = %q (sent %t), want %q", priority, ok, tt.wantSent)
+					}
+					writeData(t, w, map[string]interface{}{"id": "job_1", "status": "queued"})
+				})
+
+				err := submit(context.Background(), client, tt.priority)
+				if !tt.wantErr {
+					if err != nil {
+						t.Fatalf("unexpected error: %v", err)
+					}
+					return
+				}
+				var apiErr *APIError
+				if !errors.As(err, &apiErr) || apiErr.Code != "INVALID_REQUEST" {
+					t.Errorf("err = %v, want an INVALID_REQUEST error", err)
+				}
+				if requests != 0 {
+					t.Errorf("sent %d requests with an invalid priority", requests)
+				}
+			})
+		}
+	}
+}
+
+func TestAddAndRemoveTags(t *testing.T) {
+	tests := []struct {
+		name     string
+		initial  []string
+		add      []string
+		remove   []string
+		wantSent []string
+		wantTags []string
+		wantErr  bool
+	}{
+		{
+			name:     "add",
+			initial:  []string{"house"},
+			add:      []string{"summer", "vocal"},
+			wantSent: []string{"summer", "vocal"},
+			wantTags: []string{"house", "summer", "vocal"},
+		},
+		{
+			name:     "add duplicates and whitespace",
+			initial:  []string{"house"},
+			add:      []string{" summer", "summer ", "house", ""},
+			wantSent: []string{"summer", "house"},
+			wantTags: []string{"house", "summer"},
+		},
+		{
+			name:     "remove",
+			initial:  []string{"house", "summer", "vocal"},
+			remove:   []string{"summer", "summer", "missing"},
+			wantSent: []string{"summer", "missing"},
+			wantTags: []string{"house", "vocal"},
+		},
+		{
+			name:    "only blank tags",
+			initial: []string{"house"},
+			add:     []string{" ", ""},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tags := append([]string(nil), tt.initial...)
+			var sent []string
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				var body struct {
+					Tags []string `json:"tags"`
+				}
+				if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+					t.Errorf("decoding body: %v", err)
+				}
+				sent = body.Tags
+
+				listed := make(map[string]bool, len(body.Tags))
+				for _, tag := range body.Tags {
+					listed[tag] = true
+				}
+				switch r.URL.Path {
+				case "/v1/tracks/trk_1/tags":
+					for _, tag := range tags {
+						delete(listed, tag)
+					}
+					for _, tag := range body.Tags {
+						if listed[tag] {
+							tags = append(tags, tag)
+						}
+					}
+				case "/v1/tracks/trk_1/tags/remove":
+					kept := tags[:0]
+					for _, tag := range tags {
+						if !listed[tag] {
+							kept = append(kept, tag)
+						}
+					}
+					tags = kept
+				default:
+					t.Errorf("unexpected path %s", r.URL.Path)
+				}
+				writeData(t, w, map[string]interface{}{"id": "trk_1", "tags": tags})
+			})
+
+			var track *Track
+			var err error
+			if tt.remove != nil {
+				track, err = client.Tracks.RemoveTags(context.Background(), "trk_1", tt.remove)
+			} else {
+				track, err = client.Tracks.AddTags(context.Background(), "trk_1", tt.add)
+			}
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				if sent != nil {
+					t.Errorf("sent %v, want no request", sent)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if strings.Join(sent, ",") != strings.Join(tt.wantSent, ",") {
+				t.Errorf("tags sent = %v, want %v", sent, tt.wantSent)
+			}
+			if strings.Join(track.Tags, ",") != strings.Join(tt.wantTags, ",") {
+				t.Errorf("track tags = %v, want %v", track.Tags, tt.wantTags)
+			}
+		})
+	}
+}
+
+func TestListFiltersByTags(t *testing.T) {
+	tests := []struct {
+		name      string
+		filter    TrackFilter
+		opts      []ClientOption
+		wantQuery string
+		wantErr   bool
+	}{
+		{
+			name:      "single tag",
+			filter:    TrackFilter{Tags: []string{"house"}},
+			wantQuery: "page=1&perPage=20&tags=house",
+		},
+		{
+			name:      "all tags",
+			filter:    TrackFilter{Tags: []string{"house", "vocal"}, TagMatch: TagMatchAll},
+			wantQuery: "page=1&perPage=20&tagMatch=all&tags=house%2Cvocal",
+		},
+		{
+			name:      "any tag",
+			filter:    TrackFilter{Tags: []string{"house", "vocal"}, TagMatch: TagMatchAny},
+			wantQuery: "page=1&perPage=20&tagMatch=any&tags=house%2Cvocal",
+		},
+		{
+			name:      "repeated parameters",
+			filter:    TrackFilter{Tags: []string{"house", "vocal"}, TagMatch: TagMatchAny},
+			opts:      []ClientOption{WithListParamStyle(ListParamRepeat)},
+			wantQuery: "page=1&perPage=20&tagMatch=any&tags=house&tags=vocal",
+		},
+		{
+			name:    "unknown match mode",
+			filter:  TrackFilter{Tags: []string{"house", "vocal"}, TagMatch: "some"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests := 0
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				if got := r.URL.Query().Encode(); got != tt.wantQuery {
+					t.Errorf("query = %q, want %q", got, tt.wantQuery)
+				}
+				writePage(t, w, []map[string]interface{}{}, 1, 1, 0)
+			}, tt.opts...)
+
+			_, err := client.Tracks.List(context.Background(), 1, 20, &tt.filter)
+			if tt.wantErr {
+				if err == nil || requests != 0 {
+					t.Errorf("err = %v after %d requests, want an error before sending", err, requests)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestUploadTrackArtwork(t *testing.T) {
+	const variants = `{
+		"url": "https://cdn.example.com/trk_1/cover.png",
+		"width": 3000,
+		"height": 3000,
+		"format": "png",
+		"variants": [
+			{"platform": "spotify", "url": "https://cdn.example.com/trk_1/spotify.jpg", "width": 640, "height": 640, "format": "jpeg"},
+			{"platform": "apple", "url": "https://cdn.example.com/trk_1/apple.jpg", "width": 3000, "height": 3000, "format": "jpeg"},
+			{"url": "https://cdn.example.com/trk_1/1400.png", "width": 1400, "height": 1400, "format": "png"}
+		]
+	}`
+
+	tests := []struct {
+		name         string
+		image        []byte
+		options      *ArtworkOptions
+		wantFields   map[string]string
+		wantVariants []ArtworkVariant
+		wantErr      bool
+	}{
+		{
+			name:       "no conversion",
+			image:      encodeImage(t, "jpeg", MinArtworkSize, MinArtworkSize),
+			wantFields: map[string]string{},
+		},
+		{
+			name:  "platform and size variants",
+			image: encodeImage(t, "png", 3000, 3000),
+			options: &ArtworkOptions{
+				Platforms: []string{"spotify", "apple"},
+				Sizes:     []int{1400},
+				Formats:   []string{"png"},
+			},
+			wantFields: map[string]string{"platforms": "spotify,apple", "sizes": "1400", "formats": "png"},
+			wantVariants: []ArtworkVariant{
+				{Platform: "spotify", URL: "https://cdn.example.com/trk_1/spotify.jpg", Width: 640, Height: 640, Format: "jpeg"},
+				{Platform: "apple", URL: "https://cdn.example.com/trk_1/apple.jpg", Width: 3000, Height: 3000, Format: "jpeg"},
+				{URL: "https://cdn.example.com/trk_1/1400.png", Width: 1400, Height: 1400, Format: "png"},
+			},
+		},
+		{
+			name:    "too small",
+			image:   encodeImage(t, "jpeg", 1000, 1000),
+			options: &ArtworkOptions{Platforms: []string{"spotify"}},
+			wantErr: true,
+		},
+		{
+			name:    "too small without conversion",
+			image:   encodeImage(t, "png", 1000, 1000),
+			wantErr: true,
+		},
+		{
+			name:    "not an image without conversion",
+			image:   []byte("GIF89a not supported"),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uploads := 0
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				uploads++
+				if r.URL.Path != "/v1/tracks/trk_1/artwork" {
+					t.Errorf("path = %s", r.URL.Path)
+				}
+				if fields := requestFields(t, r); !reflect.DeepEqual(fields, tt.wantFields) {
+					t.Errorf("fields = %v, want %v", fields, tt.wantFields)
+				}
+				if tt.wantVariants == nil {
+					writeData(t, w, map[string]interface{}{"url": "https://cdn.example.com/trk_1/cover.png", "width": 3000, "height": 3000})
+					return
+				}
+				writeData(t, w, json.RawMessage(variants))
+			})
+
+			result, err := client.Tracks.UploadArtwork(context.Background(), "trk_1", bytes.NewReader(tt.image), "cover.png", tt.options)
+			if tt.wantErr {
+				var apiErr *APIError
+				if !errors.As(err, &apiErr) || apiErr.Code != "INVALID_REQUEST" {
+					t.Errorf("err = %v, want an INVALID_REQUEST error", err)
+				}
+				if uploads != 0 {
+					t.Error("invalid artwork was uploaded")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("UploadArtwork: %v", err)
+			}
+			if result.URL != "https://cdn.example.com/trk_1/cover.png" || result.Width != 3000 {
+				t.Errorf("result = %+v", result)
+			}
+			if !reflect.DeepEqual(result.Variants, tt.wantVariants) {
+				t.Errorf("variants = %+v, want %+v", result.Variants, tt.wantVariants)
+			}
+		})
+	}
+}
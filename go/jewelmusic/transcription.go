@@ -3,6 +3,7 @@ package jewelmusic
 import (
 	"context"
 	"io"
+	"net/url"
 	"strconv"
 	"strings"
 )
@@ -14,21 +15,21 @@ type TranscriptionResource struct {
 
 // TranscriptionOptions represents options for transcription creation
 type TranscriptionOptions struct {
-	Languages            []string `json:"languages,omitempty"`
-	IncludeTimestamps    bool     `json:"includeTimestamps,omitempty"`
-	WordLevelTimestamps  bool     `json:"wordLevelTimestamps,omitempty"`
-	SpeakerDiarization   bool     `json:"speakerDiarization,omitempty"`
-	Model                string   `json:"model,omitempty"`
-	MaxSpeakers          int      `json:"maxSpeakers,omitempty"`
+	Languages           []string `json:"languages,omitempty"`
+	IncludeTimestamps   bool     `json:"includeTimestamps,omitempty"`
+	WordLevelTimestamps bool     `json:"wordLevelTimestamps,omitempty"`
+	SpeakerDiarization  bool     `json:"speakerDiarization,omitempty"`
+	Model               string   `json:"model,omitempty"`
+	MaxSpeakers         int      `json:"maxSpeakers,omitempty"`
 }
 
 // LyricsEnhancementOptions represents options for lyrics enhancement
 type LyricsEnhancementOptions struct {
-	ImproveMeter    bool   `json:"improveMeter,omitempty"`
-	EnhanceRhyming  bool   `json:"enhanceRhyming,omitempty"`
-	AdjustTone      string `json:"adjustTone,omitempty"`
-	TargetLanguage  string `json:"targetLanguage,omitempty"`
-	PreserveStyle   bool   `json:"preserveStyle,omitempty"`
+	ImproveMeter   bool   `json:"improveMeter,omitempty"`
+	EnhanceRhyming bool   `json:"enhanceRhyming,omitempty"`
+	AdjustTone     string `json:"adjustTone,omitempty"`
+	TargetLanguage string `json:"targetLanguage,omitempty"`
+	PreserveStyle  bool   `json:"preserveStyle,omitempty"`
 }
 
 // TranslationOptions represents options for lyrics translation
@@ -40,42 +41,24 @@ type TranslationOptions struct {
 
 // Create creates a new transcription from track ID or file
 func (tr *TranscriptionResource) Create(ctx context.Context, trackID string, file io.Reader, filename string, options *TranscriptionOptions) (*Transcription, error) {
+	if options == nil {
+		options = tr.client.defaultTranscriptionOptions
+	}
+
 	if trackID != "" {
 		// Create from existing track
-		requestData := map[string]interface{}{
-			"trackId": trackID,
-		}
-		
-		if options != nil {
-			if len(options.Languages) > 0 {
-				requestData["languages"] = options.Languages
-			}
-			if options.IncludeTimestamps {
-				requestData["includeTimestamps"] = true
-			}
-			if options.WordLevelTimestamps {
-				requestData["wordLevelTimestamps"] = true
-			}
-			if options.SpeakerDiarization {
-				requestData["speakerDiarization"] = true
-			}
-			if options.Model != "" {
-				requestData["model"] = options.Model
-			}
-			if options.MaxSpeakers > 0 {
-				requestData["maxSpeakers"] = options.MaxSpeakers
-			}
-		}
+		requestData := transcriptionRequestData(options)
+		requestData["trackId"] = trackID
 
 		var result Transcription
 		err := tr.client.Post(ctx, "/transcription/create", requestData, &result)
 		return &result, err
 	}
-	
+
 	if file != nil {
 		// Create from file upload
 		metadata := make(map[string]string)
-		
+
 		if options != nil {
 			if len(options.Languages) > 0 {
 				metadata["languages"] = strings.Join(options.Languages, ",")
@@ -106,10 +89,101 @@ func (tr *TranscriptionResource) Create(ctx context.Context, trackID string, fil
 		// Convert response data to Transcription struct
 		return &result, nil
 	}
-	
+
 	return nil, &APIError{Code: "INVALID_REQUEST", Message: "Either trackId or file must be provided"}
 }
 
+// CreateFromPath creates a new transcription from a local audio file
+func (tr *TranscriptionResource) CreateFromPath(ctx context.Context, path string, options *TranscriptionOptions) (*Transcription, error) {
+	return uploadFromPath(path, func(file io.Reader, filename string) (*Transcription, error) {
+		return tr.Create(ctx, "", file, filename, options)
+	})
+}
+
+// CreateFromURL creates a new transcription from publicly hosted audio.
+// The server fetches the audio itself; the URL must use http or https.
+func (tr *TranscriptionResource) CreateFromURL(ctx context.Context, audioURL string, options *TranscriptionOptions) (*Transcription, error) {
+	if options == nil {
+		options = tr.client.defaultTranscriptionOptions
+	}
+
+	parsed, err := url.Parse(audioURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return nil, &APIError{Code: "INVALID_REQUEST", Message: "Audio URL must be an absolute http or https URL"}
+	}
+
+	requestData := transcriptionRequestData(options)
+	requestData["audioUrl"] = audioURL
+
+	var result Transcription
+	err = tr.client.Post(ctx, "/transcription/create", requestData, &result)
+	return &result, err
+}
+
+// TranscriptionBatch represents the result of a batch transcription request
+type TranscriptionBatch struct {
+	Jobs   []Transcription `json:"jobs"`
+	Failed []BatchError    `json:"failed,omitempty"`
+}
+
+// HasFailures reports whether any track in the batch could not be queued
+func (b *TranscriptionBatch) HasFailures() bool {
+	return len(b.Failed) > 0
+}
+
+// CreateBatch creates transcriptions for multiple tracks in a single request.
+// Tracks that could not be queued are reported in Failed rather than failing
+// the whole batch. The batch is rejected with a *QuotaExceededError if it
+// exceeds the remaining transcription quota, unless the client was created
+// with WithoutQuotaCheck.
+func (tr *TranscriptionResource) CreateBatch(ctx context.Context, trackIDs []string, options *TranscriptionOptions) (*TranscriptionBatch, error) {
+	if options == nil {
+		options = tr.client.defaultTranscriptionOptions
+	}
+
+	if len(trackIDs) == 0 {
+		return nil, &APIError{Code: "INVALID_REQUEST", Message: "At least one track ID is required"}
+	}
+	if err := tr.client.checkQuota(ctx, "transcriptions", len(trackIDs)); err != nil {
+		return nil, err
+	}
+
+	requestData := transcriptionRequestData(options)
+	requestData["trackIds"] = trackIDs
+
+	var result TranscriptionBatch
+	err := tr.client.Post(ctx, "/transcription/batch", requestData, &result)
+	return &result, err
+}
+
+// transcriptionRequestData converts transcription options into a JSON request body
+func transcriptionRequestData(options *TranscriptionOptions) map[string]interface{} {
+	requestData := make(map[string]interface{})
+	if options == nil {
+		return requestData
+	}
+
+	if len(options.Languages) > 0 {
+		requestData["languages"] = options.Languages
+	}
+	if options.IncludeTimestamps {
+		requestData["includeTimestamps"] = true
+	}
+	if options.WordLevelTimestamps {
+		requestData["wordLevelTimestamps"] = true
+	}
+	if options.SpeakerDiarization {
+		requestData["speakerDiarization"] = true
+	}
+	if options.Model != "" {
+		requestData["model"] = options.Model
+	}
+	if options.MaxSpeakers > 0 {
+		requestData["maxSpeakers"] = options.MaxSpeakers
+	}
+	return requestData
+}
+
 // Get retrieves a transcription by ID
 func (tr *TranscriptionResource) Get(ctx context.Context, transcriptionID string) (*Transcription, error) {
 	var result Transcription
@@ -118,16 +192,46 @@ func (tr *TranscriptionResource) Get(ctx context.Context, transcriptionID string
 }
 
 // GetStatus gets the status of a transcription
-func (tr *TranscriptionResource) GetStatus(ctx context.Context, transcriptionID string) (map[string]interface{}, error) {
-	var result map[string]interface{}
+func (tr *TranscriptionResource) GetStatus(ctx context.Context, transcriptionID string) (*TranscriptionStatus, error) {
+	var result TranscriptionStatus
 	err := tr.client.Get(ctx, "/transcription/"+transcriptionID+"/status", nil, &result)
-	return result, err
+	return &result, err
+}
+
+// Low-confidence segment handling modes for downloads
+const (
+	LowConfidenceDrop = "drop"
+	LowConfidenceFlag = "flag"
+)
+
+// TranscriptionDownloadOptions represents options for transcription downloads
+type TranscriptionDownloadOptions struct {
+	Format        string  `json:"format"`
+	MinConfidence float64 `json:"minConfidence,omitempty"`
+	LowConfidence string  `json:"lowConfidence,omitempty"`
 }
 
 // Download downloads transcription in the specified format
 func (tr *TranscriptionResource) Download(ctx context.Context, transcriptionID string, format string) (map[string]interface{}, error) {
+	return tr.DownloadWithOptions(ctx, transcriptionID, TranscriptionDownloadOptions{Format: format})
+}
+
+// DownloadWithOptions downloads a transcription, optionally dropping or
+// flagging segments below a confidence threshold
+func (tr *TranscriptionResource) DownloadWithOptions(ctx context.Context, transcriptionID string, options TranscriptionDownloadOptions) (map[string]interface{}, error) {
 	params := map[string]string{
-		"format": format,
+		"format": options.Format,
+	}
+	if options.MinConfidence > 0 {
+		params["minConfidence"] = strconv.FormatFloat(options.MinConfidence, 'f', -1, 64)
+		switch options.LowConfidence {
+		case "", LowConfidenceDrop:
+			params["lowConfidence"] = LowConfidenceDrop
+		case LowConfidenceFlag:
+			params["lowConfidence"] = LowConfidenceFlag
+		default:
+			return nil, &APIError{Code: "INVALID_REQUEST", Message: "Invalid low-confidence mode: " + options.LowConfidence}
+		}
 	}
 
 	var result map[string]interface{}
@@ -136,11 +240,21 @@ func (tr *TranscriptionResource) Download(ctx context.Context, transcriptionID s
 }
 
 // TranslateLyrics translates lyrics to target languages
-func (tr *TranscriptionResource) TranslateLyrics(ctx context.Context, transcriptionID string, targetLanguages []string, options *TranslationOptions) (map[string]interface{}, error) {
+// The result is keyed by ISO 639-1 language code.
+func (tr *TranscriptionResource) TranslateLyrics(ctx context.Context, transcriptionID string, targetLanguages []string, options *TranslationOptions) (map[string]Translation, error) {
+	if len(targetLanguages) == 0 {
+		return nil, &APIError{Code: "INVALID_REQUEST", Message: "At least one target language is required"}
+	}
+	for _, lang := range targetLanguages {
+		if !IsValidLanguageCode(lang) {
+			return nil, &APIError{Code: "INVALID_REQUEST", Message: "Invalid ISO 639-1 language code: " + lang}
+		}
+	}
+
 	requestData := map[string]interface{}{
 		"targetLanguages": targetLanguages,
 	}
-	
+
 	if options != nil {
 		if options.PreserveRhyme {
 			requestData["preserveRhyme"] = true
@@ -153,21 +267,27 @@ func (tr *TranscriptionResource) TranslateLyrics(ctx context.Context, transcript
 		}
 	}
 
-	var result map[string]interface{}
-	err := tr.client.Post(ctx, "/transcription/"+transcriptionID+"/translate", requestData, &result)
-	return result, err
+	var result struct {
+		Translations map[string]Translation `json:"translations"`
+	}
+	if err := tr.client.Post(ctx, "/transcription/"+transcriptionID+"/translate", requestData, &result); err != nil {
+		return nil, err
+	}
+	return result.Translations, nil
 }
 
 // SyncLyrics synchronizes lyrics with audio file
-func (tr *TranscriptionResource) SyncLyrics(ctx context.Context, transcriptionID string, audioFile io.Reader, filename string) (map[string]interface{}, error) {
+func (tr *TranscriptionResource) SyncLyrics(ctx context.Context, transcriptionID string, audioFile io.Reader, filename string) (*SyncedLyrics, error) {
 	resp, err := tr.client.UploadFile(ctx, "/transcription/"+transcriptionID+"/sync", audioFile, filename, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	result := make(map[string]interface{})
-	// Convert response data to map
-	return result, nil
+	var result SyncedLyrics
+	if err := resp.DecodeData(&result); err != nil {
+		return nil, err
+	}
+	return &result, nil
 }
 
 // EnhanceLyrics enhances lyrics with AI
@@ -175,7 +295,7 @@ func (tr *TranscriptionResource) EnhanceLyrics(ctx context.Context, lyrics strin
 	requestData := map[string]interface{}{
 		"lyrics": lyrics,
 	}
-	
+
 	if options != nil {
 		if options.ImproveMeter {
 			requestData["improveMeter"] = true
@@ -235,11 +355,8 @@ func (tr *TranscriptionResource) CheckLanguageQuality(ctx context.Context, lyric
 
 // List lists user's transcriptions with pagination
 func (tr *TranscriptionResource) List(ctx context.Context, page, perPage int, status, language string) (*ListResponse, error) {
-	params := map[string]string{
-		"page":    strconv.Itoa(page),
-		"perPage": strconv.Itoa(perPage),
-	}
-	
+	params := pageParams(page, perPage)
+
 	if status != "" {
 		params["status"] = status
 	}
@@ -250,4 +367,18 @@ func (tr *TranscriptionResource) List(ctx context.Context, page, perPage int, st
 	var result ListResponse
 	err := tr.client.Get(ctx, "/transcription", params, &result)
 	return &result, err
-}
\ No newline at end of file
+}
+
+// WaitForTranscription polls the status of a transcription until it reaches a
+// terminal status and then returns the full transcription
+func (tr *TranscriptionResource) WaitForTranscription(ctx context.Context, transcriptionID string, options *PollOptions) (*Transcription, error) {
+	_, err := Poll(ctx, func() (*TranscriptionStatus, error) {
+		return tr.GetStatus(detachCallOptions(ctx), transcriptionID)
+	}, func(status *TranscriptionStatus) bool {
+		return IsTerminalStatus(status.Status)
+	}, tr.client.pollOptions(options))
+	if err != nil {
+		return nil, err
+	}
+	return tr.Get(detachCallOptions(ctx), transcriptionID)
+}
@@ -0,0 +1,370 @@
+package jewelmusic
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGetStatus(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload map[string]interface{}
+		want    TranscriptionStatus
+	}{
+		{
+			name:    "in progress",
+			payload: map[string]interface{}{"status": "processing", "progress": 0.42, "stage": "alignment", "eta": 35},
+			want:    TranscriptionStatus{Status: "processing", Progress: 0.42, Stage: "alignment", ETA: 35},
+		},
+		{
+			name:    "completed",
+			payload: map[string]interface{}{"status": "completed", "progress": 1},
+			want:    TranscriptionStatus{Status: "completed", Progress: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/v1/transcription/tr_1/status" {
+					t.Errorf("path = %s", r.URL.Path)
+				}
+				writeData(t, w, tt.payload)
+			})
+
+			status, err := client.Transcription.GetStatus(context.Background(), "tr_1")
+			if err != nil {
+				t.Fatalf("GetStatus: %v", err)
+			}
+			if *status != tt.want {
+				t.Errorf("status = %+v, want %+v", *status, tt.want)
+			}
+		})
+	}
+}
+
+func TestWaitForTranscriptionPollsStatus(t *testing.T) {
+	progress := []float64{0.1, 0.6, 1}
+	statusCalls := 0
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/transcription/tr_1/status":
+			status := "processing"
+			if statusCalls == len(progress)-1 {
+				status = "completed"
+			}
+			writeData(t, w, map[string]interface{}{"status": status, "progress": progress[statusCalls]})
+			statusCalls++
+		case "/v1/transcription/tr_1":
+			writeData(t, w, map[string]interface{}{"id": "tr_1", "status": "completed", "text": "la la la"})
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+	})
+
+	transcription, err := client.Transcription.WaitForTranscription(context.Background(), "tr_1", &PollOptions{Interval: time.Millisecond})
+	if err != nil {
+		t.Fatalf("WaitForTranscription: %v", err)
+	}
+	if statusCalls != len(progress) {
+		t.Errorf("status calls = %d, want %d", statusCalls, len(progress))
+	}
+	if transcription.Text != "la la la" {
+		t.Errorf("transcription = %+v", transcription)
+	}
+}
+
+func TestSyncLyrics(t *testing.T) {
+	tests := []struct {
+		name      string
+		segments  []map[string]interface{}
+		wantStart []float64
+		wantWords []int
+	}{
+		{
+			name: "word timings",
+			segments: []map[string]interface{}{
+				{"text": "hello world", "startTime": 1.25, "endTime": 2.5, "words": []map[string]interface{}{
+					{"text": "hello", "startTime": 1.25, "endTime": 1.8},
+					{"text": "world", "startTime": 1.9, "endTime": 2.5},
+				}},
+				{"text": "again", "startTime": 3, "endTime": 3.75},
+			},
+			wantStart: []float64{1.25, 3},
+			wantWords: []int{2, 0},
+		},
+		{
+			name:      "line timings only",
+			segments:  []map[string]interface{}{{"text": "one line", "startTime": 0.5, "endTime": 4}},
+			wantStart: []float64{0.5},
+			wantWords: []int{0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/v1/transcription/tr_1/sync" {
+					t.Errorf("path = %s", r.URL.Path)
+				}
+				writeData(t, w, map[string]interface{}{"transcriptionId": "tr_1", "segments": tt.segments, "duration": 4})
+			})
+
+			synced, err := client.Transcription.SyncLyrics(context.Background(), "tr_1", strings.NewReader("audio"), "song.wav")
+			if err != nil {
+				t.Fatalf("SyncLyrics: %v", err)
+			}
+			if len(synced.Segments) != len(tt.wantStart) {
+				t.Fatalf("segments = %+v, want %d", synced.Segments, len(tt.wantStart))
+			}
+			for i, segment := range synced.Segments {
+				if segment.StartTime != tt.wantStart[i] {
+					t.Errorf("segment %d start = %v, want %v", i, segment.StartTime, tt.wantStart[i])
+				}
+				if len(segment.Words) != tt.wantWords[i] {
+					t.Errorf("segment %d words = %d, want %d", i, len(segment.Words), tt.wantWords[i])
+				}
+			}
+			if words := synced.Segments[0].Words; len(words) > 1 && (words[1].StartTime != 1.9 || words[1].EndTime != 2.5) {
+				t.Errorf("word timing = %+v", words[1])
+			}
+		})
+	}
+}
+
+func TestTranslateLyrics(t *testing.T) {
+	tests := []struct {
+		name      string
+		languages []string
+		wantCalls int
+		wantErr   bool
+	}{
+		{"two languages", []string{"es", "fr"}, 1, false},
+		{"invalid code", []string{"es", "spanish"}, 0, true},
+		{"no languages", nil, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				calls++
+				writeData(t, w, map[string]interface{}{"translations": map[string]interface{}{
+					"es": map[string]interface{}{"language": "es", "text": "hola mundo"},
+					"fr": map[string]interface{}{"language": "fr", "text": "bonjour le monde", "segments": []map[string]interface{}{
+						{"text": "bonjour le monde", "startTime": 1, "endTime": 2},
+					}},
+				}})
+			})
+
+			translations, err := client.Transcription.TranslateLyrics(context.Background(), "tr_1", tt.languages, nil)
+			if calls != tt.wantCalls {
+				t.Errorf("requests = %d, want %d", calls, tt.wantCalls)
+			}
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("TranslateLyrics: %v", err)
+			}
+			if got := translations["es"].Text; got != "hola mundo" {
+				t.Errorf("es text = %q", got)
+			}
+			if fr := translations["fr"]; fr.Text != "bonjour le monde" || len(fr.Segments) != 1 {
+				t.Errorf("fr translation = %+v", fr)
+			}
+		})
+	}
+}
+
+func TestCreateFromURL(t *testing.T) {
+	tests := []struct {
+		name     string
+		audioURL string
+		options  *TranscriptionOptions
+		wantBody string
+		wantErr  bool
+	}{
+		{
+			name:     "https with options",
+			audioURL: "https://cdn.example.com/song.mp3",
+			options:  &TranscriptionOptions{Languages: []string{"en"}, IncludeTimestamps: true},
+			wantBody: `{"audioUrl":"https://cdn.example.com/song.mp3","includeTimestamps":true,"languages":["en"]}`,
+		},
+		{
+			name:     "plain http",
+			audioURL: "http://example.com/song.wav",
+			wantBody: `{"audioUrl":"http://example.com/song.wav"}`,
+		},
+		{name: "ftp scheme", audioURL: "ftp://example.com/song.wav", wantErr: true},
+		{name: "relative path", audioURL: "/song.wav", wantErr: true},
+		{name: "missing host", audioURL: "https:///song.wav", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body string
+			calls := 0
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				calls++
+				if r.Method != http.MethodPost || r.URL.Path != "/v1/transcription/create" {
+					t.Errorf("request = %s %s", r.Method, r.URL.Path)
+				}
+				data, _ := io.ReadAll(r.Body)
+				body = strings.TrimSpace(string(data))
+				writeData(t, w, map[string]interface{}{"id": "tr_1", "status": "pending"})
+			})
+
+			transcription, err := client.Transcription.CreateFromURL(context.Background(), tt.audioURL, tt.options)
+			if tt.wantErr {
+				if err == nil || calls != 0 {
+					t.Fatalf("err = %v after %d requests, want a rejection before sending", err, calls)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CreateFromURL: %v", err)
+			}
+			if body != tt.wantBody {
+				t.Errorf("body = %s, want %s", body, tt.wantBody)
+			}
+			if transcription.ID != "tr_1" {
+				t.Errorf("transcription = %+v", transcription)
+			}
+		})
+	}
+}
+
+func TestCreateBatchReportsPartialFailure(t *testing.T) {
+	var trackIDs []string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/transcription/batch" {
+			t.Errorf("path = %s", r.URL.Path)
+		}
+		var body struct {
+			TrackIDs []string `json:"trackIds"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("decoding body: %v", err)
+		}
+		trackIDs = body.TrackIDs
+		writeData(t, w, map[string]interface{}{
+			"jobs": []map[string]interface{}{
+				{"id": "tr_1", "trackId": "trk_1", "status": "pending"},
+				{"id": "tr_3", "trackId": "trk_3", "status": "pending"},
+			},
+			"failed": []map[string]interface{}{
+				{"id": "trk_2", "code": "NOT_FOUND", "message": "Track not found"},
+			},
+		})
+	}, WithoutQuotaCheck())
+
+	batch, err := client.Transcription.CreateBatch(context.Background(), []string{"trk_1", "trk_2", "trk_3"}, nil)
+	if err != nil {
+		t.Fatalf("CreateBatch: %v", err)
+	}
+	if strings.Join(trackIDs, ",") != "trk_1,trk_2,trk_3" {
+		t.Errorf("trackIds = %v", trackIDs)
+	}
+	if len(batch.Jobs) != 2 || batch.Jobs[1].TrackID != "trk_3" {
+		t.Errorf("jobs = %+v", batch.Jobs)
+	}
+	if !batch.HasFailures() || batch.Failed[0].ID != "trk_2" || batch.Failed[0].Code != "NOT_FOUND" {
+		t.Errorf("failed = %+v", batch.Failed)
+	}
+
+	if _, err := client.Transcription.CreateBatch(context.Background(), nil, nil); err == nil {
+		t.Error("empty batch accepted")
+	}
+}
+
+func TestFilterSegments(t *testing.T) {
+	transcription := &Transcription{Segments: []Segment{
+		{Text: "clear", Confidence: 0.95},
+		{Text: "mumbled", Confidence: 0.4},
+		{Text: "borderline", Confidence: 0.7},
+	}}
+
+	tests := []struct {
+		name          string
+		minConfidence float64
+		want          []string
+	}{
+		{"no threshold", 0, []string{"clear", "mumbled", "borderline"}},
+		{"threshold inclusive", 0.7, []string{"clear", "borderline"}},
+		{"high threshold", 0.9, []string{"clear"}},
+		{"above all", 0.99, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, segment := range transcription.FilterSegments(tt.minConfidence) {
+				got = append(got, segment.Text)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("FilterSegments(%v) = %v, want %v", tt.minConfidence, got, tt.want)
+			}
+
+			flagged := transcription.FlagSegments(tt.minConfidence)
+			if len(flagged) != len(transcription.Segments) {
+				t.Fatalf("FlagSegments returned %d segments, want %d", len(flagged), len(transcription.Segments))
+			}
+			for _, segment := range flagged {
+				if want := segment.Confidence < tt.minConfidence; segment.LowConfidence != want {
+					t.Errorf("segment %q LowConfidence = %v, want %v", segment.Text, segment.LowConfidence, want)
+				}
+			}
+		})
+	}
+
+	if transcription.Segments[1].LowConfidence {
+		t.Error("FlagSegments modified the transcription")
+	}
+}
+
+func TestDownloadWithConfidenceOptions(t *testing.T) {
+	tests := []struct {
+		name      string
+		options   TranscriptionDownloadOptions
+		wantQuery string
+		wantErr   bool
+	}{
+		{"no threshold", TranscriptionDownloadOptions{Format: "srt"}, "format=srt", false},
+		{"drop by default", TranscriptionDownloadOptions{Format: "srt", MinConfidence: 0.6}, "format=srt&lowConfidence=drop&minConfidence=0.6", false},
+		{"flag", TranscriptionDownloadOptions{Format: "vtt", MinConfidence: 0.5, LowConfidence: LowConfidenceFlag}, "format=vtt&lowConfidence=flag&minConfidence=0.5", false},
+		{"invalid mode", TranscriptionDownloadOptions{Format: "srt", MinConfidence: 0.5, LowConfidence: "hide"}, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var query string
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				query = r.URL.Query().Encode()
+				writeData(t, w, map[string]interface{}{"content": ""})
+			})
+
+			_, err := client.Transcription.DownloadWithOptions(context.Background(), "tr_1", tt.options)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("DownloadWithOptions: %v", err)
+			}
+			if query != tt.wantQuery {
+				t.Errorf("query = %q, want %q", query, tt.wantQuery)
+			}
+		})
+	}
+}
@@ -1,6 +1,11 @@
 package jewelmusic
 
-import "time"
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
 
 // Common types used across the SDK
 
@@ -16,6 +21,7 @@ type Track struct {
 	UploadedAt  time.Time         `json:"uploadedAt"`
 	ProcessedAt *time.Time        `json:"processedAt,omitempty"`
 	Metadata    map[string]string `json:"metadata,omitempty"`
+	Tags        []string          `json:"tags,omitempty"`
 	FileURL     string            `json:"fileUrl,omitempty"`
 }
 
@@ -28,26 +34,71 @@ type TrackMetadata struct {
 	ReleaseDate string            `json:"releaseDate,omitempty"`
 	Tags        []string          `json:"tags,omitempty"`
 	Custom      map[string]string `json:"custom,omitempty"`
+	// BPM, Key, and Mode are known musical properties. When set, analysis
+	// trusts them instead of detecting them unless ForceReanalyze is set.
+	BPM            float64 `json:"bpm,omitempty"`
+	Key            string  `json:"key,omitempty"`
+	Mode           string  `json:"mode,omitempty"`
+	ForceReanalyze bool    `json:"forceReanalyze,omitempty"`
+}
+
+// DownloadURL represents a temporary URL for downloading a track
+type DownloadURL struct {
+	URL       string    `json:"url"`
+	Format    string    `json:"format"`
+	Quality   string    `json:"quality"`
+	Size      int64     `json:"size,omitempty"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// Collection represents a named group of tracks such as an album or playlist
+type Collection struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Type        string    `json:"type"`
+	Description string    `json:"description,omitempty"`
+	TrackIDs    []string  `json:"trackIds"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+// Fingerprint represents an acoustic fingerprint of a track
+type Fingerprint struct {
+	TrackID     string  `json:"trackId,omitempty"`
+	Fingerprint string  `json:"fingerprint"`
+	Hash        string  `json:"hash"`
+	Algorithm   string  `json:"algorithm,omitempty"`
+	Duration    float64 `json:"duration,omitempty"`
+}
+
+// FingerprintMatch represents a track matching a fingerprint
+type FingerprintMatch struct {
+	TrackID    string  `json:"trackId"`
+	Title      string  `json:"title,omitempty"`
+	Artist     string  `json:"artist,omitempty"`
+	Similarity float64 `json:"similarity"`
+	Offset     float64 `json:"offset,omitempty"`
 }
 
 // Analysis represents audio analysis results
 type Analysis struct {
-	ID         string             `json:"id"`
-	TrackID    string             `json:"trackId"`
-	Status     string             `json:"status"`
-	Tempo      TempoAnalysis      `json:"tempo"`
-	Key        KeyAnalysis        `json:"key"`
-	Structure  StructureAnalysis  `json:"structure"`
-	Quality    QualityAnalysis    `json:"quality"`
-	CreatedAt  time.Time          `json:"createdAt"`
+	ID          string            `json:"id"`
+	TrackID     string            `json:"trackId"`
+	Status      string            `json:"status"`
+	Tempo       TempoAnalysis     `json:"tempo"`
+	Key         KeyAnalysis       `json:"key"`
+	Structure   StructureAnalysis `json:"structure"`
+	Quality     QualityAnalysis   `json:"quality"`
+	CreatedAt   time.Time         `json:"createdAt"`
 	CompletedAt *time.Time        `json:"completedAt,omitempty"`
+	PollAfter   float64           `json:"pollAfter,omitempty"`
 }
 
 // TempoAnalysis represents tempo analysis
 type TempoAnalysis struct {
-	BPM        float64 `json:"bpm"`
-	Confidence float64 `json:"confidence"`
-	TimeSignature string `json:"timeSignature,omitempty"`
+	BPM           float64 `json:"bpm"`
+	Confidence    float64 `json:"confidence"`
+	TimeSignature string  `json:"timeSignature,omitempty"`
 }
 
 // KeyAnalysis represents key analysis
@@ -78,62 +129,227 @@ type QualityAnalysis struct {
 	Issues       []string           `json:"issues,omitempty"`
 }
 
+// StemJob represents a stem separation job. StemURLs maps each stem name to
+// its download URL once the job has completed.
+type StemJob struct {
+	ID          string            `json:"id"`
+	TrackID     string            `json:"trackId"`
+	Status      string            `json:"status"`
+	Stems       []string          `json:"stems"`
+	StemURLs    map[string]string `json:"stemUrls,omitempty"`
+	CreatedAt   time.Time         `json:"createdAt"`
+	CompletedAt *time.Time        `json:"completedAt,omitempty"`
+	PollAfter   float64           `json:"pollAfter,omitempty"`
+}
+
+// GenreScore represents a genre and the confidence that a track belongs to it
+type GenreScore struct {
+	Genre      string  `json:"genre"`
+	Confidence float64 `json:"confidence"`
+}
+
+// MoodReport represents the mood and energy profile of a track. Valence,
+// Energy, and Danceability range from 0 to 1.
+type MoodReport struct {
+	TrackID      string   `json:"trackId"`
+	Valence      float64  `json:"valence"`
+	Energy       float64  `json:"energy"`
+	Danceability float64  `json:"danceability"`
+	Moods        []string `json:"moods"`
+}
+
+// ExplicitReport represents the result of explicit content detection
+type ExplicitReport struct {
+	TrackID    string            `json:"trackId"`
+	Explicit   bool              `json:"explicit"`
+	Confidence float64           `json:"confidence"`
+	Flagged    []ExplicitSegment `json:"flagged,omitempty"`
+}
+
+// ExplicitSegment represents a passage flagged as explicit
+type ExplicitSegment struct {
+	StartTime  float64 `json:"startTime"`
+	EndTime    float64 `json:"endTime"`
+	Text       string  `json:"text,omitempty"`
+	Confidence float64 `json:"confidence"`
+}
+
+// MasteringPreset describes a mastering preset and its loudness target
+type MasteringPreset struct {
+	Name           string  `json:"name"`
+	Description    string  `json:"description"`
+	TargetLoudness float64 `json:"targetLoudness"`
+	TargetPlatform string  `json:"targetPlatform,omitempty"`
+}
+
+// CostEstimate is the expected cost of a generation or batch operation
+type CostEstimate struct {
+	Credits  float64 `json:"credits"`
+	Price    float64 `json:"price"`
+	Currency string  `json:"currency"`
+}
+
+// Template represents a song template. Duration is in seconds.
+type Template struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	Genre       string   `json:"genre"`
+	Style       string   `json:"style"`
+	Mood        string   `json:"mood"`
+	Duration    int      `json:"duration"`
+	Structure   []string `json:"structure,omitempty"`
+}
+
+// Templates represents a list of song templates
+type Templates struct {
+	Items []Template `json:"items"`
+}
+
 // Generation represents AI-generated content
 type Generation struct {
-	ID         string                 `json:"id"`
-	Type       string                 `json:"type"`
-	Status     string                 `json:"status"`
-	Parameters map[string]interface{} `json:"parameters"`
-	Result     interface{}            `json:"result,omitempty"`
-	CreatedAt  time.Time             `json:"createdAt"`
-	CompletedAt *time.Time            `json:"completedAt,omitempty"`
-	PreviewURL string                 `json:"previewUrl,omitempty"`
-	DownloadURL string                `json:"downloadUrl,omitempty"`
+	ID          string                 `json:"id"`
+	Type        string                 `json:"type"`
+	Status      string                 `json:"status"`
+	Parameters  map[string]interface{} `json:"parameters"`
+	Result      interface{}            `json:"result,omitempty"`
+	CreatedAt   time.Time              `json:"createdAt"`
+	CompletedAt *time.Time             `json:"completedAt,omitempty"`
+	PreviewURL  string                 `json:"previewUrl,omitempty"`
+	DownloadURL string                 `json:"downloadUrl,omitempty"`
+	PollAfter   float64                `json:"pollAfter,omitempty"`
+	// Progress is the completed fraction between 0 and 1 and Stage the
+	// current rendering stage, while the generation is running
+	Progress float64 `json:"progress,omitempty"`
+	Stage    string  `json:"stage,omitempty"`
 }
 
 // Release represents a music release
 type Release struct {
-	ID          string      `json:"id"`
-	Type        string      `json:"type"`
-	Title       string      `json:"title"`
-	Artist      string      `json:"artist"`
-	ReleaseDate string      `json:"releaseDate"`
-	Status      string      `json:"status"`
+	ID          string         `json:"id"`
+	Type        string         `json:"type"`
+	Title       string         `json:"title"`
+	Artist      string         `json:"artist"`
+	ReleaseDate string         `json:"releaseDate"`
+	Status      string         `json:"status"`
 	Tracks      []ReleaseTrack `json:"tracks"`
-	Platforms   []string    `json:"platforms"`
-	Territories []string    `json:"territories"`
-	CreatedAt   time.Time   `json:"createdAt"`
+	Platforms   []string       `json:"platforms"`
+	Territories []string       `json:"territories"`
+	CreatedAt   time.Time      `json:"createdAt"`
 }
 
 // ReleaseTrack represents a track in a release
 type ReleaseTrack struct {
-	TrackID   string `json:"trackId"`
-	Title     string `json:"title"`
-	Duration  int    `json:"duration"`
-	ISRC      string `json:"isrc,omitempty"`
-	Position  int    `json:"position"`
+	TrackID  string `json:"trackId"`
+	Title    string `json:"title"`
+	Duration int    `json:"duration"`
+	ISRC     string `json:"isrc,omitempty"`
+	Position int    `json:"position"`
+}
+
+// DistributionStatus represents the distribution status of a release across
+// platforms. Errors lists the platforms that failed or rejected the release.
+type DistributionStatus struct {
+	ReleaseID string           `json:"releaseId"`
+	Status    string           `json:"status"`
+	Platforms []PlatformStatus `json:"platforms"`
+	Errors    []PlatformError  `json:"errors,omitempty"`
+}
+
+// PlatformStatus represents the distribution status of a release on one
+// platform
+type PlatformStatus struct {
+	Platform  string `json:"platform"`
+	Status    string `json:"status"`
+	LiveDate  string `json:"liveDate,omitempty"`
+	StoreURL  string `json:"storeUrl,omitempty"`
+	ErrorCode string `json:"errorCode,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// PlatformError describes why a platform failed or rejected a release
+type PlatformError struct {
+	Platform string `json:"platform"`
+	Code     string `json:"code"`
+	Message  string `json:"message"`
 }
 
 // Transcription represents AI transcription results
 type Transcription struct {
-	ID          string      `json:"id"`
-	TrackID     string      `json:"trackId,omitempty"`
-	Status      string      `json:"status"`
-	Language    string      `json:"language"`
-	Text        string      `json:"text"`
-	Segments    []Segment   `json:"segments,omitempty"`
-	Confidence  float64     `json:"confidence"`
-	CreatedAt   time.Time   `json:"createdAt"`
-	CompletedAt *time.Time  `json:"completedAt,omitempty"`
+	ID          string     `json:"id"`
+	TrackID     string     `json:"trackId,omitempty"`
+	Status      string     `json:"status"`
+	Language    string     `json:"language"`
+	Text        string     `json:"text"`
+	Segments    []Segment  `json:"segments,omitempty"`
+	Confidence  float64    `json:"confidence"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	CompletedAt *time.Time `json:"completedAt,omitempty"`
 }
 
 // Segment represents a transcription segment
 type Segment struct {
-	Text      string  `json:"text"`
-	StartTime float64 `json:"startTime"`
-	EndTime   float64 `json:"endTime"`
-	Confidence float64 `json:"confidence"`
-	Speaker   string  `json:"speaker,omitempty"`
+	Text          string  `json:"text"`
+	StartTime     float64 `json:"startTime"`
+	EndTime       float64 `json:"endTime"`
+	Confidence    float64 `json:"confidence"`
+	Speaker       string  `json:"speaker,omitempty"`
+	Words         []Word  `json:"words,omitempty"`
+	LowConfidence bool    `json:"lowConfidence,omitempty"`
+}
+
+// FilterSegments returns the segments whose confidence is at least minConfidence
+func (t *Transcription) FilterSegments(minConfidence float64) []Segment {
+	filtered := make([]Segment, 0, len(t.Segments))
+	for _, segment := range t.Segments {
+		if segment.Confidence >= minConfidence {
+			filtered = append(filtered, segment)
+		}
+	}
+	return filtered
+}
+
+// FlagSegments returns a copy of the segments with LowConfidence set on those
+// whose confidence is below minConfidence
+func (t *Transcription) FlagSegments(minConfidence float64) []Segment {
+	flagged := make([]Segment, len(t.Segments))
+	for i, segment := range t.Segments {
+		segment.LowConfidence = segment.Confidence < minConfidence
+		flagged[i] = segment
+	}
+	return flagged
+}
+
+// Word represents a word-level timing within a segment
+type Word struct {
+	Text       string  `json:"text"`
+	StartTime  float64 `json:"startTime"`
+	EndTime    float64 `json:"endTime"`
+	Confidence float64 `json:"confidence,omitempty"`
+}
+
+// SyncedLyrics represents lyrics aligned to audio
+type SyncedLyrics struct {
+	TranscriptionID string    `json:"transcriptionId"`
+	Segments        []Segment `json:"segments"`
+	Duration        float64   `json:"duration,omitempty"`
+}
+
+// Translation represents lyrics translated into a single language
+type Translation struct {
+	Language string    `json:"language"`
+	Text     string    `json:"text"`
+	Segments []Segment `json:"segments,omitempty"`
+}
+
+// TranscriptionStatus represents the progress of a transcription job
+type TranscriptionStatus struct {
+	Status   string  `json:"status"`
+	Progress float64 `json:"progress"`
+	Stage    string  `json:"stage,omitempty"`
+	ETA      int     `json:"eta,omitempty"`
+	// PollAfter is the server-suggested delay in seconds before polling again
+	PollAfter float64 `json:"pollAfter,omitempty"`
 }
 
 // UserProfile represents user profile information
@@ -142,9 +358,36 @@ type UserProfile struct {
 	Name         string       `json:"name"`
 	Email        string       `json:"email"`
 	Subscription Subscription `json:"subscription"`
+	SocialLinks  SocialLinks  `json:"socialLinks"`
+	ArtistInfo   *ArtistInfo  `json:"artistInfo,omitempty"`
 	CreatedAt    time.Time    `json:"createdAt"`
 }
 
+// SocialLinks represents the social and streaming profiles linked to a user
+type SocialLinks struct {
+	Website    string `json:"website,omitempty"`
+	Spotify    string `json:"spotify,omitempty"`
+	AppleMusic string `json:"appleMusic,omitempty"`
+	YouTube    string `json:"youtube,omitempty"`
+	SoundCloud string `json:"soundcloud,omitempty"`
+	Instagram  string `json:"instagram,omitempty"`
+	TikTok     string `json:"tiktok,omitempty"`
+	Twitter    string `json:"twitter,omitempty"`
+	Facebook   string `json:"facebook,omitempty"`
+}
+
+// ArtistInfo represents artist details attached to a user profile
+type ArtistInfo struct {
+	ArtistName string   `json:"artistName"`
+	RealName   string   `json:"realName,omitempty"`
+	Genres     []string `json:"genres,omitempty"`
+	Label      string   `json:"label,omitempty"`
+	Country    string   `json:"country,omitempty"`
+	ISNI       string   `json:"isni,omitempty"`
+	IPI        string   `json:"ipi,omitempty"`
+	Verified   bool     `json:"verified"`
+}
+
 // Subscription represents user subscription information
 type Subscription struct {
 	Plan            string    `json:"plan"`
@@ -153,11 +396,36 @@ type Subscription struct {
 	Features        []string  `json:"features"`
 }
 
+// Billing represents a user's billing information
+type Billing struct {
+	PaymentMethod   *PaymentMethod `json:"paymentMethod,omitempty"`
+	NextInvoiceDate *time.Time     `json:"nextInvoiceDate,omitempty"`
+	Invoices        []Invoice      `json:"invoices,omitempty"`
+}
+
+// PaymentMethod summarizes the payment method on file
+type PaymentMethod struct {
+	Type     string `json:"type"`
+	Brand    string `json:"brand,omitempty"`
+	Last4    string `json:"last4,omitempty"`
+	ExpMonth int    `json:"expMonth,omitempty"`
+	ExpYear  int    `json:"expYear,omitempty"`
+}
+
+// Invoice represents a billing invoice
+type Invoice struct {
+	ID       string    `json:"id"`
+	Amount   float64   `json:"amount"`
+	Currency string    `json:"currency"`
+	Status   string    `json:"status"`
+	DueDate  time.Time `json:"dueDate"`
+}
+
 // AnalyticsData represents analytics data
 type AnalyticsData struct {
-	Summary    AnalyticsSummary  `json:"summary"`
-	Data       []AnalyticsPoint  `json:"data"`
-	Pagination PaginationInfo    `json:"pagination"`
+	Summary    AnalyticsSummary `json:"summary"`
+	Data       []AnalyticsPoint `json:"data"`
+	Pagination PaginationInfo   `json:"pagination"`
 }
 
 // AnalyticsSummary represents analytics summary
@@ -170,22 +438,40 @@ type AnalyticsSummary struct {
 
 // AnalyticsPoint represents a data point in analytics
 type AnalyticsPoint struct {
-	Date     string            `json:"date"`
-	Metrics  map[string]int64  `json:"metrics"`
-	Revenue  float64           `json:"revenue,omitempty"`
-	Platform string            `json:"platform,omitempty"`
+	Date     string           `json:"date"`
+	Metrics  map[string]int64 `json:"metrics"`
+	Revenue  float64          `json:"revenue,omitempty"`
+	Platform string           `json:"platform,omitempty"`
 }
 
 // Webhook represents a webhook configuration
 type Webhook struct {
-	ID          string   `json:"id"`
-	URL         string   `json:"url"`
-	Events      []string `json:"events"`
-	Secret      string   `json:"secret,omitempty"`
-	Active      bool     `json:"active"`
-	Description string   `json:"description,omitempty"`
-	CreatedAt   time.Time `json:"createdAt"`
-	UpdatedAt   time.Time `json:"updatedAt"`
+	ID          string            `json:"id"`
+	URL         string            `json:"url"`
+	Events      []string          `json:"events"`
+	Secret      string            `json:"secret,omitempty"`
+	Active      bool              `json:"active"`
+	Description string            `json:"description,omitempty"`
+	Headers     map[string]string `json:"headers,omitempty"`
+	Timeout     int               `json:"timeout,omitempty"`
+	RetryPolicy *RetryPolicy      `json:"retryPolicy,omitempty"`
+	CreatedAt   time.Time         `json:"createdAt"`
+	UpdatedAt   time.Time         `json:"updatedAt"`
+}
+
+// WebhookDelivery represents one attempt to deliver an event to a webhook
+type WebhookDelivery struct {
+	ID          string     `json:"id"`
+	WebhookID   string     `json:"webhookId"`
+	EventID     string     `json:"eventId"`
+	EventType   string     `json:"eventType"`
+	Status      string     `json:"status"`
+	StatusCode  int        `json:"statusCode,omitempty"`
+	Attempts    int        `json:"attempts"`
+	Error       string     `json:"error,omitempty"`
+	LatencyMs   int        `json:"latencyMs,omitempty"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	NextRetryAt *time.Time `json:"nextRetryAt,omitempty"`
 }
 
 // WebhookEvent represents a webhook event
@@ -196,16 +482,117 @@ type WebhookEvent struct {
 	Timestamp time.Time              `json:"timestamp"`
 }
 
+// BatchError describes a batch item that could not be processed
+type BatchError struct {
+	ID      string `json:"id"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// Error implements the error interface
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("%s: %s (%s)", e.ID, e.Message, e.Code)
+}
+
 // PaginationInfo represents pagination information
 type PaginationInfo struct {
-	Page        int `json:"page"`
-	PerPage     int `json:"perPage"`
-	Total       int `json:"total"`
-	TotalPages  int `json:"totalPages"`
+	Page       int `json:"page"`
+	PerPage    int `json:"perPage"`
+	Total      int `json:"total"`
+	TotalPages int `json:"totalPages"`
 }
 
 // ListResponse represents a paginated list response
 type ListResponse struct {
 	Items      interface{}    `json:"items"`
 	Pagination PaginationInfo `json:"pagination"`
-}
\ No newline at end of file
+}
+
+// Pagination defaults applied by list methods
+const (
+	DefaultPerPage = 20
+	MaxPerPage     = 100
+)
+
+// pageParams returns the page and perPage query parameters, defaulting page
+// to 1 and perPage to DefaultPerPage when not positive and capping perPage at
+// MaxPerPage
+func pageParams(page, perPage int) map[string]string {
+	if page <= 0 {
+		page = 1
+	}
+	if perPage <= 0 {
+		perPage = DefaultPerPage
+	}
+	if perPage > MaxPerPage {
+		perPage = MaxPerPage
+	}
+	return map[string]string{
+		"page":    strconv.Itoa(page),
+		"perPage": strconv.Itoa(perPage),
+	}
+}
+
+// Priority is the queue priority of a submitted job
+type Priority string
+
+// Queue priorities accepted by batch processing, analysis, and generation
+const (
+	PriorityLow    Priority = "low"
+	PriorityNormal Priority = "normal"
+	PriorityHigh   Priority = "high"
+)
+
+// validate checks that p is empty or one of the known priorities
+func (p Priority) validate() error {
+	switch p {
+	case "", PriorityLow, PriorityNormal, PriorityHigh:
+		return nil
+	}
+	return &APIError{Code: "INVALID_REQUEST", Message: "Unsupported priority: " + string(p)}
+}
+
+// ListOptions represents common pagination, sorting, and filtering options
+// for list methods. Filter entries are sent as query parameters.
+type ListOptions struct {
+	Page    int
+	PerPage int
+	Sort    string
+	Filter  map[string]string
+}
+
+// params converts the options into query parameters
+func (o ListOptions) params() map[string]string {
+	params := pageParams(o.Page, o.PerPage)
+	for k, v := range o.Filter {
+		if _, ok := params[k]; !ok {
+			params[k] = v
+		}
+	}
+	if o.Sort != "" {
+		params["sort"] = o.Sort
+	}
+	return params
+}
+
+// TrackList represents a page of tracks
+type TrackList struct {
+	Items      []Track        `json:"items"`
+	Pagination PaginationInfo `json:"pagination"`
+}
+
+// DecodeItems decodes the untyped list items into v, which should be a
+// pointer to a slice of the expected item type
+func (l *ListResponse) DecodeItems(v interface{}) error {
+	if l.Items == nil {
+		return nil
+	}
+	data, err := json.Marshal(l.Items)
+	if err != nil {
+		return fmt.Errorf("failed to marshal list items: %w", err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("failed to unmarshal list items: %w", err)
+	}
+	return nil
+}
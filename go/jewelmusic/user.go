@@ -4,6 +4,7 @@ import (
 	"context"
 	"io"
 	"strconv"
+	"time"
 )
 
 // UserResource manages user profile, preferences, and account settings
@@ -21,6 +22,13 @@ type ProfileUpdate struct {
 	ArtistInfo  map[string]interface{} `json:"artistInfo,omitempty"`
 }
 
+// AvatarResult represents an uploaded avatar image
+type AvatarResult struct {
+	URL    string `json:"url"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+}
+
 // Preferences represents user preferences
 type Preferences struct {
 	Notifications map[string]bool        `json:"notifications,omitempty"`
@@ -51,12 +59,12 @@ type APIKeyUpdate struct {
 
 // UsageStatsOptions represents options for usage statistics
 type UsageStatsOptions struct {
-	Period            string `json:"period,omitempty"`
-	StartDate         string `json:"startDate,omitempty"`
-	EndDate           string `json:"endDate,omitempty"`
-	GroupBy           string `json:"groupBy,omitempty"`
-	IncludeBreakdown  bool   `json:"includeBreakdown,omitempty"`
-	APIKeyID          string `json:"apiKeyId,omitempty"`
+	Period           string `json:"period,omitempty"`
+	StartDate        string `json:"startDate,omitempty"`
+	EndDate          string `json:"endDate,omitempty"`
+	GroupBy          string `json:"groupBy,omitempty"`
+	IncludeBreakdown bool   `json:"includeBreakdown,omitempty"`
+	APIKeyID         string `json:"apiKeyId,omitempty"`
 }
 
 // BillingOptions represents options for billing queries
@@ -83,6 +91,18 @@ type ExportDataOptions struct {
 	Email            string `json:"email,omitempty"`
 }
 
+// ExportJob represents the status of a user data export
+type ExportJob struct {
+	ID          string     `json:"id"`
+	Status      string     `json:"status"`
+	Progress    float64    `json:"progress"`
+	DownloadURL string     `json:"downloadUrl,omitempty"`
+	ExpiresAt   *time.Time `json:"expiresAt,omitempty"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	CompletedAt *time.Time `json:"completedAt,omitempty"`
+	PollAfter   float64    `json:"pollAfter,omitempty"`
+}
+
 // GetProfile gets user profile information
 func (u *UserResource) GetProfile(ctx context.Context) (*UserProfile, error) {
 	var result UserProfile
@@ -98,22 +118,24 @@ func (u *UserResource) UpdateProfile(ctx context.Context, updates ProfileUpdate)
 }
 
 // UploadAvatar uploads user avatar image
-func (u *UserResource) UploadAvatar(ctx context.Context, avatarFile io.Reader, filename string) (map[string]interface{}, error) {
+func (u *UserResource) UploadAvatar(ctx context.Context, avatarFile io.Reader, filename string) (*AvatarResult, error) {
 	resp, err := u.client.UploadFile(ctx, "/user/avatar", avatarFile, filename, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	result := make(map[string]interface{})
-	// Convert response data to map
-	return result, nil
+	var result AvatarResult
+	if err := resp.DecodeData(&result); err != nil {
+		return nil, err
+	}
+	return &result, nil
 }
 
 // GetPreferences gets user preferences and settings
-func (u *UserResource) GetPreferences(ctx context.Context) (map[string]interface{}, error) {
-	var result map[string]interface{}
+func (u *UserResource) GetPreferences(ctx context.Context) (*Preferences, error) {
+	var result Preferences
 	err := u.client.Get(ctx, "/user/preferences", nil, &result)
-	return result, err
+	return &result, err
 }
 
 // UpdatePreferences updates user preferences and settings
@@ -133,12 +155,12 @@ func (u *UserResource) GetAPIKeys(ctx context.Context) ([]map[string]interface{}
 // CreateAPIKey creates a new API key
 func (u *UserResource) CreateAPIKey(ctx context.Context, name string, permissions APIKeyPermissions) (map[string]interface{}, error) {
 	requestData := map[string]interface{}{
-		"name":        name,
-		"scopes":      permissions.Scopes,
-		"rateLimit":   permissions.RateLimit,
+		"name":           name,
+		"scopes":         permissions.Scopes,
+		"rateLimit":      permissions.RateLimit,
 		"ipRestrictions": permissions.IPRestrictions,
-		"expiresAt":   permissions.ExpiresAt,
-		"description": permissions.Description,
+		"expiresAt":      permissions.ExpiresAt,
+		"description":    permissions.Description,
 	}
 
 	var result map[string]interface{}
@@ -160,10 +182,53 @@ func (u *UserResource) RevokeAPIKey(ctx context.Context, keyID string) (map[stri
 	return result, err
 }
 
+// RevokeAllAPIKeys revokes every API key of the account, for example after a
+// security incident, with at most DefaultBatchConcurrency revocations in
+// flight. If exceptCurrent is set, the key the client authenticates with is
+// kept; when the API does not identify that key, nothing is revoked and an
+// error is returned. Each result's ID is the revoked key's ID.
+func (u *UserResource) RevokeAllAPIKeys(ctx context.Context, exceptCurrent bool) ([]BatchResult[map[string]interface{}], error) {
+	keys, err := u.GetAPIKeys(detachCallOptions(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	var keyIDs []string
+	foundCurrent := false
+	for _, key := range keys {
+		id, _ := key["id"].(string)
+		if id == "" {
+			continue
+		}
+		if exceptCurrent && u.isCurrentAPIKey(key) {
+			foundCurrent = true
+			continue
+		}
+		keyIDs = append(keyIDs, id)
+	}
+	if exceptCurrent && !foundCurrent {
+		return nil, &APIError{Code: "INVALID_REQUEST", Message: "Cannot identify the current API key; no keys were revoked"}
+	}
+
+	return runBatch(ctx, keyIDs, DefaultBatchConcurrency, u.RevokeAPIKey), nil
+}
+
+// isCurrentAPIKey reports whether key describes the API key the client
+// authenticates with, either because the API flags it as current or because
+// its ID or full key value is exactly the client's key
+func (u *UserResource) isCurrentAPIKey(key map[string]interface{}) bool {
+	if current, ok := key["current"].(bool); ok {
+		return current
+	}
+	id, _ := key["id"].(string)
+	value, _ := key["key"].(string)
+	return id == u.client.apiKey || value == u.client.apiKey
+}
+
 // GetUsageStats gets detailed API usage statistics
 func (u *UserResource) GetUsageStats(ctx context.Context, options *UsageStatsOptions) (map[string]interface{}, error) {
 	params := make(map[string]string)
-	
+
 	if options != nil {
 		if options.Period != "" {
 			params["period"] = options.Period
@@ -191,9 +256,9 @@ func (u *UserResource) GetUsageStats(ctx context.Context, options *UsageStatsOpt
 }
 
 // GetBilling gets billing information and invoices
-func (u *UserResource) GetBilling(ctx context.Context, options *BillingOptions) (map[string]interface{}, error) {
+func (u *UserResource) GetBilling(ctx context.Context, options *BillingOptions) (*Billing, error) {
 	params := make(map[string]string)
-	
+
 	if options != nil {
 		if options.IncludeInvoices {
 			params["includeInvoices"] = "true"
@@ -206,9 +271,9 @@ func (u *UserResource) GetBilling(ctx context.Context, options *BillingOptions)
 		}
 	}
 
-	var result map[string]interface{}
+	var result Billing
 	err := u.client.Get(ctx, "/user/billing", params, &result)
-	return result, err
+	return &result, err
 }
 
 // UpdateBilling updates billing information
@@ -252,7 +317,7 @@ func (u *UserResource) DeleteAccount(ctx context.Context, confirmEmail string, r
 // ExportData exports user data
 func (u *UserResource) ExportData(ctx context.Context, options *ExportDataOptions) (map[string]interface{}, error) {
 	requestData := make(map[string]interface{})
-	
+
 	if options != nil {
 		if options.Format != "" {
 			requestData["format"] = options.Format
@@ -274,4 +339,26 @@ func (u *UserResource) ExportData(ctx context.Context, options *ExportDataOption
 	var result map[string]interface{}
 	err := u.client.Post(ctx, "/user/export", requestData, &result)
 	return result, err
-}
\ No newline at end of file
+}
+
+// GetExportStatus gets the status of a user data export started by ExportData
+func (u *UserResource) GetExportStatus(ctx context.Context, exportID string) (*ExportJob, error) {
+	var result ExportJob
+	err := u.client.Get(ctx, "/user/export/"+exportID, nil, &result)
+	return &result, err
+}
+
+// WaitForExport polls a user data export until it reaches a terminal status
+func (u *UserResource) WaitForExport(ctx context.Context, exportID string, options *PollOptions) (*ExportJob, error) {
+	return Poll(ctx, func() (*ExportJob, error) {
+		return u.GetExportStatus(detachCallOptions(ctx), exportID)
+	}, func(job *ExportJob) bool {
+		return IsTerminalStatus(job.Status)
+	}, u.client.pollOptions(options))
+}
+
+// DownloadExport streams a completed user data export. The caller must
+// close the returned reader.
+func (u *UserResource) DownloadExport(ctx context.Context, exportID string) (io.ReadCloser, error) {
+	return u.client.openStream(ctx, "/user/export/"+exportID+"/download")
+}
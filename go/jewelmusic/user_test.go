@@ -0,0 +1,417 @@
+package jewelmusic
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRevokeAllAPIKeys(t *testing.T) {
+	tests := []struct {
+		name          string
+		keys          []map[string]interface{}
+		exceptCurrent bool
+		wantRevoked   []string
+		wantErr       bool
+	}{
+		{
+			name: "all keys",
+			keys: []map[string]interface{}{
+				{"id": "key_1", "prefix": "jml_live_"},
+				{"id": "key_2", "prefix": "jml_live_"},
+			},
+			wantRevoked: []string{"key_1", "key_2"},
+		},
+		{
+			name: "all but current flag",
+			keys: []map[string]interface{}{
+				{"id": "key_1", "prefix": "jml_live_", "current": true},
+				{"id": "key_2", "prefix": "jml_live_", "current": false},
+				{"id": "key_3", "prefix": "jml_live_", "current": false},
+			},
+			exceptCurrent: true,
+			wantRevoked:   []string{"key_2", "key_3"},
+		},
+		{
+			name: "all but exact key match",
+			keys: []map[string]interface{}{
+				{"id": "key_1", "key": "jml_live_test"},
+				{"id": "key_2", "key": "jml_live_other"},
+			},
+			exceptCurrent: true,
+			wantRevoked:   []string{"key_2"},
+		},
+		{
+			name: "shared prefix is not current",
+			keys: []map[string]interface{}{
+				{"id": "key_1", "prefix": "jml_live_"},
+				{"id": "key_2", "prefix": "jml_live_"},
+			},
+			exceptCurrent: true,
+			wantErr:       true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			var revoked []string
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				switch r.Method {
+				case "GET":
+					writeData(t, w, tt.keys)
+				case "DELETE":
+					mu.Lock()
+					revoked = append(revoked, strings.TrimPrefix(r.URL.Path, "/v1/user/api-keys/"))
+					mu.Unlock()
+					writeData(t, w, map[string]interface{}{"revoked": true})
+				}
+			})
+
+			results, err := client.User.RevokeAllAPIKeys(context.Background(), tt.exceptCurrent)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				if len(revoked) != 0 {
+					t.Fatalf("revoked %v, want none", revoked)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			for _, result := range results {
+				if result.Err != nil {
+					t.Errorf("revoking %s: %v", result.ID, result.Err)
+				}
+			}
+			sort.Strings(revoked)
+			if strings.Join(revoked, ",") != strings.Join(tt.wantRevoked, ",") {
+				t.Errorf("revoked %v, want %v", revoked, tt.wantRevoked)
+			}
+		})
+	}
+}
+
+func TestGetProfile(t *testing.T) {
+	tests := []struct {
+		name        string
+		response    string
+		wantSocials SocialLinks
+		wantArtist  *ArtistInfo
+	}{
+		{
+			name: "socials and artist info",
+			response: `{
+				"id": "usr_1",
+				"name": "Ada",
+				"email": "ada@example.com",
+				"socialLinks": {
+					"website": "https://ada.example.com",
+					"spotify": "https://open.spotify.com/artist/1",
+					"appleMusic": "https://music.apple.com/artist/1",
+					"youtube": "https://youtube.com/@ada",
+					"soundcloud": "https://soundcloud.com/ada",
+					"tiktok": "https://tiktok.com/@ada"
+				},
+				"artistInfo": {
+					"artistName": "ADA",
+					"realName": "Ada Lovelace",
+					"genres": ["electronic", "ambient"],
+					"label": "Analytical Records",
+					"country": "GB",
+					"isni": "0000000121032683",
+					"ipi": "00123456789",
+					"verified": true
+				}
+			}`,
+			wantSocials: SocialLinks{
+				Website:    "https://ada.example.com",
+				Spotify:    "https://open.spotify.com/artist/1",
+				AppleMusic: "https://music.apple.com/artist/1",
+				YouTube:    "https://youtube.com/@ada",
+				SoundCloud: "https://soundcloud.com/ada",
+				TikTok:     "https://tiktok.com/@ada",
+			},
+			wantArtist: &ArtistInfo{
+				ArtistName: "ADA",
+				RealName:   "Ada Lovelace",
+				Genres:     []string{"electronic", "ambient"},
+				Label:      "Analytical Records",
+				Country:    "GB",
+				ISNI:       "0000000121032683",
+				IPI:        "00123456789",
+				Verified:   true,
+			},
+		},
+		{
+			name:     "listener without artist info",
+			response: `{"id": "usr_2", "name": "Bob", "socialLinks": {"instagram": "https://instagram.com/bob"}}`,
+			wantSocials: SocialLinks{
+				Instagram: "https://instagram.com/bob",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != "GET" || r.URL.Path != "/v1/user/profile" {
+					t.Errorf("got %s %s, want GET /v1/user/profile", r.Method, r.URL.Path)
+				}
+				writeData(t, w, json.RawMessage(tt.response))
+			})
+
+			profile, err := client.User.GetProfile(context.Background())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if profile.SocialLinks != tt.wantSocials {
+				t.Errorf("social links = %+v, want %+v", profile.SocialLinks, tt.wantSocials)
+			}
+			if tt.wantArtist == nil {
+				if profile.ArtistInfo != nil {
+					t.Errorf("artist info = %+v, want nil", profile.ArtistInfo)
+				}
+				return
+			}
+			if profile.ArtistInfo == nil {
+				t.Fatal("artist info is nil")
+			}
+			if !reflect.DeepEqual(profile.ArtistInfo, tt.wantArtist) {
+				t.Errorf("artist info = %+v, want %+v", profile.ArtistInfo, tt.wantArtist)
+			}
+		})
+	}
+}
+
+func TestUploadAvatar(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.Path != "/v1/user/avatar" {
+			t.Errorf("got %s %s, want POST /v1/user/avatar", r.Method, r.URL.Path)
+		}
+		_, header, err := r.FormFile("file")
+		if err != nil {
+			t.Errorf("reading file part: %v", err)
+			return
+		}
+		if header.Filename != "avatar.png" {
+			t.Errorf("filename = %q, want %q", header.Filename, "avatar.png")
+		}
+		writeData(t, w, json.RawMessage(`{"url": "https://cdn.jewelmusic.art/avatars/usr_1.png", "width": 512, "height": 256}`))
+	})
+
+	result, err := client.User.UploadAvatar(context.Background(), strings.NewReader("png"), "avatar.png")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := AvatarResult{URL: "https://cdn.jewelmusic.art/avatars/usr_1.png", Width: 512, Height: 256}
+	if *result != want {
+		t.Errorf("result = %+v, want %+v", *result, want)
+	}
+}
+
+func TestPreferencesRoundTrip(t *testing.T) {
+	tests := []struct {
+		name  string
+		prefs Preferences
+	}{
+		{
+			name: "all sections",
+			prefs: Preferences{
+				Notifications: map[string]bool{"email": true, "push": false},
+				UI:            map[string]string{"theme": "dark", "language": "en"},
+				Privacy:       map[string]interface{}{"profileVisibility": "public", "showStats": true},
+				API:           map[string]int{"defaultPageSize": 50},
+			},
+		},
+		{
+			name:  "notifications only",
+			prefs: Preferences{Notifications: map[string]bool{"marketing": false}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stored json.RawMessage
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/v1/user/preferences" {
+					t.Errorf("path = %q, want /v1/user/preferences", r.URL.Path)
+				}
+				switch r.Method {
+				case "PUT":
+					if err := json.NewDecoder(r.Body).Decode(&stored); err != nil {
+						t.Errorf("decoding request: %v", err)
+					}
+					writeData(t, w, map[string]interface{}{"updated": true})
+				case "GET":
+					writeData(t, w, stored)
+				}
+			})
+
+			if _, err := client.User.UpdatePreferences(context.Background(), tt.prefs); err != nil {
+				t.Fatalf("updating preferences: %v", err)
+			}
+			got, err := client.User.GetPreferences(context.Background())
+			if err != nil {
+				t.Fatalf("getting preferences: %v", err)
+			}
+			if !reflect.DeepEqual(*got, tt.prefs) {
+				t.Errorf("preferences = %+v, want %+v", *got, tt.prefs)
+			}
+		})
+	}
+}
+
+func TestGetBilling(t *testing.T) {
+	tests := []struct {
+		name         string
+		options      *BillingOptions
+		response     string
+		wantQuery    string
+		wantPayment  *PaymentMethod
+		wantNext     time.Time
+		wantInvoices []Invoice
+	}{
+		{
+			name:    "with invoices",
+			options: &BillingOptions{IncludeInvoices: true, InvoiceLimit: 2, InvoiceStatus: "paid"},
+			response: `{
+				"paymentMethod": {"type": "card", "brand": "visa", "last4": "4242", "expMonth": 8, "expYear": 2028},
+				"nextInvoiceDate": "2026-11-01T00:00:00Z",
+				"invoices": [
+					{"id": "inv_2", "amount": 19.99, "currency": "USD", "status": "paid", "dueDate": "2026-10-01T00:00:00Z"},
+					{"id": "inv_1", "amount": 9.5, "currency": "EUR", "status": "paid", "dueDate": "2026-09-01T00:00:00Z"}
+				]
+			}`,
+			wantQuery:   "includeInvoices=true&invoiceLimit=2&invoiceStatus=paid",
+			wantPayment: &PaymentMethod{Type: "card", Brand: "visa", Last4: "4242", ExpMonth: 8, ExpYear: 2028},
+			wantNext:    time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC),
+			wantInvoices: []Invoice{
+				{ID: "inv_2", Amount: 19.99, Currency: "USD", Status: "paid", DueDate: time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)},
+				{ID: "inv_1", Amount: 9.5, Currency: "EUR", Status: "paid", DueDate: time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)},
+			},
+		},
+		{
+			name:     "free plan",
+			response: `{}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/v1/user/billing" {
+					t.Errorf("path = %q, want /v1/user/billing", r.URL.Path)
+				}
+				if got := r.URL.Query().Encode(); got != tt.wantQuery {
+					t.Errorf("query = %q, want %q", got, tt.wantQuery)
+				}
+				writeData(t, w, json.RawMessage(tt.response))
+			})
+
+			billing, err := client.User.GetBilling(context.Background(), tt.options)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(billing.PaymentMethod, tt.wantPayment) {
+				t.Errorf("payment method = %+v, want %+v", billing.PaymentMethod, tt.wantPayment)
+			}
+			switch {
+			case tt.wantNext.IsZero() && billing.NextInvoiceDate != nil:
+				t.Errorf("next invoice date = %v, want nil", billing.NextInvoiceDate)
+			case !tt.wantNext.IsZero() && (billing.NextInvoiceDate == nil || !billing.NextInvoiceDate.Equal(tt.wantNext)):
+				t.Errorf("next invoice date = %v, want %v", billing.NextInvoiceDate, tt.wantNext)
+			}
+			if len(billing.Invoices) != len(tt.wantInvoices) {
+				t.Fatalf("got %d invoices, want %d", len(billing.Invoices), len(tt.wantInvoices))
+			}
+			for i, want := range tt.wantInvoices {
+				got := billing.Invoices[i]
+				if got.ID != want.ID || got.Amount != want.Amount || got.Currency != want.Currency ||
+					got.Status != want.Status || !got.DueDate.Equal(want.DueDate) {
+					t.Errorf("invoice %d = %+v, want %+v", i, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestExportLifecycle(t *testing.T) {
+	statuses := []string{"queued", "processing", "completed"}
+	var requestBody string
+	polls := 0
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/v1/user/export":
+			data, _ := io.ReadAll(r.Body)
+			requestBody = strings.TrimSpace(string(data))
+			writeData(t, w, map[string]interface{}{"id": "exp_1", "status": "queued"})
+		case r.Method == http.MethodGet && r.URL.Path == "/v1/user/export/exp_1":
+			job := map[string]interface{}{"id": "exp_1", "status": statuses[polls], "progress": float64(polls) / 2}
+			if statuses[polls] == "completed" {
+				job["downloadUrl"] = "https://cdn.example.com/exports/exp_1.zip"
+			}
+			polls++
+			writeData(t, w, job)
+		case r.Method == http.MethodGet && r.URL.Path == "/v1/user/export/exp_1/download":
+			if polls < len(statuses) {
+				writeError(t, w, http.StatusConflict, "EXPORT_NOT_READY", "export is still processing")
+				return
+			}
+			w.Header().Set("Content-Type", "application/zip")
+			w.Write([]byte("export archive"))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	})
+
+	ctx := context.Background()
+	started, err := client.User.ExportData(ctx, &ExportDataOptions{Format: "json", IncludeTracks: true})
+	if err != nil {
+		t.Fatalf("ExportData: %v", err)
+	}
+	if requestBody != `{"format":"json","includeTracks":true}` {
+		t.Errorf("body = %s", requestBody)
+	}
+	exportID, _ := started["id"].(string)
+	if exportID != "exp_1" {
+		t.Fatalf("export ID = %q, want exp_1", exportID)
+	}
+
+	if _, err := client.User.DownloadExport(ctx, exportID); err == nil {
+		t.Error("downloading an unfinished export succeeded")
+	}
+
+	done, err := client.User.WaitForExport(ctx, exportID, &PollOptions{Interval: time.Millisecond})
+	if err != nil {
+		t.Fatalf("WaitForExport: %v", err)
+	}
+	if polls != len(statuses) {
+		t.Errorf("polls = %d, want %d", polls, len(statuses))
+	}
+	if done.Status != "completed" || done.Progress != 1 || done.DownloadURL != "https://cdn.example.com/exports/exp_1.zip" {
+		t.Errorf("finished export = %+v", done)
+	}
+
+	body, err := client.User.DownloadExport(ctx, exportID)
+	if err != nil {
+		t.Fatalf("DownloadExport: %v", err)
+	}
+	defer body.Close()
+	data, err := io.ReadAll(body)
+	if err != nil {
+		t.Fatalf("reading export: %v", err)
+	}
+	if string(data) != "export archive" {
+		t.Errorf("export = %q, want %q", data, "export archive")
+	}
+}
@@ -0,0 +1,245 @@
+package jewelmusic
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// SignatureHeader is the HTTP header carrying the webhook signature
+const SignatureHeader = "X-JewelMusic-Signature"
+
+// DefaultMaxWebhookBodySize is the default maximum accepted webhook body size
+const DefaultMaxWebhookBodySize = 1 << 20
+
+// DefaultDrainTimeout is how long RunWebhookServer waits for in-flight
+// requests to finish during shutdown
+const DefaultDrainTimeout = 30 * time.Second
+
+// Errors returned by ParseRequest
+var (
+	ErrMissingSignature = errors.New("jewelmusic: missing webhook signature")
+	ErrInvalidSignature = errors.New("jewelmusic: invalid webhook signature")
+	ErrInvalidPayload   = errors.New("jewelmusic: invalid webhook payload")
+	ErrPayloadTooLarge  = errors.New("jewelmusic: webhook payload too large")
+)
+
+// ParseRequest reads, verifies, and parses a webhook from an incoming HTTP
+// request. The request body is always consumed and closed, and bodies larger
+// than DefaultMaxWebhookBodySize are rejected. The returned error wraps
+// ErrMissingSignature, ErrInvalidSignature, ErrInvalidPayload, or
+// ErrPayloadTooLarge.
+func ParseRequest(r *http.Request, secret string) (*WebhookEvent, error) {
+	return parseRequest(r, secret, SystemClock, DefaultSignatureTolerance, DefaultMaxWebhookBodySize)
+}
+
+// ParseRequestWithLimit is like ParseRequest but rejects bodies larger than
+// maxBytes instead of DefaultMaxWebhookBodySize
+func ParseRequestWithLimit(r *http.Request, secret string, maxBytes int64) (*WebhookEvent, error) {
+	return parseRequest(r, secret, SystemClock, DefaultSignatureTolerance, maxBytes)
+}
+
+// ParseRequestWithClock is like ParseRequest but checks the signature
+// timestamp tolerance against clock
+func ParseRequestWithClock(clock Clock, r *http.Request, secret string) (*WebhookEvent, error) {
+	return parseRequest(r, secret, clock, DefaultSignatureTolerance, DefaultMaxWebhookBodySize)
+}
+
+// parseRequest implements ParseRequest with a custom clock, tolerance, and
+// size limit
+func parseRequest(r *http.Request, secret string, clock Clock, tolerance int, maxBytes int64) (*WebhookEvent, error) {
+	defer r.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to read body: %v", ErrInvalidPayload, err)
+	}
+	if int64(len(body)) > maxBytes {
+		return nil, fmt.Errorf("%w: exceeds %d bytes", ErrPayloadTooLarge, maxBytes)
+	}
+
+	signature := r.Header.Get(SignatureHeader)
+	if signature == "" {
+		return nil, ErrMissingSignature
+	}
+	if !VerifySignatureWithClock(clock, body, signature, secret, tolerance) {
+		return nil, ErrInvalidSignature
+	}
+
+	event, err := ParseEvent(body)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPayload, err)
+	}
+	return event, nil
+}
+
+// WebhookEventHandler handles a single verified webhook event
+type WebhookEventHandler func(ctx context.Context, event *WebhookEvent) error
+
+// WebhookHandler is an http.Handler that verifies, parses, and dispatches
+// webhook events to handlers registered per event type
+type WebhookHandler struct {
+	secret      string
+	clock       Clock
+	tolerance   int
+	maxBodySize int64
+
+	mu       sync.RWMutex
+	handlers map[string]WebhookEventHandler
+	fallback WebhookEventHandler
+}
+
+// NewWebhookHandler creates a webhook handler that verifies requests with secret
+func NewWebhookHandler(secret string) *WebhookHandler {
+	return &WebhookHandler{
+		secret:      secret,
+		clock:       SystemClock,
+		tolerance:   DefaultSignatureTolerance,
+		maxBodySize: DefaultMaxWebhookBodySize,
+		handlers:    make(map[string]WebhookEventHandler),
+	}
+}
+
+// SetTolerance sets the allowed timestamp skew in seconds
+func (h *WebhookHandler) SetTolerance(seconds int) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.tolerance = seconds
+}
+
+// SetClock sets the clock that signature timestamps are checked against
+func (h *WebhookHandler) SetClock(clock Clock) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.clock = clockOrSystem(clock)
+}
+
+// SetMaxBodySize sets the maximum accepted request body size in bytes
+func (h *WebhookHandler) SetMaxBodySize(maxBytes int64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.maxBodySize = maxBytes
+}
+
+// On registers a handler for an event type, replacing any previous one
+func (h *WebhookHandler) On(eventType string, handler WebhookEventHandler) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.handlers[eventType] = handler
+}
+
+// OnUnhandled registers a handler for event types without a specific handler
+func (h *WebhookHandler) OnUnhandled(handler WebhookEventHandler) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.fallback = handler
+}
+
+// ServeHTTP implements http.Handler
+func (h *WebhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	h.mu.RLock()
+	clock, tolerance, maxBodySize := h.clock, h.tolerance, h.maxBodySize
+	h.mu.RUnlock()
+
+	event, err := parseRequest(r, h.secret, clock, tolerance, maxBodySize)
+	if errors.Is(err, ErrInvalidSignature) {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	if errors.Is(err, ErrPayloadTooLarge) {
+		http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	h.mu.RLock()
+	handler, ok := h.handlers[event.Type]
+	if !ok {
+		handler = h.fallback
+	}
+	h.mu.RUnlock()
+
+	if handler != nil {
+		if err := handler(r.Context(), event); err != nil {
+			http.Error(w, "Failed to process webhook event", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"received":  true,
+		"eventId":   event.ID,
+		"eventType": event.Type,
+	})
+}
+
+// RunWebhookServer serves handler on addr until ctx is cancelled or the
+// process receives SIGINT or SIGTERM, then shuts the server down gracefully,
+// waiting up to DefaultDrainTimeout for in-flight requests to complete.
+func RunWebhookServer(ctx context.Context, addr string, handler http.Handler) error {
+	server := &http.Server{
+		Addr:         addr,
+		Handler:      handler,
+		ReadTimeout:  10 * time.Second,
+		WriteTimeout: 10 * time.Second,
+		IdleTimeout:  60 * time.Second,
+	}
+
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+
+	return serveUntilDone(ctx, server, listener, DefaultDrainTimeout)
+}
+
+// serveUntilDone runs server on listener and shuts it down when ctx is done
+// or a termination signal arrives
+func serveUntilDone(ctx context.Context, server *http.Server, listener net.Listener, drainTimeout time.Duration) error {
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- server.Serve(listener)
+	}()
+
+	select {
+	case err := <-serveErr:
+		if errors.Is(err, http.ErrServerClosed) {
+			return nil
+		}
+		return err
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), drainTimeout)
+	defer cancel()
+
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		return err
+	}
+	if err := <-serveErr; err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,147 @@
+package jewelmusic
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestServeUntilDoneShutsDownOnCancel(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	started := make(chan struct{})
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		time.Sleep(50 * time.Millisecond)
+		io.WriteString(w, "ok")
+	})
+	server := &http.Server{Handler: handler}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- serveUntilDone(ctx, server, listener, 5*time.Second)
+	}()
+
+	// Cancel while a request is in flight; it must still complete
+	response := make(chan string, 1)
+	go func() {
+		resp, err := http.Get("http://" + listener.Addr().String())
+		if err != nil {
+			response <- err.Error()
+			return
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		response <- string(body)
+	}()
+	<-started
+	cancel()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("serveUntilDone: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("server did not shut down after cancellation")
+	}
+	if body := <-response; body != "ok" {
+		t.Errorf("in-flight request got %q, want ok", body)
+	}
+	if _, err := http.Get("http://" + listener.Addr().String()); err == nil {
+		t.Error("server still accepts connections after shutdown")
+	}
+}
+
+// signedRequest builds a webhook request carrying body signed with secret
+func signedRequest(body, secret string) *http.Request {
+	r := httptest.NewRequest("POST", "/webhooks", strings.NewReader(body))
+	if secret != "" {
+		r.Header.Set(SignatureHeader, CreateSignature([]byte(body), secret, nil))
+	}
+	return r
+}
+
+func TestParseRequest(t *testing.T) {
+	const secret = "whsec_test"
+	const payload = `{"id":"evt_1","type":"track.uploaded","data":{"trackId":"trk_1"}}`
+
+	tests := []struct {
+		name    string
+		request *http.Request
+		wantErr error
+	}{
+		{"valid", signedRequest(payload, secret), nil},
+		{"missing signature", signedRequest(payload, ""), ErrMissingSignature},
+		{"wrong secret", signedRequest(payload, "whsec_other"), ErrInvalidSignature},
+		{"malformed signature", func() *http.Request {
+			r := signedRequest(payload, "")
+			r.Header.Set(SignatureHeader, "garbage")
+			return r
+		}(), ErrInvalidSignature},
+		{"invalid JSON", signedRequest(`{"id":`, secret), ErrInvalidPayload},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event, err := ParseRequest(tt.request, secret)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ParseRequest error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && (event.ID != "evt_1" || event.Type != EventTrackUploaded) {
+				t.Errorf("event = %+v", event)
+			}
+		})
+	}
+}
+
+func TestParseRequestRejectsOversizedBody(t *testing.T) {
+	const secret = "whsec_test"
+	payload := `{"id":"evt_1","type":"track.uploaded","data":{"note":"` + strings.Repeat("x", 100) + `"}}`
+
+	tests := []struct {
+		name     string
+		maxBytes int64
+		wantErr  error
+	}{
+		{"within limit", int64(len(payload)), nil},
+		{"one byte over", int64(len(payload)) - 1, ErrPayloadTooLarge},
+		{"far over", 16, ErrPayloadTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseRequestWithLimit(signedRequest(payload, secret), secret, tt.maxBytes)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ParseRequestWithLimit error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	t.Run("default limit", func(t *testing.T) {
+		huge := strings.Repeat("x", DefaultMaxWebhookBodySize+1)
+		if _, err := ParseRequest(signedRequest(huge, secret), secret); !errors.Is(err, ErrPayloadTooLarge) {
+			t.Errorf("ParseRequest error = %v, want ErrPayloadTooLarge", err)
+		}
+	})
+
+	t.Run("handler status", func(t *testing.T) {
+		handler := NewWebhookHandler(secret)
+		handler.SetMaxBodySize(16)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, signedRequest(payload, secret))
+		if w.Code != http.StatusRequestEntityTooLarge {
+			t.Errorf("status = %d, want %d", w.Code, http.StatusRequestEntityTooLarge)
+		}
+	})
+}
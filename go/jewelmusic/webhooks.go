@@ -3,10 +3,14 @@ package jewelmusic
 import (
 	"context"
 	"crypto/hmac"
+	"crypto/rand"
 	"crypto/sha256"
+	"crypto/sha512"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"hash"
+	"maps"
 	"strconv"
 	"strings"
 	"time"
@@ -48,6 +52,43 @@ type RetryPolicy struct {
 	MaxBackoffDelay   int `json:"maxBackoffDelay"`
 }
 
+// DefaultRetryPolicy returns a retry policy of 5 retries with delays doubling
+// up to one hour
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxRetries:        5,
+		BackoffMultiplier: 2,
+		MaxBackoffDelay:   3600,
+	}
+}
+
+// Validate checks that the retry counts and delays are non-negative and the
+// backoff multiplier is at least 1. A nil policy is valid.
+func (p *RetryPolicy) Validate() error {
+	if p == nil {
+		return nil
+	}
+
+	fields := make(map[string][]string)
+	if p.MaxRetries < 0 {
+		fields["maxRetries"] = append(fields["maxRetries"], "must not be negative")
+	}
+	if p.BackoffMultiplier < 1 {
+		fields["backoffMultiplier"] = append(fields["backoffMultiplier"], "must be at least 1")
+	}
+	if p.MaxBackoffDelay < 0 {
+		fields["maxBackoffDelay"] = append(fields["maxBackoffDelay"], "must not be negative")
+	}
+
+	if len(fields) == 0 {
+		return nil
+	}
+	return &ValidationError{
+		APIError: &APIError{Code: "VALIDATION_ERROR", Message: "Invalid webhook retry policy"},
+		Fields:   fields,
+	}
+}
+
 // WebhookFilter represents filters for listing webhooks
 type WebhookFilter struct {
 	Active bool     `json:"active,omitempty"`
@@ -55,43 +96,119 @@ type WebhookFilter struct {
 	URL    string   `json:"url,omitempty"`
 }
 
-// DeliveryFilter represents filters for webhook deliveries
+// DeliveryFilter represents filters for webhook deliveries.
+// StartTime and EndTime take precedence over StartDate and EndDate when set.
 type DeliveryFilter struct {
-	Status    string `json:"status,omitempty"`
-	EventType string `json:"eventType,omitempty"`
-	StartDate string `json:"startDate,omitempty"`
-	EndDate   string `json:"endDate,omitempty"`
+	Status    string    `json:"status,omitempty"`
+	EventType string    `json:"eventType,omitempty"`
+	StartDate string    `json:"startDate,omitempty"`
+	EndDate   string    `json:"endDate,omitempty"`
+	StartTime time.Time `json:"-"`
+	EndTime   time.Time `json:"-"`
 }
 
-// StatisticsOptions represents options for webhook statistics
+// StatisticsOptions represents options for webhook statistics.
+// StartTime and EndTime take precedence over StartDate and EndDate when set.
 type StatisticsOptions struct {
-	Period    string `json:"period,omitempty"`
-	StartDate string `json:"startDate,omitempty"`
-	EndDate   string `json:"endDate,omitempty"`
-	GroupBy   string `json:"groupBy,omitempty"`
+	Period    string    `json:"period,omitempty"`
+	StartDate string    `json:"startDate,omitempty"`
+	EndDate   string    `json:"endDate,omitempty"`
+	GroupBy   string    `json:"groupBy,omitempty"`
+	StartTime time.Time `json:"-"`
+	EndTime   time.Time `json:"-"`
+}
+
+// dateRange resolves the start and end query values for a date filter,
+// formatting typed times as RFC 3339 and checking that start <= end
+func dateRange(startDate, endDate string, startTime, endTime time.Time) (string, string, error) {
+	if !startTime.IsZero() && !endTime.IsZero() && startTime.After(endTime) {
+		return "", "", &APIError{Code: "INVALID_REQUEST", Message: "Start time must not be after end time"}
+	}
+	if !startTime.IsZero() {
+		startDate = startTime.UTC().Format(time.RFC3339)
+	}
+	if !endTime.IsZero() {
+		endDate = endTime.UTC().Format(time.RFC3339)
+	}
+	return startDate, endDate, nil
+}
+
+// Webhook event types
+const (
+	EventTrackUploaded              = "track.uploaded"
+	EventTrackProcessed             = "track.processed"
+	EventTrackFailed                = "track.failed"
+	EventAnalysisCompleted          = "analysis.completed"
+	EventTranscriptionCompleted     = "transcription.completed"
+	EventDistributionSubmitted      = "distribution.submitted"
+	EventDistributionReleased       = "distribution.released"
+	EventDistributionLive           = "distribution.live"
+	EventDistributionFailed         = "distribution.failed"
+	EventRoyaltyPayment             = "royalty.payment"
+	EventUserSubscriptionUpdated    = "user.subscription.updated"
+	EventCopilotGenerationCompleted = "copilot.generation_completed"
+	EventWebhookTest                = "webhook.test"
+)
+
+// EventTypes returns all webhook event types known to the SDK
+func EventTypes() []string {
+	return []string{
+		EventTrackUploaded,
+		EventTrackProcessed,
+		EventTrackFailed,
+		EventAnalysisCompleted,
+		EventTranscriptionCompleted,
+		EventDistributionSubmitted,
+		EventDistributionReleased,
+		EventDistributionLive,
+		EventDistributionFailed,
+		EventRoyaltyPayment,
+		EventUserSubscriptionUpdated,
+		EventCopilotGenerationCompleted,
+		EventWebhookTest,
+	}
+}
+
+// IsValidEventType reports whether eventType is a known webhook event type
+func IsValidEventType(eventType string) bool {
+	for _, known := range EventTypes() {
+		if eventType == known {
+			return true
+		}
+	}
+	return false
+}
+
+// validateEvents checks that all requested events are known event types
+func validateEvents(events []string) error {
+	for _, event := range events {
+		if !IsValidEventType(event) {
+			return &APIError{Code: "INVALID_REQUEST", Message: "Unknown webhook event type: " + event}
+		}
+	}
+	return nil
 }
 
 // List gets list of webhooks with filtering and pagination
 func (w *WebhooksResource) List(ctx context.Context, page, perPage int, filter *WebhookFilter) (*ListResponse, error) {
-	params := map[string]string{
-		"page":    strconv.Itoa(page),
-		"perPage": strconv.Itoa(perPage),
-	}
-	
+	params := pageParams(page, perPage)
+
 	if filter != nil {
 		if filter.Active {
 			params["active"] = "true"
 		}
-		if len(filter.Events) > 0 {
-			params["events"] = strings.Join(filter.Events, ",")
-		}
 		if filter.URL != "" {
 			params["url"] = filter.URL
 		}
 	}
 
+	var lists map[string][]string
+	if filter != nil {
+		lists = map[string][]string{"events": filter.Events}
+	}
+
 	var result ListResponse
-	err := w.client.Get(ctx, "/webhooks", params, &result)
+	err := w.client.getWithLists(ctx, "/webhooks", params, lists, &result)
 	return &result, err
 }
 
@@ -104,6 +221,13 @@ func (w *WebhooksResource) Get(ctx context.Context, webhookID string) (*Webhook,
 
 // Create creates a new webhook endpoint
 func (w *WebhooksResource) Create(ctx context.Context, webhookData WebhookCreate) (*Webhook, error) {
+	if err := validateEvents(webhookData.Events); err != nil {
+		return nil, err
+	}
+	if err := webhookData.RetryPolicy.Validate(); err != nil {
+		return nil, err
+	}
+
 	var result Webhook
 	err := w.client.Post(ctx, "/webhooks", webhookData, &result)
 	return &result, err
@@ -111,6 +235,13 @@ func (w *WebhooksResource) Create(ctx context.Context, webhookData WebhookCreate
 
 // Update updates an existing webhook
 func (w *WebhooksResource) Update(ctx context.Context, webhookID string, updates WebhookUpdate) (*Webhook, error) {
+	if err := validateEvents(updates.Events); err != nil {
+		return nil, err
+	}
+	if err := updates.RetryPolicy.Validate(); err != nil {
+		return nil, err
+	}
+
 	var result Webhook
 	err := w.client.Put(ctx, "/webhooks/"+webhookID, updates, &result)
 	return &result, err
@@ -123,39 +254,83 @@ func (w *WebhooksResource) Delete(ctx context.Context, webhookID string) (map[st
 	return result, err
 }
 
-// Test tests a webhook by sending a test event
-func (w *WebhooksResource) Test(ctx context.Context, webhookID string, eventType string) (map[string]interface{}, error) {
+// WebhookTestResult reports the outcome of a test event sent by Test
+type WebhookTestResult struct {
+	Delivered  bool   `json:"delivered"`
+	StatusCode int    `json:"statusCode,omitempty"`
+	LatencyMs  int    `json:"latencyMs"`
+	Error      string `json:"error,omitempty"`
+}
+
+// Test tests a webhook by sending a test event. A failed delivery is
+// reported in the result rather than as an error.
+func (w *WebhooksResource) Test(ctx context.Context, webhookID string, eventType string) (*WebhookTestResult, error) {
 	requestData := map[string]interface{}{
 		"eventType": eventType,
 	}
 	if eventType == "" {
-		requestData["eventType"] = "webhook.test"
+		requestData["eventType"] = EventWebhookTest
 	}
 
-	var result map[string]interface{}
-	err := w.client.Post(ctx, "/webhooks/"+webhookID+"/test", requestData, &result)
-	return result, err
+	var result WebhookTestResult
+	if err := w.client.Post(ctx, "/webhooks/"+webhookID+"/test", requestData, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// WebhookProbeResult reports whether a URL received a signed test event
+type WebhookProbeResult struct {
+	Reachable  bool   `json:"reachable"`
+	StatusCode int    `json:"statusCode,omitempty"`
+	LatencyMs  int    `json:"latencyMs"`
+	Error      string `json:"error,omitempty"`
+}
+
+// Probe asks the server to send a test event signed with secret to url
+// before a webhook is created for it, so that misconfigured endpoints are
+// caught at setup. An unreachable URL is reported in the result rather than
+// as an error.
+func (w *WebhooksResource) Probe(ctx context.Context, url, secret string) (*WebhookProbeResult, error) {
+	if url == "" {
+		return nil, &APIError{Code: "INVALID_REQUEST", Message: "URL is required"}
+	}
+
+	requestData := map[string]interface{}{
+		"url":       url,
+		"eventType": EventWebhookTest,
+	}
+	if secret != "" {
+		requestData["secret"] = secret
+	}
+
+	var result WebhookProbeResult
+	if err := w.client.Post(ctx, "/webhooks/probe", requestData, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
 }
 
 // GetDeliveries gets webhook delivery history
 func (w *WebhooksResource) GetDeliveries(ctx context.Context, webhookID string, page, perPage int, filter *DeliveryFilter) (*ListResponse, error) {
-	params := map[string]string{
-		"page":    strconv.Itoa(page),
-		"perPage": strconv.Itoa(perPage),
-	}
-	
+	params := pageParams(page, perPage)
+
 	if filter != nil {
+		startDate, endDate, err := dateRange(filter.StartDate, filter.EndDate, filter.StartTime, filter.EndTime)
+		if err != nil {
+			return nil, err
+		}
 		if filter.Status != "" {
 			params["status"] = filter.Status
 		}
 		if filter.EventType != "" {
 			params["eventType"] = filter.EventType
 		}
-		if filter.StartDate != "" {
-			params["startDate"] = filter.StartDate
+		if startDate != "" {
+			params["startDate"] = startDate
 		}
-		if filter.EndDate != "" {
-			params["endDate"] = filter.EndDate
+		if endDate != "" {
+			params["endDate"] = endDate
 		}
 	}
 
@@ -164,6 +339,24 @@ func (w *WebhooksResource) GetDeliveries(ctx context.Context, webhookID string,
 	return &result, err
 }
 
+// DeliveriesIterator returns an iterator over all deliveries of a webhook
+// matching filter
+func (w *WebhooksResource) DeliveriesIterator(webhookID string, filter *DeliveryFilter) *Iterator[map[string]interface{}] {
+	return newIterator[map[string]interface{}](func(ctx context.Context, page, perPage int) (*ListResponse, error) {
+		return w.GetDeliveries(ctx, webhookID, page, perPage, filter)
+	})
+}
+
+// FailedDeliveries returns an iterator over the failed deliveries of a
+// webhook since the given time, for bulk inspection. A zero since includes
+// all failed deliveries.
+func (w *WebhooksResource) FailedDeliveries(webhookID string, since time.Time) *Iterator[WebhookDelivery] {
+	filter := &DeliveryFilter{Status: "failed", StartTime: since}
+	return newIterator[WebhookDelivery](func(ctx context.Context, page, perPage int) (*ListResponse, error) {
+		return w.GetDeliveries(ctx, webhookID, page, perPage, filter)
+	})
+}
+
 // GetDelivery gets specific webhook delivery details
 func (w *WebhooksResource) GetDelivery(ctx context.Context, webhookID, deliveryID string) (map[string]interface{}, error) {
 	var result map[string]interface{}
@@ -188,16 +381,20 @@ func (w *WebhooksResource) GetEventTypes(ctx context.Context) ([]string, error)
 // GetStatistics gets webhook statistics and metrics
 func (w *WebhooksResource) GetStatistics(ctx context.Context, webhookID string, options *StatisticsOptions) (map[string]interface{}, error) {
 	params := make(map[string]string)
-	
+
 	if options != nil {
+		startDate, endDate, err := dateRange(options.StartDate, options.EndDate, options.StartTime, options.EndTime)
+		if err != nil {
+			return nil, err
+		}
 		if options.Period != "" {
 			params["period"] = options.Period
 		}
-		if options.StartDate != "" {
-			params["startDate"] = options.StartDate
+		if startDate != "" {
+			params["startDate"] = startDate
 		}
-		if options.EndDate != "" {
-			params["endDate"] = options.EndDate
+		if endDate != "" {
+			params["endDate"] = endDate
 		}
 		if options.GroupBy != "" {
 			params["groupBy"] = options.GroupBy
@@ -209,45 +406,267 @@ func (w *WebhooksResource) GetStatistics(ctx context.Context, webhookID string,
 	return result, err
 }
 
+// WebhookSyncReport describes the changes made by Sync
+type WebhookSyncReport struct {
+	Created []Webhook `json:"created"`
+	Updated []Webhook `json:"updated"`
+	Deleted []string  `json:"deleted"`
+}
+
+// Sync reconciles the account's webhooks with the desired set. Webhooks are
+// matched by URL: missing ones are created, ones whose configuration differs
+// are updated in place, and webhooks whose URL is not in the desired set are
+// deleted. As with Create, an empty Secret, a false Active, a zero Timeout,
+// and nil Headers or RetryPolicy leave the current setting unchanged.
+func (w *WebhooksResource) Sync(ctx context.Context, desired []WebhookCreate) (*WebhookSyncReport, error) {
+	desiredByURL := make(map[string]WebhookCreate, len(desired))
+	for _, d := range desired {
+		if d.URL == "" {
+			return nil, &APIError{Code: "INVALID_REQUEST", Message: "Webhook URL is required"}
+		}
+		if _, exists := desiredByURL[d.URL]; exists {
+			return nil, &APIError{Code: "INVALID_REQUEST", Message: "Duplicate webhook URL: " + d.URL}
+		}
+		if err := validateEvents(d.Events); err != nil {
+			return nil, err
+		}
+		desiredByURL[d.URL] = d
+	}
+
+	existing, err := w.listAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &WebhookSyncReport{}
+	seen := make(map[string]bool, len(existing))
+
+	for _, current := range existing {
+		want, ok := desiredByURL[current.URL]
+		if !ok || seen[current.URL] {
+			if _, err := w.Delete(ctx, current.ID); err != nil {
+				return report, err
+			}
+			report.Deleted = append(report.Deleted, current.ID)
+			continue
+		}
+		seen[current.URL] = true
+
+		if !webhookDrifted(current, want) {
+			continue
+		}
+		update := WebhookUpdate{
+			Events:      want.Events,
+			Secret:      want.Secret,
+			Description: want.Description,
+			Headers:     want.Headers,
+			Timeout:     want.Timeout,
+			RetryPolicy: want.RetryPolicy,
+		}
+		if want.Active {
+			update.Active = Bool(true)
+		}
+		updated, err := w.Update(ctx, current.ID, update)
+		if err != nil {
+			return report, err
+		}
+		report.Updated = append(report.Updated, *updated)
+	}
+
+	for _, d := range desired {
+		if seen[d.URL] {
+			continue
+		}
+		created, err := w.Create(ctx, d)
+		if err != nil {
+			return report, err
+		}
+		report.Created = append(report.Created, *created)
+	}
+
+	return report, nil
+}
+
+// listAll fetches every webhook across all pages
+func (w *WebhooksResource) listAll(ctx context.Context) ([]Webhook, error) {
+	it := newIterator[Webhook](func(ctx context.Context, page, perPage int) (*ListResponse, error) {
+		return w.List(ctx, page, perPage, nil)
+	})
+
+	var all []Webhook
+	for it.Next(ctx) {
+		all = append(all, it.Value())
+	}
+	return all, it.Err()
+}
+
+// webhookDrifted reports whether current differs from any setting in want
+func webhookDrifted(current Webhook, want WebhookCreate) bool {
+	switch {
+	case !sameEvents(current.Events, want.Events), current.Description != want.Description:
+		return true
+	case want.Secret != "" && current.Secret != want.Secret:
+		return true
+	case want.Active && !current.Active:
+		return true
+	case want.Headers != nil && !maps.Equal(current.Headers, want.Headers):
+		return true
+	case want.Timeout != 0 && current.Timeout != want.Timeout:
+		return true
+	case want.RetryPolicy != nil && (current.RetryPolicy == nil || *current.RetryPolicy != *want.RetryPolicy):
+		return true
+	}
+	return false
+}
+
+// sameEvents reports whether two event lists contain the same events
+func sameEvents(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	counts := make(map[string]int, len(a))
+	for _, e := range a {
+		counts[e]++
+	}
+	for _, e := range b {
+		if counts[e] == 0 {
+			return false
+		}
+		counts[e]--
+	}
+	return true
+}
+
+// RotateSecret replaces the webhook secret with a newly generated one.
+// The new secret is returned so that it can be stored by the caller; during
+// the rollover window use VerifyWithSecrets to accept both secrets.
+func (w *WebhooksResource) RotateSecret(ctx context.Context, webhookID string) (*Webhook, string, error) {
+	secret, err := GenerateSecret()
+	if err != nil {
+		return nil, "", err
+	}
+
+	webhook, err := w.Update(ctx, webhookID, WebhookUpdate{Secret: secret})
+	if err != nil {
+		return nil, "", err
+	}
+	return webhook, secret, nil
+}
+
+// GenerateSecret generates a random 256-bit webhook secret encoded as hex
+func GenerateSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// DefaultSignatureTolerance is the default allowed clock skew in seconds
+const DefaultSignatureTolerance = 300
+
+// VerifyWithSecrets verifies a webhook signature against both the previous
+// and the current secret, which allows a zero-downtime secret rotation.
+// Empty secrets are ignored.
+func VerifyWithSecrets(payload []byte, signature, oldSecret, newSecret string) bool {
+	return VerifyWithSecretsAndClock(SystemClock, payload, signature, oldSecret, newSecret)
+}
+
+// VerifyWithSecretsAndClock is like VerifyWithSecrets but checks the
+// timestamp tolerance against clock
+func VerifyWithSecretsAndClock(clock Clock, payload []byte, signature, oldSecret, newSecret string) bool {
+	for _, secret := range []string{newSecret, oldSecret} {
+		if secret == "" {
+			continue
+		}
+		if VerifySignatureWithClock(clock, payload, signature, secret, DefaultSignatureTolerance) {
+			return true
+		}
+	}
+	return false
+}
+
+// SignatureScheme pairs a signature version in the signature header, such
+// as "v1", with the hash function of its HMAC
+type SignatureScheme struct {
+	Version string
+	Hash    func() hash.Hash
+}
+
+// Signature schemes supported by the platform
+var (
+	SchemeHMACSHA256 = SignatureScheme{Version: "v1", Hash: sha256.New}
+	SchemeHMACSHA512 = SignatureScheme{Version: "v2", Hash: sha512.New}
+)
+
+// DefaultSignatureSchemes are the schemes accepted by VerifySignature
+var DefaultSignatureSchemes = []SignatureScheme{SchemeHMACSHA256, SchemeHMACSHA512}
+
 // VerifySignature verifies webhook signature
 // This is a static method that can be used to verify webhook signatures
-// without making an API call.
+// without making an API call. The signature is accepted if any of the
+// DefaultSignatureSchemes present in the header matches.
 func VerifySignature(payload []byte, signature, secret string, tolerance int) bool {
-	// Parse signature header (format: "t=timestamp,v1=hash")
+	return VerifySignatureWithSchemes(payload, signature, secret, tolerance, DefaultSignatureSchemes...)
+}
+
+// VerifySignatureWithSchemes verifies a webhook signature using only the
+// given schemes. The header may carry several versions
+// ("t=timestamp,v1=hash,v2=hash"); the signature is accepted if any of them
+// matches.
+func VerifySignatureWithSchemes(payload []byte, signature, secret string, tolerance int, schemes ...SignatureScheme) bool {
+	return VerifySignatureWithClock(SystemClock, payload, signature, secret, tolerance, schemes...)
+}
+
+// VerifySignatureWithClock is like VerifySignatureWithSchemes but checks the
+// timestamp tolerance against clock. No schemes means
+// DefaultSignatureSchemes.
+func VerifySignatureWithClock(clock Clock, payload []byte, signature, secret string, tolerance int, schemes ...SignatureScheme) bool {
+	if len(schemes) == 0 {
+		schemes = DefaultSignatureSchemes
+	}
+
+	// Parse signature header (format: "t=timestamp,v1=hash,v2=hash")
 	elements := strings.Split(signature, ",")
 	var timestamp int64
-	var hash string
-	
+	hashes := make(map[string][]string)
+
 	for _, element := range elements {
-		if strings.HasPrefix(element, "t=") {
-			timestampStr := strings.TrimPrefix(element, "t=")
+		key, value, ok := strings.Cut(strings.TrimSpace(element), "=")
+		if !ok {
+			continue
+		}
+		if key == "t" {
 			var err error
-			timestamp, err = strconv.ParseInt(timestampStr, 10, 64)
+			timestamp, err = strconv.ParseInt(value, 10, 64)
 			if err != nil {
 				return false
 			}
-		} else if strings.HasPrefix(element, "v1=") {
-			hash = strings.TrimPrefix(element, "v1=")
+		} else {
+			hashes[key] = append(hashes[key], value)
 		}
 	}
-	
-	if timestamp == 0 || hash == "" {
+
+	if timestamp == 0 || len(hashes) == 0 {
 		return false
 	}
-	
+
 	// Check timestamp tolerance
-	now := time.Now().Unix()
+	now := clockOrSystem(clock).Now().Unix()
 	if abs(now-timestamp) > int64(tolerance) {
 		return false
 	}
-	
+
 	// Verify signature
-	signedPayload := fmt.Sprintf("%d.%s", timestamp, string(payload))
-	expectedHash := hmac.New(sha256.New, []byte(secret))
-	expectedHash.Write([]byte(signedPayload))
-	expectedHashHex := hex.EncodeToString(expectedHash.Sum(nil))
-	
-	return hmac.Equal([]byte(hash), []byte(expectedHashHex))
+	for _, scheme := range schemes {
+		expectedHashHex := computeSignature(scheme.Hash, timestamp, payload, secret)
+		for _, got := range hashes[scheme.Version] {
+			if hmac.Equal([]byte(got), []byte(expectedHashHex)) {
+				return true
+			}
+		}
+	}
+	return false
 }
 
 // ParseEvent parses webhook event payload
@@ -264,19 +683,40 @@ func ParseEvent(payload []byte) (*WebhookEvent, error) {
 // CreateSignature creates webhook signature for testing
 // This utility method can be used for testing webhook signature verification.
 func CreateSignature(payload []byte, secret string, timestamp *int64) string {
-	var ts int64
-	if timestamp != nil {
-		ts = *timestamp
-	} else {
-		ts = time.Now().Unix()
+	if timestamp == nil {
+		return CreateSignatureWithClock(SystemClock, payload, secret)
 	}
-	
-	signedPayload := fmt.Sprintf("%d.%s", ts, string(payload))
-	hash := hmac.New(sha256.New, []byte(secret))
-	hash.Write([]byte(signedPayload))
-	hashHex := hex.EncodeToString(hash.Sum(nil))
-	
-	return fmt.Sprintf("t=%d,v1=%s", ts, hashHex)
+
+	hashHex := computeSignature(sha256.New, *timestamp, payload, secret)
+
+	return fmt.Sprintf("t=%d,v1=%s", *timestamp, hashHex)
+}
+
+// CreateSignatureWithClock creates a webhook signature timestamped with the
+// current time of clock
+func CreateSignatureWithClock(clock Clock, payload []byte, secret string) string {
+	ts := clockOrSystem(clock).Now().Unix()
+	return CreateSignature(payload, secret, &ts)
+}
+
+// CreateSignatureWithSchemes creates a webhook signature header carrying a
+// hash for each of the given schemes, for testing multi-scheme verification
+func CreateSignatureWithSchemes(payload []byte, secret string, timestamp int64, schemes ...SignatureScheme) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "t=%d", timestamp)
+	for _, scheme := range schemes {
+		fmt.Fprintf(&b, ",%s=%s", scheme.Version, computeSignature(scheme.Hash, timestamp, payload, secret))
+	}
+	return b.String()
+}
+
+// computeSignature returns the hex-encoded HMAC of "timestamp.payload" using
+// newHash
+func computeSignature(newHash func() hash.Hash, timestamp int64, payload []byte, secret string) string {
+	signedPayload := fmt.Sprintf("%d.%s", timestamp, string(payload))
+	mac := hmac.New(newHash, []byte(secret))
+	mac.Write([]byte(signedPayload))
+	return hex.EncodeToString(mac.Sum(nil))
 }
 
 // Helper function for absolute value
@@ -285,4 +725,4 @@ func abs(x int64) int64 {
 		return -x
 	}
 	return x
-}
\ No newline at end of file
+}
@@ -0,0 +1,647 @@
+package jewelmusic
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWebhookCreateValidatesEvents(t *testing.T) {
+	tests := []struct {
+		name    string
+		events  []string
+		wantErr bool
+	}{
+		{"known events", []string{EventTrackFailed, EventRoyaltyPayment, EventUserSubscriptionUpdated}, false},
+		{"unknown event", []string{EventTrackUploaded, "track.deleted"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				called = true
+				writeData(t, w, map[string]interface{}{"id": "wh_1"})
+			})
+
+			_, err := client.Webhooks.Create(context.Background(), WebhookCreate{
+				URL:    "https://example.com/hooks",
+				Events: tt.events,
+			})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Create error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if called == tt.wantErr {
+				t.Errorf("request sent = %v, want %v", called, !tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestWebhookRetryPolicyValidation(t *testing.T) {
+	tests := []struct {
+		name       string
+		policy     *RetryPolicy
+		wantFields []string
+	}{
+		{name: "no policy", policy: nil},
+		{name: "default policy", policy: DefaultRetryPolicy()},
+		{name: "no retries", policy: &RetryPolicy{MaxRetries: 0, BackoffMultiplier: 1, MaxBackoffDelay: 0}},
+		{name: "negative retries", policy: &RetryPolicy{MaxRetries: -1, BackoffMultiplier: 2, MaxBackoffDelay: 60}, wantFields: []string{"maxRetries"}},
+		{name: "zero multiplier", policy: &RetryPolicy{MaxRetries: 3, BackoffMultiplier: 0, MaxBackoffDelay: 60}, wantFields: []string{"backoffMultiplier"}},
+		{
+			name:       "all invalid",
+			policy:     &RetryPolicy{MaxRetries: -3, BackoffMultiplier: -2, MaxBackoffDelay: -60},
+			wantFields: []string{"backoffMultiplier", "maxBackoffDelay", "maxRetries"},
+		},
+	}
+
+	for _, tt := range tests {
+		for _, method := range []string{"create", "update"} {
+			t.Run(tt.name+"/"+method, func(t *testing.T) {
+				var sent *RetryPolicy
+				called := false
+				client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+					called = true
+					var body struct {
+						RetryPolicy *RetryPolicy `json:"retryPolicy"`
+					}
+					if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+						t.Errorf("decoding body: %v", err)
+					}
+					sent = body.RetryPolicy
+					writeData(t, w, map[string]interface{}{"id": "wh_1"})
+				})
+
+				var err error
+				if method == "create" {
+					_, err = client.Webhooks.Create(context.Background(), WebhookCreate{
+						URL:         "https://example.com/hooks",
+						Events:      []string{EventTrackUploaded},
+						RetryPolicy: tt.policy,
+					})
+				} else {
+					_, err = client.Webhooks.Update(context.Background(), "wh_1", WebhookUpdate{RetryPolicy: tt.policy})
+				}
+
+				if tt.wantFields == nil {
+					if err != nil {
+						t.Fatalf("unexpected error: %v", err)
+					}
+					if (sent == nil) != (tt.policy == nil) || (sent != nil && *sent != *tt.policy) {
+						t.Errorf("retry policy sent = %+v, want %+v", sent, tt.policy)
+					}
+					return
+				}
+
+				var validationErr *ValidationError
+				if !errors.As(err, &validationErr) {
+					t.Fatalf("err = %v, want a *ValidationError", err)
+				}
+				var fields []string
+				for field := range validationErr.Fields {
+					fields = append(fields, field)
+				}
+				sort.Strings(fields)
+				if strings.Join(fields, ",") != strings.Join(tt.wantFields, ",") {
+					t.Errorf("invalid fields = %v, want %v", fields, tt.wantFields)
+				}
+				if called {
+					t.Error("invalid retry policy was sent")
+				}
+			})
+		}
+	}
+}
+
+func TestDefaultRetryPolicy(t *testing.T) {
+	policy := DefaultRetryPolicy()
+	if err := policy.Validate(); err != nil {
+		t.Fatalf("default policy is invalid: %v", err)
+	}
+	if policy.MaxRetries <= 0 || policy.BackoffMultiplier < 1 || policy.MaxBackoffDelay <= 0 {
+		t.Errorf("default policy = %+v, want retries with growing, bounded delays", policy)
+	}
+	policy.MaxRetries = 0
+	if DefaultRetryPolicy().MaxRetries == 0 {
+		t.Error("DefaultRetryPolicy returns a shared policy")
+	}
+}
+
+func TestWebhookTestResult(t *testing.T) {
+	tests := []struct {
+		name     string
+		response map[string]interface{}
+		want     WebhookTestResult
+	}{
+		{
+			name:     "delivered",
+			response: map[string]interface{}{"delivered": true, "statusCode": 200, "latencyMs": 84},
+			want:     WebhookTestResult{Delivered: true, StatusCode: 200, LatencyMs: 84},
+		},
+		{
+			name:     "failed",
+			response: map[string]interface{}{"delivered": false, "statusCode": 500, "latencyMs": 1200, "error": "endpoint returned 500"},
+			want:     WebhookTestResult{StatusCode: 500, LatencyMs: 1200, Error: "endpoint returned 500"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var eventType interface{}
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				var body map[string]interface{}
+				json.NewDecoder(r.Body).Decode(&body)
+				eventType = body["eventType"]
+				writeData(t, w, tt.response)
+			})
+
+			result, err := client.Webhooks.Test(context.Background(), "wh_1", "")
+			if err != nil {
+				t.Fatalf("Test: %v", err)
+			}
+			if *result != tt.want {
+				t.Errorf("result = %+v, want %+v", *result, tt.want)
+			}
+			if eventType != EventWebhookTest {
+				t.Errorf("eventType = %v, want %s", eventType, EventWebhookTest)
+			}
+		})
+	}
+}
+
+func TestVerifyWithSecrets(t *testing.T) {
+	payload := []byte(`{"event":"track.processed"}`)
+	const oldSecret, newSecret = "whsec_old", "whsec_new"
+
+	tests := []struct {
+		name     string
+		signedBy string
+		old, new string
+		want     bool
+	}{
+		{"signed with new secret", newSecret, oldSecret, newSecret, true},
+		{"signed with old secret", oldSecret, oldSecret, newSecret, true},
+		{"old secret retired", oldSecret, "", newSecret, false},
+		{"unknown secret", "whsec_other", oldSecret, newSecret, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			signature := CreateSignature(payload, tt.signedBy, nil)
+			if got := VerifyWithSecrets(payload, signature, tt.old, tt.new); got != tt.want {
+				t.Errorf("VerifyWithSecrets = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerifySignatureSchemes(t *testing.T) {
+	payload := []byte(`{"event":"track.processed"}`)
+	const secret = "whsec_test"
+	ts := time.Now().Unix()
+
+	// hmacHex computes the expected signature independently of the SDK
+	hmacHex := func(newHash func() hash.Hash, secret string) string {
+		mac := hmac.New(newHash, []byte(secret))
+		fmt.Fprintf(mac, "%d.%s", ts, payload)
+		return hex.EncodeToString(mac.Sum(nil))
+	}
+	sha256Sig := hmacHex(sha256.New, secret)
+	sha512Sig := hmacHex(sha512.New, secret)
+
+	tests := []struct {
+		name    string
+		header  string
+		schemes []SignatureScheme
+		want    bool
+	}{
+		{"sha256", fmt.Sprintf("t=%d,v1=%s", ts, sha256Sig), nil, true},
+		{"sha512", fmt.Sprintf("t=%d,v2=%s", ts, sha512Sig), nil, true},
+		{"sha512 not accepted", fmt.Sprintf("t=%d,v2=%s", ts, sha512Sig), []SignatureScheme{SchemeHMACSHA256}, false},
+		{"sha256 hash labelled v2", fmt.Sprintf("t=%d,v2=%s", ts, sha256Sig), nil, false},
+		{"both versions", fmt.Sprintf("t=%d,v1=%s,v2=%s", ts, sha256Sig, sha512Sig), []SignatureScheme{SchemeHMACSHA512}, true},
+		{"invalid v1 valid v2", fmt.Sprintf("t=%d,v1=%s,v2=%s", ts, strings.Repeat("0", 64), sha512Sig), nil, true},
+		{"repeated v1", fmt.Sprintf("t=%d,v1=%s,v1=%s", ts, strings.Repeat("0", 64), sha256Sig), nil, true},
+		{"wrong secret", fmt.Sprintf("t=%d,v1=%s,v2=%s", ts, hmacHex(sha256.New, "whsec_other"), hmacHex(sha512.New, "whsec_other")), nil, false},
+		{"unknown version only", fmt.Sprintf("t=%d,v9=%s", ts, sha256Sig), nil, false},
+		{"created with schemes", CreateSignatureWithSchemes(payload, secret, ts, SchemeHMACSHA256, SchemeHMACSHA512), []SignatureScheme{SchemeHMACSHA512}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := VerifySignatureWithSchemes(payload, tt.header, secret, 300, tt.schemes...); got != tt.want {
+				t.Errorf("VerifySignatureWithSchemes(%q) = %v, want %v", tt.header, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRotateSecret(t *testing.T) {
+	var sent map[string]interface{}
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "PUT" || r.URL.Path != "/v1/webhooks/wh_1" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		json.NewDecoder(r.Body).Decode(&sent)
+		writeData(t, w, map[string]interface{}{"id": "wh_1"})
+	})
+
+	webhook, secret, err := client.Webhooks.RotateSecret(context.Background(), "wh_1")
+	if err != nil {
+		t.Fatalf("RotateSecret: %v", err)
+	}
+	if webhook.ID != "wh_1" {
+		t.Errorf("webhook ID = %q, want wh_1", webhook.ID)
+	}
+	if len(secret) != 64 || sent["secret"] != secret {
+		t.Errorf("secret = %q, sent %v", secret, sent["secret"])
+	}
+}
+
+func TestWebhookSync(t *testing.T) {
+	existing := []map[string]interface{}{
+		{"id": "wh_same", "url": "https://example.com/same", "events": []string{EventTrackUploaded}},
+		{"id": "wh_changed", "url": "https://example.com/changed", "events": []string{EventTrackUploaded}},
+		{"id": "wh_extra", "url": "https://example.com/extra", "events": []string{EventTrackUploaded}},
+	}
+	desired := []WebhookCreate{
+		{URL: "https://example.com/same", Events: []string{EventTrackUploaded}},
+		{URL: "https://example.com/changed", Events: []string{EventTrackUploaded, EventAnalysisCompleted}},
+		{URL: "https://example.com/new", Events: []string{EventDistributionLive}},
+	}
+
+	var mu sync.Mutex
+	var calls []string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		calls = append(calls, r.Method+" "+r.URL.Path)
+		mu.Unlock()
+		switch r.Method {
+		case "GET":
+			writePage(t, w, existing, 1, 1, len(existing))
+		case "POST":
+			writeData(t, w, map[string]interface{}{"id": "wh_new", "url": "https://example.com/new"})
+		case "PUT":
+			writeData(t, w, map[string]interface{}{"id": "wh_changed", "url": "https://example.com/changed"})
+		case "DELETE":
+			writeData(t, w, map[string]interface{}{"deleted": true})
+		}
+	})
+
+	report, err := client.Webhooks.Sync(context.Background(), desired)
+	if err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+	if len(report.Created) != 1 || report.Created[0].ID != "wh_new" {
+		t.Errorf("created = %+v, want wh_new", report.Created)
+	}
+	if len(report.Updated) != 1 || report.Updated[0].ID != "wh_changed" {
+		t.Errorf("updated = %+v, want wh_changed", report.Updated)
+	}
+	if strings.Join(report.Deleted, ",") != "wh_extra" {
+		t.Errorf("deleted = %v, want [wh_extra]", report.Deleted)
+	}
+	for _, call := range calls {
+		if strings.HasSuffix(call, "/wh_same") {
+			t.Errorf("unchanged webhook was modified: %s", call)
+		}
+	}
+}
+
+func TestWebhookSyncUpdatesDriftInPlace(t *testing.T) {
+	const hookURL = "https://example.com/hook"
+	existing := map[string]interface{}{
+		"id":          "wh_1",
+		"url":         hookURL,
+		"events":      []string{EventTrackUploaded},
+		"active":      true,
+		"headers":     map[string]string{"X-Team": "audio"},
+		"timeout":     10,
+		"retryPolicy": map[string]interface{}{"maxRetries": 5, "backoffMultiplier": 2, "maxBackoffDelay": 3600},
+	}
+	base := WebhookCreate{URL: hookURL, Events: []string{EventTrackUploaded}}
+
+	tests := []struct {
+		name       string
+		desired    func(d *WebhookCreate)
+		wantUpdate map[string]string
+	}{
+		{
+			name: "unchanged",
+			desired: func(d *WebhookCreate) {
+				d.Headers = map[string]string{"X-Team": "audio"}
+				d.Timeout = 10
+				d.RetryPolicy = DefaultRetryPolicy()
+				d.Active = true
+			},
+		},
+		{
+			name:    "unset fields keep current settings",
+			desired: func(d *WebhookCreate) {},
+		},
+		{
+			name: "headers differ",
+			desired: func(d *WebhookCreate) {
+				d.Headers = map[string]string{"X-Team": "distribution"}
+			},
+			wantUpdate: map[string]string{"headers": `{"X-Team":"distribution"}`},
+		},
+		{
+			name: "retry policy differs",
+			desired: func(d *WebhookCreate) {
+				d.RetryPolicy = &RetryPolicy{MaxRetries: 3, BackoffMultiplier: 2, MaxBackoffDelay: 600}
+			},
+			wantUpdate: map[string]string{"retryPolicy": `{"maxRetries":3,"backoffMultiplier":2,"maxBackoffDelay":600}`},
+		},
+		{
+			name: "timeout differs",
+			desired: func(d *WebhookCreate) {
+				d.Timeout = 30
+			},
+			wantUpdate: map[string]string{"timeout": "30"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var updates []map[string]string
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				switch r.Method {
+				case "GET":
+					writePage(t, w, []map[string]interface{}{existing}, 1, 1, 1)
+				case "PUT":
+					if r.URL.Path != "/v1/webhooks/wh_1" {
+						t.Errorf("path = %s", r.URL.Path)
+					}
+					updates = append(updates, requestFields(t, r))
+					writeData(t, w, existing)
+				default:
+					t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+				}
+			})
+
+			desired := base
+			tt.desired(&desired)
+			report, err := client.Webhooks.Sync(context.Background(), []WebhookCreate{desired})
+			if err != nil {
+				t.Fatalf("Sync: %v", err)
+			}
+			if len(report.Created) != 0 || len(report.Deleted) != 0 {
+				t.Errorf("report = %+v, want an update in place", report)
+			}
+
+			if tt.wantUpdate == nil {
+				if len(updates) != 0 || len(report.Updated) != 0 {
+					t.Errorf("updates = %v, want none", updates)
+				}
+				return
+			}
+			if len(updates) != 1 || len(report.Updated) != 1 {
+				t.Fatalf("updates = %v, want one", updates)
+			}
+			for field, want := range tt.wantUpdate {
+				if got := updates[0][field]; got != want {
+					t.Errorf("%s = %s, want %s", field, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestWebhookSyncRejectsDuplicateURLs(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+	})
+
+	_, err := client.Webhooks.Sync(context.Background(), []WebhookCreate{
+		{URL: "https://example.com/hook", Events: []string{EventTrackUploaded}},
+		{URL: "https://example.com/hook", Events: []string{EventTrackProcessed}},
+	})
+	if err == nil {
+		t.Fatal("expected an error for duplicate URLs")
+	}
+}
+
+func TestGetDeliveriesDateRange(t *testing.T) {
+	start := time.Date(2024, 5, 1, 8, 30, 0, 0, time.FixedZone("CEST", 2*60*60))
+	end := time.Date(2024, 5, 31, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		filter    DeliveryFilter
+		wantStart string
+		wantEnd   string
+		wantErr   bool
+	}{
+		{"typed times in UTC", DeliveryFilter{StartTime: start, EndTime: end}, "2024-05-01T06:30:00Z", "2024-05-31T00:00:00Z", false},
+		{"string dates", DeliveryFilter{StartDate: "2024-05-01", EndDate: "2024-05-31"}, "2024-05-01", "2024-05-31", false},
+		{"typed time takes precedence", DeliveryFilter{StartDate: "2024-01-01", StartTime: start}, "2024-05-01T06:30:00Z", "", false},
+		{"start after end", DeliveryFilter{StartTime: end, EndTime: start}, "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var query url.Values
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				query = r.URL.Query()
+				writePage(t, w, []interface{}{}, 1, 1, 0)
+			})
+
+			_, err := client.Webhooks.GetDeliveries(context.Background(), "wh_1", 1, 20, &tt.filter)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				if query != nil {
+					t.Error("request sent despite an invalid range")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetDeliveries: %v", err)
+			}
+			if got := query.Get("startDate"); got != tt.wantStart {
+				t.Errorf("startDate = %q, want %q", got, tt.wantStart)
+			}
+			if got := query.Get("endDate"); got != tt.wantEnd {
+				t.Errorf("endDate = %q, want %q", got, tt.wantEnd)
+			}
+		})
+	}
+}
+
+// probeServer is a mock API server that handles webhook probes by sending a
+// signed test event to the requested URL
+func probeServer(t *testing.T) *Client {
+	return newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/v1/webhooks/probe" {
+			t.Errorf("request = %s %s", r.Method, r.URL.Path)
+		}
+		var probe struct {
+			URL       string `json:"url"`
+			Secret    string `json:"secret"`
+			EventType string `json:"eventType"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&probe); err != nil {
+			t.Errorf("decoding body: %v", err)
+		}
+
+		payload := `{"id":"evt_probe","type":"` + probe.EventType + `"}`
+		req, err := http.NewRequest(http.MethodPost, probe.URL, strings.NewReader(payload))
+		if err != nil {
+			t.Errorf("building probe: %v", err)
+			return
+		}
+		req.Header.Set(SignatureHeader, CreateSignature([]byte(payload), probe.Secret, nil))
+		start := time.Now()
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			writeData(t, w, map[string]interface{}{"reachable": false, "latencyMs": 0, "error": "connection refused"})
+			return
+		}
+		resp.Body.Close()
+		writeData(t, w, map[string]interface{}{
+			"reachable":  true,
+			"statusCode": resp.StatusCode,
+			"latencyMs":  time.Since(start).Milliseconds(),
+		})
+	})
+}
+
+func TestWebhookProbe(t *testing.T) {
+	const secret = "whsec_probe"
+
+	t.Run("reachable", func(t *testing.T) {
+		var verified bool
+		endpoint := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			payload, _ := io.ReadAll(r.Body)
+			verified = VerifySignature(payload, r.Header.Get(SignatureHeader), secret, 300)
+			if !strings.Contains(string(payload), EventWebhookTest) {
+				t.Errorf("payload = %s, want a %s event", payload, EventWebhookTest)
+			}
+		}))
+		defer endpoint.Close()
+
+		result, err := probeServer(t).Webhooks.Probe(context.Background(), endpoint.URL, secret)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.Reachable || result.StatusCode != http.StatusOK || result.LatencyMs < 0 || result.Error != "" {
+			t.Errorf("result = %+v, want a reachable endpoint", result)
+		}
+		if !verified {
+			t.Error("endpoint could not verify the probe signature")
+		}
+	})
+
+	t.Run("unreachable", func(t *testing.T) {
+		endpoint := httptest.NewServer(http.NotFoundHandler())
+		endpoint.Close()
+
+		result, err := probeServer(t).Webhooks.Probe(context.Background(), endpoint.URL, secret)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Reachable || result.StatusCode != 0 || result.Error == "" {
+			t.Errorf("result = %+v, want an unreachable endpoint", result)
+		}
+	})
+
+	t.Run("missing URL", func(t *testing.T) {
+		client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		})
+		if _, err := client.Webhooks.Probe(context.Background(), "", secret); err == nil {
+			t.Error("probe without a URL succeeded")
+		}
+	})
+}
+
+func TestFailedDeliveries(t *testing.T) {
+	const pageSize = 2
+	created := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	retryAt := created.Add(time.Hour)
+	deliveries := []map[string]interface{}{
+		{"id": "dlv_1", "webhookId": "wh_1", "eventType": EventTrackUploaded, "status": "failed", "statusCode": 500, "attempts": 3, "error": "Internal Server Error", "createdAt": created},
+		{"id": "dlv_2", "webhookId": "wh_1", "eventType": EventAnalysisCompleted, "status": "failed", "statusCode": 404, "attempts": 1, "createdAt": created},
+		{"id": "dlv_3", "webhookId": "wh_1", "eventType": EventTrackUploaded, "status": "failed", "attempts": 2, "error": "timeout", "createdAt": created, "nextRetryAt": retryAt},
+		{"id": "dlv_4", "webhookId": "wh_1", "eventType": EventDistributionLive, "status": "failed", "statusCode": 502, "attempts": 5, "createdAt": created},
+		{"id": "dlv_5", "webhookId": "wh_1", "eventType": EventTrackFailed, "status": "failed", "statusCode": 503, "attempts": 1, "createdAt": created},
+	}
+
+	tests := []struct {
+		name          string
+		since         time.Time
+		wantStartDate string
+	}{
+		{name: "all failures"},
+		{name: "since a time", since: time.Date(2024, 3, 1, 9, 0, 0, 0, time.FixedZone("CET", 3600)), wantStartDate: "2024-03-01T08:00:00Z"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var pages []int
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/v1/webhooks/wh_1/deliveries" {
+					t.Errorf("path = %s", r.URL.Path)
+				}
+				query := r.URL.Query()
+				if query.Get("status") != "failed" || query.Get("startDate") != tt.wantStartDate {
+					t.Errorf("query = %v, want failed deliveries since %q", query, tt.wantStartDate)
+				}
+				page, _ := strconv.Atoi(query.Get("page"))
+				pages = append(pages, page)
+
+				start := min((page-1)*pageSize, len(deliveries))
+				end := min(start+pageSize, len(deliveries))
+				totalPages := (len(deliveries) + pageSize - 1) / pageSize
+				writePage(t, w, deliveries[start:end], page, totalPages, len(deliveries))
+			})
+
+			var got []WebhookDelivery
+			it := client.Webhooks.FailedDeliveries("wh_1", tt.since)
+			for it.Next(context.Background()) {
+				got = append(got, it.Value())
+			}
+			if err := it.Err(); err != nil {
+				t.Fatalf("iterating: %v", err)
+			}
+
+			if want := []int{1, 2, 3}; !reflect.DeepEqual(pages, want) {
+				t.Errorf("pages requested = %v, want %v", pages, want)
+			}
+			if len(got) != len(deliveries) {
+				t.Fatalf("deliveries = %d, want %d", len(got), len(deliveries))
+			}
+			for i, delivery := range got {
+				if want := fmt.Sprintf("dlv_%d", i+1); delivery.ID != want || delivery.Status != "failed" || !delivery.CreatedAt.Equal(created) {
+					t.Errorf("delivery %d = %+v, want failed delivery %s", i, delivery, want)
+				}
+			}
+			if d := got[0]; d.StatusCode != 500 || d.Attempts != 3 || d.Error != "Internal Server Error" || d.EventType != EventTrackUploaded {
+				t.Errorf("first delivery = %+v", d)
+			}
+			if d := got[2]; d.NextRetryAt == nil || !d.NextRetryAt.Equal(retryAt) {
+				t.Errorf("third delivery retry = %v, want %v", d.NextRetryAt, retryAt)
+			}
+		})
+	}
+}